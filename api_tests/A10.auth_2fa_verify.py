@@ -0,0 +1,22 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL
+
+print("--- VERIFY TWO-FACTOR LOGIN ---")
+
+mock_mfa_token = "PUT_MFA_TOKEN_FROM_LOGIN_HERE"
+mock_code = "PUT_AUTHENTICATOR_OR_RECOVERY_CODE_HERE"
+
+url = f"{BASE_URL}/auth/2fa/verify"
+body = {
+    "mfaToken": mock_mfa_token,
+    "code": mock_code
+}
+
+response = send_and_print(
+    url=url,
+    body=body,
+    method="POST",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}.json"
+)
@@ -0,0 +1,18 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL
+
+print("--- REQUEST MAGIC LINK ---")
+
+url = f"{BASE_URL}/auth/magic-link"
+body = {
+    "email": "test@example.com"
+}
+
+response = send_and_print(
+    url=url,
+    body=body,
+    method="POST",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}.json"
+)
@@ -0,0 +1,29 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- CHANGE PASSWORD ---")
+
+token = load_config("accessToken")
+
+if not token:
+    print("Error: No access token. Run A2.auth_login.py first.")
+    sys.exit(1)
+
+url = f"{BASE_URL}/auth/change-password"
+headers = {
+    "Authorization": f"Bearer {token}"
+}
+body = {
+    "currentPassword": "password123",
+    "newPassword": "newpassword123"
+}
+
+response = send_and_print(
+    url=url,
+    body=body,
+    headers=headers,
+    method="POST",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}.json"
+)
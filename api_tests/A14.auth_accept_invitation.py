@@ -0,0 +1,19 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL
+
+print("--- ACCEPT INVITATION ---")
+
+mock_token = "PUT_VALID_TOKEN_HERE_FROM_EMAIL"
+
+response = send_and_print(
+    url=f"{BASE_URL}/auth/accept-invitation",
+    body={
+        "token": mock_token,
+        "name": "Invited User",
+        "password": "invitedPassword123"
+    },
+    method="POST",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}.json"
+)
@@ -0,0 +1,16 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL
+
+print("--- VERIFY EMAIL ---")
+
+mock_token = "PUT_VALID_TOKEN_HERE_FROM_EMAIL"
+
+url = f"{BASE_URL}/auth/verify-email?token={mock_token}"
+
+response = send_and_print(
+    url=url,
+    method="POST",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}.json"
+)
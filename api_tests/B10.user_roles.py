@@ -0,0 +1,58 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- LIST PERMISSIONS ---")
+
+token = load_config("accessToken")
+target_id = load_config("target_user_id")
+
+if not token or not target_id:
+    print("Error: Missing token or target_user_id. Run A2 and B1 first.")
+    sys.exit(1)
+
+headers = {
+    "Authorization": f"Bearer {token}"
+}
+name = os.path.splitext(os.path.basename(__file__))[0]
+
+send_and_print(
+    url=f"{BASE_URL}/permissions",
+    headers=headers,
+    method="GET",
+    output_file=f"{name}_permissions.json"
+)
+
+print("--- CREATE ROLE ---")
+
+send_and_print(
+    url=f"{BASE_URL}/roles",
+    body={
+        "name": "support",
+        "description": "Help desk, reads accounts and sessions",
+        "permissions": ["users:read", "sessions:read"]
+    },
+    headers=headers,
+    method="POST",
+    output_file=f"{name}.json"
+)
+
+print("--- ASSIGN ROLE ---")
+
+send_and_print(
+    url=f"{BASE_URL}/users/{target_id}/role",
+    body={"role": "support"},
+    headers=headers,
+    method="PUT",
+    output_file=f"{name}_assign.json"
+)
+
+print("--- LIST ROLES ---")
+
+send_and_print(
+    url=f"{BASE_URL}/roles",
+    headers=headers,
+    method="GET",
+    output_file=f"{name}_list.json"
+)
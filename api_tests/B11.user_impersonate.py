@@ -0,0 +1,47 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- IMPERSONATE USER ---")
+
+token = load_config("accessToken")
+target_id = load_config("target_user_id")
+
+if not token or not target_id:
+    print("Error: Missing token or target_user_id. Run A2 and B1 first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+
+response = send_and_print(
+    url=f"{BASE_URL}/users/{target_id}/impersonate",
+    headers={"Authorization": f"Bearer {token}"},
+    method="POST",
+    output_file=f"{name}.json"
+)
+
+if response.status_code != 200:
+    print(">>> Impersonation Failed.")
+    sys.exit(1)
+
+impersonation_token = response.json()['data']['tokens']['access']['token']
+
+print("--- ACT AS THE USER ---")
+
+send_and_print(
+    url=f"{BASE_URL}/users/{target_id}",
+    headers={"Authorization": f"Bearer {impersonation_token}"},
+    method="GET",
+    output_file=f"{name}_get.json"
+)
+
+print("--- PASSWORD CHANGE IS BLOCKED (expect 403) ---")
+
+send_and_print(
+    url=f"{BASE_URL}/auth/change-password",
+    headers={"Authorization": f"Bearer {impersonation_token}"},
+    body={"currentPassword": "x", "newPassword": "y"},
+    method="POST",
+    output_file=f"{name}_change_password.json"
+)
@@ -0,0 +1,56 @@
+import sys
+import os
+import time
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config, save_config
+
+print("--- INVITE USER ---")
+
+token = load_config("accessToken")
+
+if not token:
+    print("Error: No access token found. Run A2 (login as admin) first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+headers = {"Authorization": f"Bearer {token}"}
+
+response = send_and_print(
+    url=f"{BASE_URL}/users/invitations",
+    headers=headers,
+    body={
+        "email": f"invited_{int(time.time())}@example.com",
+        "name": "Invited User",
+        "role": "user"
+    },
+    method="POST",
+    output_file=f"{name}.json"
+)
+
+if response.status_code != 201:
+    print(">>> Invitation Failed.")
+    sys.exit(1)
+
+invitation_id = response.json()['data']['id']
+save_config("invitation_id", invitation_id)
+
+print("--- LIST INVITATIONS ---")
+
+send_and_print(
+    url=f"{BASE_URL}/users/invitations",
+    headers=headers,
+    method="GET",
+    output_file=f"{name}_list.json"
+)
+
+print("--- RESEND INVITATION ---")
+
+send_and_print(
+    url=f"{BASE_URL}/users/invitations/{invitation_id}/resend",
+    headers=headers,
+    method="POST",
+    output_file=f"{name}_resend.json"
+)
+
+print("The invitee accepts with the emailed token, see A14. Revoke with:")
+print(f"DELETE {BASE_URL}/users/invitations/{invitation_id}")
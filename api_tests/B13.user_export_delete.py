@@ -0,0 +1,47 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- EXPORT MY DATA ---")
+
+token = load_config("accessToken")
+
+if not token:
+    print("Error: No access token found. Run A2 (login) first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+headers = {"Authorization": f"Bearer {token}"}
+
+response = send_and_print(
+    url=f"{BASE_URL}/users/me/export",
+    headers=headers,
+    method="GET",
+    output_file=f"{name}_export.json"
+)
+
+if response.status_code != 200:
+    print(">>> Export Failed.")
+    sys.exit(1)
+
+print(f"Content-Disposition: {response.headers.get('Content-Disposition')}")
+
+if "--delete" not in sys.argv:
+    print("Run again with --delete to schedule this account for deletion (signs out every session).")
+    sys.exit(0)
+
+print("--- SCHEDULE ACCOUNT DELETION ---")
+
+password = "password123"  # same credentials as A2
+
+response = send_and_print(
+    url=f"{BASE_URL}/users/me",
+    headers=headers,
+    body={"password": password},
+    method="DELETE",
+    output_file=f"{name}_delete.json"
+)
+
+if response.status_code == 202:
+    print(">>> Deletion scheduled. Log in again (A2) before the date to cancel it.")
@@ -0,0 +1,75 @@
+import sys
+import os
+import time
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- GET REGISTRATION POLICY ---")
+
+token = load_config("accessToken")
+
+if not token:
+    print("Error: No access token found. Run A2 (login as admin) first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+headers = {"Authorization": f"Bearer {token}"}
+
+response = send_and_print(
+    url=f"{BASE_URL}/settings/registration",
+    headers=headers,
+    method="GET",
+    output_file=f"{name}.json"
+)
+
+if response.status_code != 200:
+    print(">>> Get Policy Failed.")
+    sys.exit(1)
+
+previous = response.json()['data']
+
+print("--- RESTRICT REGISTRATION TO example.com ---")
+
+send_and_print(
+    url=f"{BASE_URL}/settings/registration",
+    headers=headers,
+    body={
+        "mode": "restricted",
+        "allowed_domains": ["example.com"],
+        "denied_domains": []
+    },
+    method="PUT",
+    output_file=f"{name}_update.json"
+)
+
+print("--- REGISTER FROM ANOTHER DOMAIN (expect 403 email_domain_not_allowed) ---")
+
+response = send_and_print(
+    url=f"{BASE_URL}/auth/register",
+    body={
+        "name": "Outsider",
+        "email": f"outsider_{int(time.time())}@example.org",
+        "password": "password123"
+    },
+    method="POST",
+    output_file=f"{name}_register.json"
+)
+
+if response.status_code == 403:
+    print(f">>> Refused as expected: {response.json()['data']['code']}")
+else:
+    print(">>> Expected the sign-up to be refused.")
+
+print("--- RESTORE PREVIOUS POLICY ---")
+
+send_and_print(
+    url=f"{BASE_URL}/settings/registration",
+    headers=headers,
+    body={
+        "mode": previous['mode'],
+        "allowed_domains": previous['allowed_domains'],
+        "denied_domains": previous['denied_domains']
+    },
+    method="PUT",
+    output_file=f"{name}_restore.json"
+)
@@ -0,0 +1,39 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- CREATE PERSONAL ACCESS TOKEN ---")
+
+token = load_config("accessToken")
+
+if not token:
+    print("Error: No access token. Run A2.auth_login.py first.")
+    sys.exit(1)
+
+url = f"{BASE_URL}/users/me/tokens"
+headers = {
+    "Authorization": f"Bearer {token}"
+}
+body = {
+    "name": "ci-pipeline",
+    "scopes": ["users:read", "sessions:read"],
+    "expires_in_days": 30
+}
+
+response = send_and_print(
+    url=url,
+    body=body,
+    headers=headers,
+    method="POST",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}.json"
+)
+
+print("--- LIST PERSONAL ACCESS TOKENS ---")
+
+response = send_and_print(
+    url=url,
+    headers=headers,
+    method="GET",
+    output_file=f"{os.path.splitext(os.path.basename(__file__))[0]}_list.json"
+)
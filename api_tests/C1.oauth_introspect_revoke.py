@@ -0,0 +1,47 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+# Service client credentials: a row of the clients table, secret = its bearer_key.
+# Revoking a user token needs the tokens:revoke scope on the client.
+client_id = load_config("oauth_client_id")
+client_secret = load_config("oauth_client_secret")
+token = load_config("accessToken")
+
+if not client_id or not client_secret or not token:
+    print("Error: Set oauth_client_id / oauth_client_secret in secrets.json and run A2.auth_login.py first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+credentials = {
+    "client_id": str(client_id),
+    "client_secret": client_secret
+}
+
+print("--- INTROSPECT ACCESS TOKEN ---")
+
+send_and_print(
+    url=f"{BASE_URL}/oauth/introspect",
+    body={"token": token, "token_type_hint": "access_token", **credentials},
+    method="POST",
+    output_file=f"{name}.json"
+)
+
+print("--- REVOKE ACCESS TOKEN ---")
+
+send_and_print(
+    url=f"{BASE_URL}/oauth/revoke",
+    body={"token": token, **credentials},
+    method="POST",
+    output_file=f"{name}_revoke.json"
+)
+
+print("--- INTROSPECT AGAIN (expect active: false) ---")
+
+send_and_print(
+    url=f"{BASE_URL}/oauth/introspect",
+    body={"token": token, **credentials},
+    method="POST",
+    output_file=f"{name}_after.json"
+)
@@ -0,0 +1,48 @@
+import sys
+import os
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config, save_config
+
+print("--- CLIENT CREDENTIALS GRANT ---")
+
+# Service client credentials: a row of the clients table, secret = its bearer_key
+client_id = load_config("oauth_client_id")
+client_secret = load_config("oauth_client_secret")
+target_id = load_config("target_user_id")
+
+if not client_id or not client_secret:
+    print("Error: Set oauth_client_id / oauth_client_secret in secrets.json first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+
+response = send_and_print(
+    url=f"{BASE_URL}/oauth/token",
+    body={
+        "grant_type": "client_credentials",
+        "scope": "users:read",
+        "client_id": str(client_id),
+        "client_secret": client_secret
+    },
+    method="POST",
+    output_file=f"{name}.json"
+)
+
+if response.status_code != 200:
+    print(">>> Token request Failed.")
+    sys.exit(1)
+
+client_token = response.json()['access_token']
+save_config("clientAccessToken", client_token)
+
+if not target_id:
+    sys.exit(0)
+
+print("--- MACHINE-TO-MACHINE USER LOOKUP ---")
+
+send_and_print(
+    url=f"{BASE_URL}/m2m/users/{target_id}",
+    headers={"Authorization": f"Bearer {client_token}"},
+    method="GET",
+    output_file=f"{name}_user.json"
+)
@@ -0,0 +1,84 @@
+import hashlib
+import hmac
+import json
+import sys
+import os
+import time
+import uuid
+sys.path.append(os.path.abspath(os.path.dirname(__file__)))
+from utils import send_and_print, BASE_URL, load_config
+
+print("--- SIGNED INTERNAL REQUEST ---")
+
+# Same value as INTERNAL_KEY in the server's .env
+internal_key = load_config("internal_key")
+
+if not internal_key:
+    print("Error: Set internal_key in secrets.json first.")
+    sys.exit(1)
+
+name = os.path.splitext(os.path.basename(__file__))[0]
+
+
+def signed_headers(method, path, body, timestamp=None, nonce=None):
+    """Mirrors utils/signature: method, path, body hash, timestamp and nonce joined by newlines."""
+    timestamp = timestamp or str(int(time.time()))
+    nonce = nonce or uuid.uuid4().hex
+    string_to_sign = "\n".join([
+        method.upper(),
+        path,
+        hashlib.sha256(body.encode()).hexdigest(),
+        timestamp,
+        nonce
+    ])
+    signature = hmac.new(internal_key.encode(), string_to_sign.encode(), hashlib.sha256).hexdigest()
+    return {
+        "Authorization": f"HMAC-SHA256 {signature}",
+        "X-Signature-Timestamp": timestamp,
+        "X-Signature-Nonce": nonce
+    }
+
+
+path = "/v1/internal/clients/find"
+body = json.dumps({})
+headers = signed_headers("POST", path, body)
+
+response = send_and_print(
+    url=f"{BASE_URL}/internal/clients/find",
+    headers=headers,
+    body=body,
+    method="POST",
+    output_file=f"{name}.json"
+)
+
+if response.status_code != 200:
+    print(">>> Signed request Failed.")
+    sys.exit(1)
+
+print("\n--- REPLAYING THE SAME REQUEST (expect 401) ---")
+replay = send_and_print(
+    url=f"{BASE_URL}/internal/clients/find",
+    headers=headers,
+    body=body,
+    method="POST",
+    output_file=f"{name}.json",
+    write_mode="a"
+)
+
+if replay.status_code != 401:
+    print(">>> Replay was not rejected.")
+    sys.exit(1)
+
+print("\n--- STALE TIMESTAMP (expect 401) ---")
+stale = send_and_print(
+    url=f"{BASE_URL}/internal/clients/find",
+    headers=signed_headers("POST", path, body, timestamp=str(int(time.time()) - 3600)),
+    body=body,
+    method="POST",
+    output_file=f"{name}.json",
+    write_mode="a"
+)
+
+if stale.status_code != 401:
+    print(">>> Stale request was not rejected.")
+    sys.exit(1)
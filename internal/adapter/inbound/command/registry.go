@@ -20,3 +20,11 @@ func NewAdapter(
 func (s *adapter) Client() inbound_port.ClientCommandPort {
 	return NewClientAdapter(s.domain)
 }
+
+func (s *adapter) User() inbound_port.UserCommandPort {
+	return NewUserAdapter(s.domain)
+}
+
+func (s *adapter) Token() inbound_port.TokenCommandPort {
+	return NewTokenAdapter(s.domain)
+}
@@ -20,6 +20,16 @@ func InitRoute(
 		default:
 			log.WithContext(ctx).Info("command not found")
 		}
+	} else if len(args) > 1 {
+		// Commands that take no value
+		switch args[1] {
+		case "purge_deleted_users":
+			port.User().PurgeScheduledDeletions()
+		case "purge_tokens":
+			port.Token().Purge()
+		default:
+			log.WithContext(ctx).Info("command not found")
+		}
 	} else {
 		log.WithContext(ctx).Info("command not found")
 	}
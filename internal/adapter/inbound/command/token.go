@@ -0,0 +1,30 @@
+package command_inbound_adapter
+
+import (
+	"prabogo/internal/domain"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/activity"
+	"prabogo/utils/log"
+)
+
+type tokenAdapter struct {
+	domain domain.Domain
+}
+
+func NewTokenAdapter(
+	domain domain.Domain,
+) inbound_port.TokenCommandPort {
+	return &tokenAdapter{
+		domain: domain,
+	}
+}
+
+func (h *tokenAdapter) Purge() {
+	ctx := activity.NewContext("command_purge_tokens")
+	purged, err := h.domain.Auth().PurgeTokens(ctx)
+	if err != nil {
+		log.WithContext(ctx).Errorf("purge tokens error after %d rows: %s", purged, err.Error())
+		return
+	}
+	log.WithContext(ctx).Infof("purge tokens success: %d rows deleted", purged)
+}
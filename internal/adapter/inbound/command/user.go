@@ -0,0 +1,33 @@
+package command_inbound_adapter
+
+import (
+	"prabogo/internal/domain"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/activity"
+	"prabogo/utils/log"
+)
+
+// purgeBatchSize bounds how many accounts are loaded per query
+const purgeBatchSize = 100
+
+type userAdapter struct {
+	domain domain.Domain
+}
+
+func NewUserAdapter(
+	domain domain.Domain,
+) inbound_port.UserCommandPort {
+	return &userAdapter{
+		domain: domain,
+	}
+}
+
+func (h *userAdapter) PurgeScheduledDeletions() {
+	ctx := activity.NewContext("command_purge_deleted_users")
+	purged, err := h.domain.User().PurgeScheduledDeletions(ctx, purgeBatchSize)
+	if err != nil {
+		log.WithContext(ctx).Errorf("purge deleted users error after %d accounts: %s", purged, err.Error())
+		return
+	}
+	log.WithContext(ctx).Infof("purge deleted users success: %d accounts deleted", purged)
+}
@@ -0,0 +1,88 @@
+package fiber_inbound_adapter
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/activity"
+)
+
+type accessTokenAdapter struct {
+	domain domain.Domain
+}
+
+func NewAccessTokenAdapter(domain domain.Domain) inbound_port.AccessTokenHttpPort {
+	return &accessTokenAdapter{domain: domain}
+}
+
+// accessTokenAuth signs the request in as the owner of a personal access token. The token's
+// scopes are left in locals for RequireScope, and in the context for domain checks.
+func (m *middlewareAdapter) accessTokenAuth(c *fiber.Ctx, tokenString string) error {
+	token, err := m.domain.AccessToken().Authenticate(c.Context(), tokenString)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Invalid or expired token"})
+	}
+
+	c.Locals("userID", token.UserID)
+	c.Locals("sessionID", "")
+	c.Locals("tokenScopes", token.Scopes)
+	c.Context().SetUserValue(activity.TokenScopes, token.Scopes)
+
+	return c.Next()
+}
+
+func (h *accessTokenAdapter) Create(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	var req model.PersonalAccessTokenInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	token, raw, err := h.domain.AccessToken().Create(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.Response{
+		Success: true,
+		Message: "Copy the token now, it is not shown again",
+		Data: fiber.Map{
+			"token":        raw,
+			"access_token": token,
+		},
+	})
+}
+
+func (h *accessTokenAdapter) GetList(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	tokens, err := h.domain.AccessToken().List(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: tokens})
+}
+
+func (h *accessTokenAdapter) Revoke(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	if err := h.domain.AccessToken().Revoke(c.Context(), userID, c.Params("tokenId")); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true})
+}
@@ -0,0 +1,98 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/password"
+)
+
+func TestAccessTokenAuth(t *testing.T) {
+	Convey("Test Personal Access Token Auth", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		user := &model.User{ID: "user-1", Email: "ci@example.com", Role: "user", IsEmailVerified: true}
+		raw := model.PersonalAccessTokenPrefix + "0123456789abcdef"
+		recent := time.Now()
+		stored := &model.PersonalAccessToken{
+			ID:         "token-1",
+			UserID:     user.ID,
+			Scopes:     []string{model.ScopeUsersRead},
+			ExpiresAt:  time.Now().Add(time.Hour),
+			LastUsedAt: &recent,
+		}
+
+		request := func(method, path string) *http.Response {
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+raw)
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			return resp
+		}
+
+		Convey("Unknown token", func() {
+			mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(nil, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/users/user-1")
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Scope granted", func() {
+			mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/users/user-1")
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Scope missing", func() {
+			mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+
+			resp := request(http.MethodDelete, "/v1/users/user-1/sessions")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Token management needs a session", func() {
+			mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+
+			resp := request(http.MethodPost, "/v1/users/me/tokens")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Account security routes need a session", func() {
+			mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+
+			resp := request(http.MethodPost, "/v1/auth/change-password")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
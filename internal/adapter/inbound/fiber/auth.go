@@ -1,10 +1,18 @@
 package fiber_inbound_adapter
 
 import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
 	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/activity"
+	"prabogo/utils/log"
+	"prabogo/utils/password"
 )
 
 type authAdapter struct {
@@ -15,6 +23,27 @@ func NewAuthAdapter(domain domain.Domain) inbound_port.AuthHttpPort {
 	return &authAdapter{domain: domain}
 }
 
+// deviceContext carries the caller's device metadata down to the session being issued
+func deviceContext(c *fiber.Ctx) context.Context {
+	ctx := activity.WithIPAddress(c.Context(), c.IP())
+	return activity.WithUserAgent(ctx, c.Get(fiber.HeaderUserAgent))
+}
+
+// badRequest answers 400, listing the failed rules when a password was rejected by the policy.
+// A sign-up refused by the registration policy is a 403 carrying its error code. Domains return
+// both errors unwrapped, as they do LoginThrottledError for signInFailed.
+func badRequest(c *fiber.Ctx, err error) error {
+	var policy *password.PolicyError
+	if errors.As(err, &policy) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: policy.Error(), Data: policy})
+	}
+	var refused *model.RegistrationError
+	if errors.As(err, &refused) {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: refused.Error(), Data: refused})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+}
+
 func (h *authAdapter) Register(a any) error {
 	c := a.(*fiber.Ctx)
 	var req model.UserInput
@@ -22,9 +51,9 @@ func (h *authAdapter) Register(a any) error {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
 	}
 
-	user, tokens, err := h.domain.Auth().Register(c.Context(), req)
+	user, tokens, err := h.domain.Auth().Register(deviceContext(c), req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+		return badRequest(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -43,9 +72,17 @@ func (h *authAdapter) Login(a any) error {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
 	}
 
-	user, tokens, err := h.domain.Auth().Login(c.Context(), req.Email, req.Password)
+	user, tokens, err := h.domain.Auth().Login(deviceContext(c), req.Email, req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: err.Error()})
+		return signInFailed(c, err)
+	}
+
+	// Second factor still owed: no user data until it is verified
+	if _, ok := tokens["mfa"]; ok {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"mfa_required": true,
+			"tokens":       tokens,
+		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -54,6 +91,16 @@ func (h *authAdapter) Login(a any) error {
 	})
 }
 
+// signInFailed answers 429 with Retry-After while the lockout holds, 401 otherwise
+func signInFailed(c *fiber.Ctx, err error) error {
+	var throttled *model.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(throttled.RetrySeconds()))
+		return c.Status(fiber.StatusTooManyRequests).JSON(model.Response{Success: false, Error: throttled.Error()})
+	}
+	return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: err.Error()})
+}
+
 func (h *authAdapter) RefreshToken(a any) error {
 	c := a.(*fiber.Ctx)
 	var req struct {
@@ -63,7 +110,7 @@ func (h *authAdapter) RefreshToken(a any) error {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
 	}
 
-	tokens, err := h.domain.Auth().RefreshToken(c.Context(), req.RefreshToken)
+	tokens, err := h.domain.Auth().RefreshToken(deviceContext(c), req.RefreshToken)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: err.Error()})
 	}
@@ -80,6 +127,16 @@ func (h *authAdapter) Logout(a any) error {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
 	}
 
+	// The access token, when sent along, stops working right away. Revoked before the session
+	// goes, so a failure leaves the refresh token in place for the client to retry with
+	parts := strings.Split(c.Get("Authorization"), " ")
+	if len(parts) == 2 && parts[0] == "Bearer" {
+		if err := h.domain.Auth().RevokeAccessToken(c.Context(), parts[1]); err != nil {
+			log.WithContext(c.Context()).Error(err)
+			return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: "Internal Server Error"})
+		}
+	}
+
 	if err := h.domain.Auth().Logout(c.Context(), req.RefreshToken); err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
 	}
@@ -111,8 +168,218 @@ func (h *authAdapter) ResetPassword(a any) error {
 	}
 
 	if err := h.domain.Auth().ResetPassword(c.Context(), token, req.Password); err != nil {
+		return badRequest(c, err)
+	}
+
+	return c.JSON(model.Response{Success: true})
+}
+
+func (h *authAdapter) ChangePassword(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+	sessionID, _ := c.Locals("sessionID").(string)
+
+	var req struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	if err := h.domain.Auth().ChangePassword(c.Context(), userID, sessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		return badRequest(c, err)
+	}
+
+	return c.JSON(model.Response{Success: true, Message: "Password changed, other sessions signed out"})
+}
+
+func (h *authAdapter) SendVerificationEmail(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	if err := h.domain.Auth().SendVerificationEmail(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.JSON(model.Response{Success: true, Message: "Verification email sent"})
+}
+
+func (h *authAdapter) VerifyEmail(a any) error {
+	c := a.(*fiber.Ctx)
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Missing token"})
+	}
+
+	if err := h.domain.Auth().VerifyEmail(c.Context(), token); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.JSON(model.Response{Success: true})
+}
+
+func (h *authAdapter) RequestMagicLink(a any) error {
+	c := a.(*fiber.Ctx)
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	h.domain.Auth().RequestMagicLink(c.Context(), req.Email)
+	return c.JSON(model.Response{Success: true, Message: "If email exists, sign in link sent"})
+}
+
+func (h *authAdapter) VerifyMagicLink(a any) error {
+	c := a.(*fiber.Ctx)
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Missing token"})
+	}
+
+	user, tokens, err := h.domain.Auth().VerifyMagicLink(deviceContext(c), token)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	// Second factor still owed: no user data until it is verified
+	if _, ok := tokens["mfa"]; ok {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"mfa_required": true,
+			"tokens":       tokens,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"user":   user,
+		"tokens": tokens,
+	})
+}
+
+// AcceptInvitation lets an invitee pick their name and password, then signs them in
+func (h *authAdapter) AcceptInvitation(a any) error {
+	c := a.(*fiber.Ctx)
+	var req model.AcceptInvitationInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+	if req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Missing token"})
+	}
+
+	user, tokens, err := h.domain.Auth().AcceptInvitation(deviceContext(c), req)
+	if err != nil {
+		return badRequest(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"user":   user,
+		"tokens": tokens,
+	})
+}
+
+func (h *authAdapter) EnrollTwoFactor(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	enrollment, err := h.domain.Auth().EnrollTwoFactor(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.JSON(model.Response{Success: true, Data: enrollment})
+}
+
+func (h *authAdapter) ConfirmTwoFactor(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	codes, err := h.domain.Auth().ConfirmTwoFactor(c.Context(), userID, req.Code)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.JSON(model.Response{Success: true, Data: fiber.Map{"recoveryCodes": codes}})
+}
+
+func (h *authAdapter) DisableTwoFactor(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	if err := h.domain.Auth().DisableTwoFactor(c.Context(), userID, req.Code); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
 	}
 
 	return c.JSON(model.Response{Success: true})
+}
+
+func (h *authAdapter) RegenerateRecoveryCodes(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	codes, err := h.domain.Auth().RegenerateRecoveryCodes(c.Context(), userID, req.Code)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.JSON(model.Response{Success: true, Data: fiber.Map{"recoveryCodes": codes}})
+}
+
+func (h *authAdapter) VerifyTwoFactor(a any) error {
+	c := a.(*fiber.Ctx)
+	var req struct {
+		MfaToken string `json:"mfaToken"`
+		Code     string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	user, tokens, err := h.domain.Auth().VerifyTwoFactorLogin(deviceContext(c), req.MfaToken, req.Code)
+	if err != nil {
+		return signInFailed(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"user":   user,
+		"tokens": tokens,
+	})
 }
\ No newline at end of file
@@ -35,7 +35,7 @@ func TestClientAdapter(t *testing.T) {
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mock_outbound_port.NewMockClientMessagePort(mockCtrl)).AnyTimes()
 
-		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort)
+		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mock_outbound_port.NewMockEmailPort(mockCtrl), mock_outbound_port.NewMockDirectoryPort(mockCtrl))
 		adapter := fiber_inbound_adapter.NewAdapter(dom)
 
 		app := fiber.New()
@@ -73,6 +73,7 @@ func TestClientAdapter(t *testing.T) {
 			Convey("Success", func() {
 				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
 				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().Delete("test-bearer-key").Return(nil).Times(1)
 
 				body, _ := json.Marshal(inputs)
 				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
@@ -158,7 +159,9 @@ func TestClientAdapter(t *testing.T) {
 
 		Convey("Delete", func() {
 			Convey("Success", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(filter, false).Return(outputs, nil).Times(1)
 				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete("test-bearer-key").Return(nil).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-delete", bytes.NewReader(body))
@@ -186,6 +189,7 @@ func TestClientAdapter(t *testing.T) {
 			})
 
 			Convey("Domain error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(filter, false).Return(outputs, nil).Times(1)
 				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(errors.New("error")).Times(1)
 
 				body, _ := json.Marshal(filter)
@@ -0,0 +1,112 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+)
+
+func TestImpersonation(t *testing.T) {
+	Convey("Test Impersonated Requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		t.Setenv("JWT_SECRET", "test-secret")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+		mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), "").Return(false, nil).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mockCachePort,
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		user := &model.User{ID: uuid.New().String(), Role: model.RoleUser, IsEmailVerified: true}
+		actor := &model.User{ID: uuid.New().String(), Role: "support", IsEmailVerified: true}
+		token, _, err := jwt.GenerateImpersonationToken(user.ID, actor.ID)
+		So(err, ShouldBeNil)
+
+		// Every request checks the token is still stored and the actor may still impersonate
+		stored := &model.Token{ID: 1, Token: token, UserID: user.ID, Type: model.TokenTypeImpersonation}
+		allowed := true
+		mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeImpersonation).DoAndReturn(func(string, string) (*model.Token, error) {
+			return stored, nil
+		}).AnyTimes()
+		mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(actor, nil).AnyTimes()
+		mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersImpersonate).DoAndReturn(func(string, string) (bool, error) {
+			return allowed, nil
+		}).AnyTimes()
+		mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support", Permissions: []string{model.PermissionUsersImpersonate}}, nil).AnyTimes()
+		mockRoleDatabasePort.EXPECT().FindByName(model.RoleUser).Return(&model.Role{Name: model.RoleUser, Permissions: []string{}}, nil).AnyTimes()
+		mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).AnyTimes()
+
+		request := func(method, path string) *http.Response {
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			return resp
+		}
+
+		Convey("Acts as the user", func() {
+			resp := request(http.MethodGet, "/v1/users/"+user.ID)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Ends once the token was dropped", func() {
+			stored = nil
+
+			resp := request(http.MethodGet, "/v1/users/"+user.ID)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Ends once the actor may no longer impersonate", func() {
+			allowed = false
+
+			resp := request(http.MethodGet, "/v1/users/"+user.ID)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Cannot change the password", func() {
+			resp := request(http.MethodPost, "/v1/auth/change-password")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Cannot change two-factor settings", func() {
+			resp := request(http.MethodPost, "/v1/auth/2fa/disable")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Cannot impersonate again", func() {
+			resp := request(http.MethodPost, "/v1/users/"+uuid.New().String()+"/impersonate")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
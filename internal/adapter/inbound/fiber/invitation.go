@@ -0,0 +1,63 @@
+package fiber_inbound_adapter
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+)
+
+type invitationAdapter struct {
+	domain domain.Domain
+}
+
+func NewInvitationAdapter(domain domain.Domain) inbound_port.InvitationHttpPort {
+	return &invitationAdapter{domain: domain}
+}
+
+func (h *invitationAdapter) GetList(a any) error {
+	c := a.(*fiber.Ctx)
+
+	invitations, err := h.domain.Invitation().List(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: invitations})
+}
+
+func (h *invitationAdapter) Create(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	var req model.InvitationInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	invitation, err := h.domain.Invitation().Invite(c.Context(), actorID, req)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(model.Response{Success: true, Message: "Invitation sent", Data: invitation})
+}
+
+func (h *invitationAdapter) Resend(a any) error {
+	c := a.(*fiber.Ctx)
+	id := c.Params("invitationId")
+
+	invitation, err := h.domain.Invitation().Resend(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Message: "Invitation sent", Data: invitation})
+}
+
+func (h *invitationAdapter) Revoke(a any) error {
+	c := a.(*fiber.Ctx)
+	id := c.Params("invitationId")
+
+	if err := h.domain.Invitation().Revoke(c.Context(), id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Message: "Invitation revoked"})
+}
@@ -0,0 +1,127 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/password"
+)
+
+func TestInvitationRoutes(t *testing.T) {
+	Convey("Test Invitation Routes", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		os.Setenv("JWT_SECRET", "test-secret")
+		defer os.Unsetenv("JWT_SECRET")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockAuditDatabasePort := mock_outbound_port.NewMockAuditDatabasePort(mockCtrl)
+		mockInvitationDatabasePort := mock_outbound_port.NewMockInvitationDatabasePort(mockCtrl)
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Audit().Return(mockAuditDatabasePort).AnyTimes()
+		mockAuditDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+		mockDatabasePort.EXPECT().Invitation().Return(mockInvitationDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+			return fn(mockDatabasePort)
+		}).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		Convey("Admins list invitations, not a user called invitations", func() {
+			raw := model.PersonalAccessTokenPrefix + "0123456789abcdef"
+			recent := time.Now()
+			mockAccessTokenDatabasePort.EXPECT().FindByHash(gomock.Any()).Return(&model.PersonalAccessToken{
+				ID:         "token-1",
+				UserID:     "admin-1",
+				Scopes:     model.AccessTokenScopes,
+				ExpiresAt:  time.Now().Add(time.Hour),
+				LastUsedAt: &recent,
+			}, nil).AnyTimes()
+			admin := &model.User{ID: "admin-1", Role: model.RoleAdmin, IsEmailVerified: true}
+			mockUserDatabasePort.EXPECT().FindByID("admin-1").Return(admin, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().HasPermission(model.RoleAdmin, model.PermissionUsersRead).Return(true, nil).Times(1)
+			mockInvitationDatabasePort.EXPECT().FindAll().Return([]model.Invitation{{ID: "i", Email: "new@example.com"}}, nil).Times(1)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/users/invitations", nil)
+			req.Header.Set("Authorization", "Bearer "+raw)
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Accept invitation", func() {
+			accept := func(body string) *http.Response {
+				req := httptest.NewRequest(http.MethodPost, "/v1/auth/accept-invitation", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				return resp
+			}
+
+			Convey("Missing token", func() {
+				resp := accept(`{"password":"correct7horse"}`)
+				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("Unknown token", func() {
+				mockInvitationDatabasePort.EXPECT().FindByHash(password.HashToken("abc")).Return(nil, nil).Times(1)
+
+				resp := accept(`{"token":"abc","password":"correct7horse"}`)
+				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("Success signs the invitee in", func() {
+				userID := uuid.New().String()
+				mockInvitationDatabasePort.EXPECT().FindByHash(password.HashToken("abc")).Return(&model.Invitation{ID: "i", UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(userID).Return(&model.User{ID: userID, Email: "new@example.com", Name: "New Hire", Pending: true}, nil).Times(1)
+				mockInvitationDatabasePort.EXPECT().Consume("i", password.HashToken("abc")).Return(true, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				resp := accept(`{"token":"abc","name":"Jamie Doe","password":"correct7horse"}`)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+				var body struct {
+					User   model.User             `json:"user"`
+					Tokens map[string]interface{} `json:"tokens"`
+				}
+				So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+				So(body.User.Pending, ShouldBeFalse)
+				So(body.Tokens["access"], ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -1,19 +1,29 @@
 package fiber_inbound_adapter
 
 import (
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
+	"prabogo/utils"
+	"prabogo/utils/activity"
 	"prabogo/utils/jwt"
+	"prabogo/utils/log"
+	"prabogo/utils/signature"
 )
 
 type MiddlewareAdapter interface {
 	Auth(a any) error
-	RequireAdmin(a any) error
-	RequireAdminOrSelf(a any) error
+	RequirePermission(a any, permission string) error
+	RequirePermissionOrSelf(a any, permission string) error
+	RequireVerifiedEmail(a any) error
+	RequireScope(a any, scope string) error
+	RequireClientScope(a any, scope string) error
+	RequireSession(a any) error
 	InternalAuth(a any) error
 	ClientAuth(a any) error
 }
@@ -42,72 +52,220 @@ func (m *middlewareAdapter) Auth(a any) error {
 	}
 
 	tokenString := parts[1]
+	// Personal access tokens are always issued here, whatever AUTH_DRIVER says
+	if strings.HasPrefix(tokenString, model.PersonalAccessTokenPrefix) {
+		return m.accessTokenAuth(c, tokenString)
+	}
 	claims, err := jwt.ValidateLocalToken(tokenString)
+	// Impersonation tokens are always issued here too
+	if os.Getenv("AUTH_DRIVER") == authDriverOIDC && (err != nil || claims.Act == nil) {
+		return m.externalAuth(c, tokenString)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Invalid or expired token"})
 	}
 
-	if claims.Type != "access" {
+	// Client tokens only reach the machine-to-machine routes
+	if claims.Type != "access" || claims.ClientID != "" {
 		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Invalid token type"})
 	}
 
+	revoked, err := m.domain.Auth().IsTokenRevoked(c.Context(), claims)
+	if err != nil || revoked {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Token has been revoked"})
+	}
+	if claims.Act != nil {
+		if err := m.domain.Auth().CheckImpersonation(c.Context(), tokenString, claims); err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Impersonation has ended"})
+		}
+	}
+
 	c.Locals("userID", claims.Sub)
+	c.Locals("sessionID", claims.Sid)
+	// Handlers log with c.Context(), so every entry of an impersonated request names the actor
+	c.Context().SetUserValue(activity.UserID, claims.Sub)
+	if claims.Act != nil {
+		c.Locals("actorID", claims.Act.Sub)
+		c.Context().SetUserValue(activity.ActorID, claims.Act.Sub)
+		log.WithContext(c.Context()).Infof("impersonated request %s %s", c.Method(), c.Path())
+	}
 	
 	return c.Next()
 }
 
-func (m *middlewareAdapter) RequireAdmin(a any) error {
+// RequirePermission lets the request through when the caller's role grants permission
+func (m *middlewareAdapter) RequirePermission(a any, permission string) error {
 	c := a.(*fiber.Ctx)
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
 		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
 	}
 
-	user, err := m.domain.User().GetByID(c.Context(), userID)
-	if err != nil || user == nil {
+	return m.checkPermission(c, userID, permission)
+}
+
+// RequirePermissionOrSelf also lets users act on their own :id without the permission
+func (m *middlewareAdapter) RequirePermissionOrSelf(a any, permission string) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	targetID := c.Params("id")
+
+	if targetID == userID {
+		return c.Next()
+	}
+
+	return m.checkPermission(c, userID, permission)
+}
+
+func (m *middlewareAdapter) checkPermission(c *fiber.Ctx, userID, permission string) error {
+	granted, err := m.domain.Role().HasPermission(c.Context(), userID, permission)
+	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "User not found"})
 	}
 
-	if user.Role != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: Admins only"})
+	if !granted {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: missing permission " + permission})
 	}
 
 	return c.Next()
 }
 
-func (m *middlewareAdapter) RequireAdminOrSelf(a any) error {
+func (m *middlewareAdapter) RequireVerifiedEmail(a any) error {
 	c := a.(*fiber.Ctx)
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
 		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
 	}
 
-	targetID := c.Params("id")
+	user, err := m.domain.User().GetByID(c.Context(), userID)
+	if err != nil || user == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "User not found"})
+	}
 
-	if targetID == userID {
+	if !user.IsEmailVerified {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: Email not verified"})
+	}
+
+	return c.Next()
+}
+
+// RequireScope lets a personal access token through only when it was granted scope.
+// Session logins are not limited by scopes.
+func (m *middlewareAdapter) RequireScope(a any, scope string) error {
+	c := a.(*fiber.Ctx)
+	scopes, ok := c.Locals("tokenScopes").([]string)
+	if !ok {
 		return c.Next()
 	}
 
-	user, err := m.domain.User().GetByID(c.Context(), userID)
-	if err != nil || user == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "User not found"})
+	for _, s := range scopes {
+		if s == scope {
+			return c.Next()
+		}
+	}
+	return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: token lacks scope " + scope})
+}
+
+// RequireClientScope authorizes a machine-to-machine call: the bearer must be a token from the
+// client_credentials grant that holds scope, or ClientAuth resolved a client granted scope
+func (m *middlewareAdapter) RequireClientScope(a any, scope string) error {
+	c := a.(*fiber.Ctx)
+	if client, ok := c.Locals("client").(*model.Client); ok {
+		if !utils.IsInList(client.Scopes, scope) {
+			return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: client lacks scope " + scope})
+		}
+		return c.Next()
 	}
 
-	if user.Role != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: Access denied"})
+	claims, message := m.clientClaims(c)
+	if claims == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: message})
 	}
 
+	c.Locals("clientID", claims.ClientID)
+	c.Context().SetUserValue(activity.ClientID, claims.ClientID)
+
+	if !utils.IsInList(strings.Fields(claims.Scope), scope) {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: client lacks scope " + scope})
+	}
 	return c.Next()
 }
 
-// --- Legacy Middleware (Stubs to satisfy interface) ---
+// RequireSession keeps personal access tokens and impersonated requests away from account
+// security routes
+func (m *middlewareAdapter) RequireSession(a any) error {
+	c := a.(*fiber.Ctx)
+	if _, ok := c.Locals("tokenScopes").([]string); ok {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: personal access tokens cannot be used here"})
+	}
+	if _, ok := c.Locals("actorID").(string); ok {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: "Forbidden: not allowed while impersonating"})
+	}
+	return c.Next()
+}
+
+var signatureErrors = map[error]string{
+	signature.ErrMissing:  "Missing request signature",
+	signature.ErrExpired:  "Request timestamp is too old or too far ahead",
+	signature.ErrInvalid:  "Invalid request signature",
+	signature.ErrReplayed: "Request nonce was already used",
+}
 
+// InternalAuth accepts requests signed with INTERNAL_KEY, see utils/signature
 func (m *middlewareAdapter) InternalAuth(a any) error {
-	// Implement original logic if needed, or redirect to Auth
-	return m.Auth(a)
+	c := a.(*fiber.Ctx)
+	parts := strings.Split(c.Get("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != signature.Scheme || parts[1] == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Missing request signature"})
+	}
+
+	request := signature.Request{
+		Method:    c.Method(),
+		Path:      c.OriginalURL(),
+		Body:      c.Body(),
+		Timestamp: c.Get(signature.HeaderTimestamp),
+		Nonce:     c.Get(signature.HeaderNonce),
+	}
+	if err := m.domain.Auth().VerifySignedRequest(c.Context(), request, parts[1]); err != nil {
+		if message, ok := signatureErrors[err]; ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: message})
+		}
+		log.WithContext(c.Context()).Error(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: "Internal Server Error"})
+	}
+
+	return c.Next()
 }
 
+// ClientAuth authenticates service-to-service calls by the client's bearer key
 func (m *middlewareAdapter) ClientAuth(a any) error {
-	// Implement original logic if needed, or redirect to Auth
-	return m.Auth(a)
-}
\ No newline at end of file
+	c := a.(*fiber.Ctx)
+	parts := strings.Split(c.Get("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Missing client bearer key"})
+	}
+
+	bearerKey := parts[1]
+
+	client, err := m.domain.Client().FindByBearerKey(c.Context(), bearerKey)
+	if err != nil {
+		log.WithContext(c.Context()).Error(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: "Internal Server Error"})
+	}
+	if client == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unknown client bearer key"})
+	}
+
+	clientID := strconv.Itoa(client.ID)
+	c.Locals("client", client)
+	c.Locals("clientID", clientID)
+	c.SetUserContext(activity.WithClientID(c.UserContext(), clientID))
+	c.Context().SetUserValue(activity.ClientID, clientID)
+
+	return c.Next()
+}
+
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
 	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/signature"
 )
 
 func TestMiddlewareAdapter(t *testing.T) {
@@ -28,12 +31,14 @@ func TestMiddlewareAdapter(t *testing.T) {
 		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
 		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
+		mockNonceCachePort := mock_outbound_port.NewMockNonceCachePort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockCachePort.EXPECT().Nonce().Return(mockNonceCachePort).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mock_outbound_port.NewMockClientMessagePort(mockCtrl)).AnyTimes()
 
-		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort)
+		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mock_outbound_port.NewMockEmailPort(mockCtrl), mock_outbound_port.NewMockDirectoryPort(mockCtrl))
 		adapter := fiber_inbound_adapter.NewAdapter(dom)
 
 		Convey("InternalAuth", func() {
@@ -74,7 +79,7 @@ func TestMiddlewareAdapter(t *testing.T) {
 				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
 			})
 
-			Convey("Valid bearer token", func() {
+			Convey("Plain internal key is not accepted", func() {
 				os.Setenv("INTERNAL_KEY", "valid-key")
 				defer os.Unsetenv("INTERNAL_KEY")
 
@@ -83,7 +88,64 @@ func TestMiddlewareAdapter(t *testing.T) {
 				resp, err := app.Test(req)
 				So(err, ShouldBeNil)
 				defer resp.Body.Close()
-				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Signed request", func() {
+				os.Setenv("INTERNAL_KEY", "valid-key")
+				defer os.Unsetenv("INTERNAL_KEY")
+
+				app.Post("/test", func(c *fiber.Ctx) error {
+					return c.SendString("OK")
+				})
+				req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"ids":[1]}`))
+				So(signature.SignRequest(req, "valid-key"), ShouldBeNil)
+
+				Convey("Valid signature", func() {
+					mockNonceCachePort.EXPECT().Claim(req.Header.Get(signature.HeaderNonce), gomock.Any()).Return(true, nil).Times(1)
+
+					resp, err := app.Test(req)
+					So(err, ShouldBeNil)
+					defer resp.Body.Close()
+					So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				})
+
+				Convey("Replayed nonce", func() {
+					mockNonceCachePort.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+
+					resp, err := app.Test(req)
+					So(err, ShouldBeNil)
+					defer resp.Body.Close()
+					So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				})
+
+				Convey("Tampered body", func() {
+					tampered := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"ids":[2]}`))
+					tampered.Header = req.Header
+
+					resp, err := app.Test(tampered)
+					So(err, ShouldBeNil)
+					defer resp.Body.Close()
+					So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				})
+
+				Convey("Stale timestamp", func() {
+					stale := signature.Request{
+						Method:    http.MethodPost,
+						Path:      "/test",
+						Body:      []byte(`{"ids":[1]}`),
+						Timestamp: strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10),
+						Nonce:     "stale-nonce",
+					}
+					req.Header.Set(signature.HeaderTimestamp, stale.Timestamp)
+					req.Header.Set(signature.HeaderNonce, stale.Nonce)
+					req.Header.Set("Authorization", signature.Scheme+" "+stale.Signature("valid-key"))
+
+					resp, err := app.Test(req)
+					So(err, ShouldBeNil)
+					defer resp.Body.Close()
+					So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				})
 			})
 
 			Convey("Malformed authorization header", func() {
@@ -138,6 +200,7 @@ func TestMiddlewareAdapter(t *testing.T) {
 				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
 				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
 				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.Client{clientOutput}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindScopes(1).Return([]string{}, nil).Times(1)
 				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -0,0 +1,198 @@
+package fiber_inbound_adapter
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/activity"
+	"prabogo/utils/jwt"
+	"prabogo/utils/log"
+)
+
+type oauthAdapter struct {
+	domain domain.Domain
+}
+
+func NewOAuthAdapter(domain domain.Domain) inbound_port.OAuthHttpPort {
+	return &oauthAdapter{domain: domain}
+}
+
+// oauthRequest is the form body of the OAuth endpoints; JSON is accepted as well
+type oauthRequest struct {
+	GrantType     string `form:"grant_type" json:"grant_type"`
+	Scope         string `form:"scope" json:"scope"`
+	Token         string `form:"token" json:"token"`
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+	ClientID      string `form:"client_id" json:"client_id"`
+	ClientSecret  string `form:"client_secret" json:"client_secret"`
+}
+
+// Token is the RFC 6749 token endpoint. Only the client_credentials grant is offered; users
+// sign in through /v1/auth.
+func (h *oauthAdapter) Token(a any) error {
+	c := a.(*fiber.Ctx)
+	var req oauthRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorInvalidRequest, Description: "invalid body"})
+	}
+
+	if req.GrantType == "" {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorInvalidRequest, Description: "grant_type is required"})
+	}
+	if req.GrantType != model.GrantTypeClientCredentials {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorUnsupportedGrantType, Description: "only client_credentials is supported"})
+	}
+
+	client, err := h.authenticateClient(c, req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	ctx := activity.WithClientID(c.Context(), strconv.Itoa(client.ID))
+	token, err := h.domain.OAuth().IssueClientToken(ctx, client, req.Scope)
+	if err != nil {
+		return oauthError(c, err)
+	}
+	log.WithContext(ctx).Infof("client token issued for scope %q", token.Scope)
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+	return c.JSON(token)
+}
+
+// clientClaims accepts access tokens from the client_credentials grant only; on rejection
+// the message says why
+func (m *middlewareAdapter) clientClaims(c *fiber.Ctx) (*jwt.TokenPayload, string) {
+	tokenString, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !found || tokenString == "" {
+		return nil, "Missing client token"
+	}
+
+	claims, err := jwt.ValidateLocalToken(tokenString)
+	if err != nil || claims.Type != model.TokenTypeAccess || claims.ClientID == "" {
+		return nil, "Invalid or expired client token"
+	}
+
+	revoked, err := m.domain.Auth().IsTokenRevoked(c.Context(), claims)
+	if err != nil || revoked {
+		return nil, "Token has been revoked"
+	}
+	return claims, ""
+}
+
+// Introspect answers RFC 7662 requests. The token type hint is not needed: every token
+// issued here says what it is.
+func (h *oauthAdapter) Introspect(a any) error {
+	c := a.(*fiber.Ctx)
+	var req oauthRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorInvalidRequest, Description: "invalid body"})
+	}
+
+	client, err := h.authenticateClient(c, req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+	if req.Token == "" {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorInvalidRequest, Description: "token is required"})
+	}
+
+	result, err := h.domain.OAuth().Introspect(activity.WithClientID(c.Context(), strconv.Itoa(client.ID)), req.Token)
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(result)
+}
+
+// Revoke answers RFC 7009 requests with an empty 200, also for unknown tokens
+func (h *oauthAdapter) Revoke(a any) error {
+	c := a.(*fiber.Ctx)
+	var req oauthRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorInvalidRequest, Description: "invalid body"})
+	}
+
+	client, err := h.authenticateClient(c, req)
+	if err != nil {
+		return oauthError(c, err)
+	}
+	if req.Token == "" {
+		return oauthError(c, &model.OAuthError{Code: model.OAuthErrorInvalidRequest, Description: "token is required"})
+	}
+
+	ctx := activity.WithClientID(c.Context(), strconv.Itoa(client.ID))
+	if err := h.domain.OAuth().Revoke(ctx, client, req.Token); err != nil {
+		return oauthError(c, err)
+	}
+	log.WithContext(ctx).Info("token revoked by client")
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// authenticateClient takes the credentials from HTTP Basic auth or, failing that, the body
+// (RFC 6749 section 2.3.1)
+func (h *oauthAdapter) authenticateClient(c *fiber.Ctx, req oauthRequest) (*model.Client, error) {
+	clientID, clientSecret := req.ClientID, req.ClientSecret
+	if header := c.Get(fiber.HeaderAuthorization); header != "" {
+		id, secret, ok := basicCredentials(header)
+		if !ok {
+			return nil, &model.OAuthError{Code: model.OAuthErrorInvalidClient, Description: "malformed basic credentials"}
+		}
+		clientID, clientSecret = id, secret
+	}
+	if clientID == "" {
+		return nil, &model.OAuthError{Code: model.OAuthErrorInvalidClient, Description: "client credentials are required"}
+	}
+	return h.domain.OAuth().AuthenticateClient(c.Context(), clientID, clientSecret)
+}
+
+// basicCredentials decodes "Basic base64(id:secret)"; both parts are form-urlencoded
+func basicCredentials(header string) (string, string, bool) {
+	encoded, found := strings.CutPrefix(header, "Basic ")
+	if !found {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	id, secret, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", false
+	}
+	id, err = url.QueryUnescape(id)
+	if err != nil {
+		return "", "", false
+	}
+	secret, err = url.QueryUnescape(secret)
+	if err != nil {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// oauthError answers in the RFC 6749 error format; failed client authentication is a 401
+func oauthError(c *fiber.Ctx, err error) error {
+	var oauthErr *model.OAuthError
+	if !errors.As(err, &oauthErr) {
+		log.WithContext(c.Context()).Error(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(&model.OAuthError{Code: "server_error"})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	if oauthErr.Code == model.OAuthErrorInvalidClient {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(oauthErr)
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(oauthErr)
+}
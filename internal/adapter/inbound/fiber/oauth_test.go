@@ -0,0 +1,172 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+)
+
+func TestOAuthAdapter(t *testing.T) {
+	Convey("Test OAuth Endpoints", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		t.Setenv("JWT_SECRET", "test-secret")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		client := model.Client{ID: 7, ClientInput: model.ClientInput{Name: "billing", BearerKey: "secret-key"}}
+
+		request := func(path string, form url.Values, configure func(*http.Request)) (*http.Response, map[string]interface{}) {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if configure != nil {
+				configure(req)
+			}
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			body := map[string]interface{}{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			return resp, body
+		}
+
+		Convey("Missing client credentials", func() {
+			resp, body := request("/v1/oauth/introspect", url.Values{"token": {"x"}}, nil)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			So(resp.Header.Get("WWW-Authenticate"), ShouldStartWith, "Basic")
+			So(body["error"], ShouldEqual, model.OAuthErrorInvalidClient)
+		})
+
+		Convey("Wrong client secret", func() {
+			mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+
+			resp, body := request("/v1/oauth/introspect", url.Values{"token": {"x"}}, func(req *http.Request) {
+				req.SetBasicAuth("7", "other-key")
+			})
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			So(body["error"], ShouldEqual, model.OAuthErrorInvalidClient)
+		})
+
+		Convey("Missing token", func() {
+			mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+
+			resp, body := request("/v1/oauth/introspect", url.Values{}, func(req *http.Request) {
+				req.SetBasicAuth("7", "secret-key")
+			})
+			So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+			So(body["error"], ShouldEqual, model.OAuthErrorInvalidRequest)
+		})
+
+		Convey("Introspecting an unknown token with credentials in the body", func() {
+			mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+
+			resp, body := request("/v1/oauth/introspect", url.Values{
+				"token":         {"not-a-token"},
+				"client_id":     {"7"},
+				"client_secret": {"secret-key"},
+			}, nil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			So(body, ShouldResemble, map[string]interface{}{"active": false})
+		})
+
+		Convey("Token endpoint", func() {
+			Convey("Unsupported grant type", func() {
+				resp, body := request("/v1/oauth/token", url.Values{"grant_type": {"password"}}, nil)
+				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(body["error"], ShouldEqual, model.OAuthErrorUnsupportedGrantType)
+			})
+
+			Convey("Client credentials grant", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeUsersRead}, nil).Times(1)
+
+				resp, body := request("/v1/oauth/token", url.Values{"grant_type": {"client_credentials"}}, func(req *http.Request) {
+					req.SetBasicAuth("7", "secret-key")
+				})
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				So(resp.Header.Get("Cache-Control"), ShouldEqual, "no-store")
+				So(body["token_type"], ShouldEqual, "Bearer")
+				So(body["scope"], ShouldEqual, model.ScopeUsersRead)
+				So(body["access_token"], ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("Machine-to-machine routes", func() {
+			mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+			mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+			mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+			mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+			mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), "").Return(false, nil).AnyTimes()
+			mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+
+			dom := domain.NewDomain(
+				mockDatabasePort,
+				mock_outbound_port.NewMockMessagePort(mockCtrl),
+				mockCachePort,
+				mock_outbound_port.NewMockEmailPort(mockCtrl),
+				mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+			)
+			app := fiber.New()
+			fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+			get := func(path, scope string) *http.Response {
+				token, _, err := jwt.GenerateClientToken("7", scope)
+				So(err, ShouldBeNil)
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				return resp
+			}
+
+			Convey("Client holding the scope", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1"}, nil).Times(1)
+
+				So(get("/v1/m2m/users/user-1", model.ScopeUsersRead).StatusCode, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Client lacking the scope", func() {
+				So(get("/v1/m2m/users/user-1", model.ScopeSessionsRead).StatusCode, ShouldEqual, http.StatusForbidden)
+			})
+
+			Convey("Client tokens are no user tokens", func() {
+				So(get("/v1/users/user-1", model.ScopeUsersRead).StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("Revoking an unknown token succeeds", func() {
+			mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+
+			resp, _ := request("/v1/oauth/revoke", url.Values{"token": {"not-a-token"}}, func(req *http.Request) {
+				req.SetBasicAuth("7", "secret-key")
+			})
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+	})
+}
@@ -0,0 +1,194 @@
+package fiber_inbound_adapter_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+// newJWKSServer stands in for the identity provider's JWKS endpoint
+func newJWKSServer(key *rsa.PublicKey, kid string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+}
+
+func signIdPToken(key *rsa.PrivateKey, kid string, claims gojwt.MapClaims) string {
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, _ := token.SignedString(key)
+	return signed
+}
+
+func TestOIDCAuth(t *testing.T) {
+	Convey("Test OIDC Auth Driver", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockIdentityDatabasePort := mock_outbound_port.NewMockIdentityDatabasePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Identity().Return(mockIdentityDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		adapter := fiber_inbound_adapter.NewAdapter(dom)
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		So(err, ShouldBeNil)
+		server := newJWKSServer(&key.PublicKey, "test-kid")
+		defer server.Close()
+
+		os.Setenv("AUTH_DRIVER", "oidc")
+		os.Setenv("AUTH_JWKS_URL", server.URL)
+		defer os.Unsetenv("AUTH_DRIVER")
+		defer os.Unsetenv("AUTH_JWKS_URL")
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			return adapter.Middleware().Auth(c)
+		})
+		app.Get("/test", func(c *fiber.Ctx) error {
+			return c.SendString(c.Locals("userID").(string))
+		})
+
+		user := &model.User{ID: "local-user", Name: "Jane", Email: "jane@example.com", Role: "user", IsEmailVerified: true}
+		claims := gojwt.MapClaims{
+			"sub":            "idp-subject",
+			"email":          "jane@example.com",
+			"email_verified": true,
+			"name":           "Jane",
+			"groups":         []string{"staff"},
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+
+		Convey("Token signed by another key", func() {
+			otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signIdPToken(otherKey, "test-kid", claims))
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Local HS256 token is rejected", func() {
+			token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, claims)
+			signed, _ := token.SignedString([]byte("secret"))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signed)
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Known identity resolves to the linked user", func() {
+			mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").
+				Return(&model.UserIdentity{Provider: model.IdentityProviderOIDC, Subject: "idp-subject", UserID: user.ID}, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signIdPToken(key, "test-kid", claims))
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Pending user is refused", func() {
+			pending := *user
+			pending.Pending = true
+			mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").
+				Return(&model.UserIdentity{Provider: model.IdentityProviderOIDC, Subject: "idp-subject", UserID: user.ID}, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&pending, nil).Times(1)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signIdPToken(key, "test-kid", claims))
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("User scheduled for deletion is refused", func() {
+			scheduled := *user
+			due := time.Now().Add(24 * time.Hour)
+			scheduled.DeletionScheduledAt = &due
+			mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").
+				Return(&model.UserIdentity{Provider: model.IdentityProviderOIDC, Subject: "idp-subject", UserID: user.ID}, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&scheduled, nil).Times(1)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signIdPToken(key, "test-kid", claims))
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Admin group promotes the user", func() {
+			claims["groups"] = []string{"admin"}
+			mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").
+				Return(&model.UserIdentity{Provider: model.IdentityProviderOIDC, Subject: "idp-subject", UserID: user.ID}, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+			mockRoleDatabasePort.EXPECT().FindByName("admin").Return(&model.Role{Name: "admin"}, nil).Times(1)
+			// Runs on fiber's goroutine, so assert after the request
+			var updatedRole string
+			mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+				updatedRole = u.Role
+				return nil
+			}).Times(1)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signIdPToken(key, "test-kid", claims))
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			So(updatedRole, ShouldEqual, "admin")
+		})
+
+		Convey("Wrong audience", func() {
+			os.Setenv("AUTH_AUDIENCE", "prabogo")
+			defer os.Unsetenv("AUTH_AUDIENCE")
+			claims["aud"] = "another-app"
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+signIdPToken(key, "test-kid", claims))
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package fiber_inbound_adapter
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+)
+
+type registrationAdapter struct {
+	domain domain.Domain
+}
+
+func NewRegistrationAdapter(domain domain.Domain) inbound_port.RegistrationHttpPort {
+	return &registrationAdapter{domain: domain}
+}
+
+func (h *registrationAdapter) GetPolicy(a any) error {
+	c := a.(*fiber.Ctx)
+
+	policy, err := h.domain.Registration().GetPolicy(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: policy})
+}
+
+func (h *registrationAdapter) UpdatePolicy(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	var req model.RegistrationPolicyInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	policy, err := h.domain.Registration().UpdatePolicy(c.Context(), actorID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Message: "Registration policy updated", Data: policy})
+}
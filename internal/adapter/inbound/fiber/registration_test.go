@@ -0,0 +1,132 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+)
+
+func TestRegistrationPolicy(t *testing.T) {
+	Convey("Test Registration Policy", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		t.Setenv("JWT_SECRET", "test-secret")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockRegistrationPolicyDatabasePort := mock_outbound_port.NewMockRegistrationPolicyDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().RegistrationPolicy().Return(mockRegistrationPolicyDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+		mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+		mockRoleDatabasePort.EXPECT().HasPermission(model.RoleAdmin, gomock.Any()).Return(true, nil).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mockCachePort,
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		request := func(method, path, body, bearer string) *http.Response {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			if bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+bearer)
+			}
+			if body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			return resp
+		}
+		admin := &model.User{ID: uuid.New().String(), Role: model.RoleAdmin, IsEmailVerified: true}
+		signIn := func(user *model.User) string {
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).AnyTimes()
+			access, _, _, _, err := jwt.GenerateAuthTokens(user.ID, uuid.New().String())
+			So(err, ShouldBeNil)
+			return access
+		}
+
+		Convey("Refused sign-up is a 403 with the error code", func() {
+			mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeInviteOnly}, nil).Times(1)
+
+			resp := request(http.MethodPost, "/v1/auth/register", `{"name":"New","email":"new@example.com","password":"correct7horse"}`, "")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+
+			var body struct {
+				Data model.RegistrationError `json:"data"`
+			}
+			So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+			So(body.Data.Code, ShouldEqual, model.RegistrationErrorInviteOnly)
+		})
+
+		Convey("Admins read the open default", func() {
+			access := signIn(admin)
+			mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(nil, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/settings/registration", "", access)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+			var body struct {
+				Data model.RegistrationPolicy `json:"data"`
+			}
+			So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+			So(body.Data.Mode, ShouldEqual, model.RegistrationModeOpen)
+		})
+
+		Convey("Admins replace the policy", func() {
+			access := signIn(admin)
+			var saved *model.RegistrationPolicy
+			mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).DoAndReturn(func(p *model.RegistrationPolicy) error {
+				saved = p
+				return nil
+			}).Times(1)
+
+			resp := request(http.MethodPut, "/v1/settings/registration", `{"mode":"restricted","allowed_domains":["@Example.com"]}`, access)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			So(saved.AllowedDomains, ShouldResemble, []string{"example.com"})
+			So(*saved.UpdatedBy, ShouldEqual, admin.ID)
+		})
+
+		Convey("Invalid policy", func() {
+			access := signIn(admin)
+			mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).Times(0)
+
+			resp := request(http.MethodPut, "/v1/settings/registration", `{"mode":"restricted"}`, access)
+			So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Other roles need the permission", func() {
+			access := signIn(&model.User{ID: uuid.New().String(), Role: model.RoleUser, IsEmailVerified: true})
+			mockRoleDatabasePort.EXPECT().HasPermission(model.RoleUser, model.PermissionSettingsWrite).Return(false, nil).Times(1)
+			mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).Times(0)
+
+			resp := request(http.MethodPut, "/v1/settings/registration", `{"mode":"closed"}`, access)
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
@@ -35,4 +35,32 @@ func (s *adapter) Auth() inbound_port.AuthHttpPort {
 
 func (s *adapter) User() inbound_port.UserHttpPort {
 	return NewUserAdapter(s.domain)
+}
+
+func (s *adapter) Session() inbound_port.SessionHttpPort {
+	return NewSessionAdapter(s.domain)
+}
+
+func (s *adapter) AccessToken() inbound_port.AccessTokenHttpPort {
+	return NewAccessTokenAdapter(s.domain)
+}
+
+func (s *adapter) Role() inbound_port.RoleHttpPort {
+	return NewRoleAdapter(s.domain)
+}
+
+func (s *adapter) OAuth() inbound_port.OAuthHttpPort {
+	return NewOAuthAdapter(s.domain)
+}
+
+func (s *adapter) Invitation() inbound_port.InvitationHttpPort {
+	return NewInvitationAdapter(s.domain)
+}
+
+func (s *adapter) Registration() inbound_port.RegistrationHttpPort {
+	return NewRegistrationAdapter(s.domain)
+}
+
+func (s *adapter) WellKnown() inbound_port.WellKnownHttpPort {
+	return NewWellKnownAdapter()
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+package fiber_inbound_adapter
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+)
+
+type roleAdapter struct {
+	domain domain.Domain
+}
+
+func NewRoleAdapter(domain domain.Domain) inbound_port.RoleHttpPort {
+	return &roleAdapter{domain: domain}
+}
+
+func (h *roleAdapter) GetList(a any) error {
+	c := a.(*fiber.Ctx)
+
+	roles, err := h.domain.Role().List(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: roles})
+}
+
+func (h *roleAdapter) GetOne(a any) error {
+	c := a.(*fiber.Ctx)
+	name := c.Params("name")
+
+	role, err := h.domain.Role().Get(c.Context(), name)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: role})
+}
+
+func (h *roleAdapter) Create(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	var req model.RoleInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	role, err := h.domain.Role().Create(c.Context(), actorID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(model.Response{Success: true, Data: role})
+}
+
+func (h *roleAdapter) Update(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	name := c.Params("name")
+	var req model.RoleInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	role, err := h.domain.Role().Update(c.Context(), actorID, name, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: role})
+}
+
+func (h *roleAdapter) Delete(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	name := c.Params("name")
+
+	if err := h.domain.Role().Delete(c.Context(), actorID, name); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true})
+}
+
+func (h *roleAdapter) GetPermissions(a any) error {
+	c := a.(*fiber.Ctx)
+
+	permissions, err := h.domain.Role().ListPermissions(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: permissions})
+}
@@ -0,0 +1,100 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestRequirePermission(t *testing.T) {
+	Convey("Test Require Permission", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		// A personal access token with every scope leaves the role as the only gate
+		raw := model.PersonalAccessTokenPrefix + "0123456789abcdef"
+		recent := time.Now()
+		mockAccessTokenDatabasePort.EXPECT().FindByHash(gomock.Any()).Return(&model.PersonalAccessToken{
+			ID:         "token-1",
+			UserID:     "user-1",
+			Scopes:     model.AccessTokenScopes,
+			ExpiresAt:  time.Now().Add(time.Hour),
+			LastUsedAt: &recent,
+		}, nil).AnyTimes()
+
+		request := func(method, path string) *http.Response {
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+raw)
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			return resp
+		}
+
+		Convey("Role grants the permission", func() {
+			user := &model.User{ID: "user-1", Role: "support", IsEmailVerified: true}
+			mockUserDatabasePort.EXPECT().FindByID("user-1").Return(user, nil).Times(1)
+			mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersRead).Return(true, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindAll(gomock.Any(), 1, 10, "created_at:desc").Return([]model.User{}, int64(0), nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/users/")
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Role lacks the permission", func() {
+			user := &model.User{ID: "user-1", Role: model.RoleUser, IsEmailVerified: true}
+			mockUserDatabasePort.EXPECT().FindByID("user-1").Return(user, nil).Times(1)
+			mockRoleDatabasePort.EXPECT().HasPermission(model.RoleUser, model.PermissionRolesRead).Return(false, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/roles/")
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Unverified accounts cannot write roles or settings", func() {
+			user := &model.User{ID: "user-1", Role: model.RoleAdmin}
+			mockUserDatabasePort.EXPECT().FindByID("user-1").Return(user, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().HasPermission(gomock.Any(), gomock.Any()).Times(0)
+
+			So(request(http.MethodPatch, "/v1/roles/support").StatusCode, ShouldEqual, http.StatusForbidden)
+			So(request(http.MethodDelete, "/v1/roles/support").StatusCode, ShouldEqual, http.StatusForbidden)
+			So(request(http.MethodPut, "/v1/settings/registration").StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Users may read themselves without the permission", func() {
+			user := &model.User{ID: "user-1", Role: model.RoleUser, IsEmailVerified: true}
+			mockUserDatabasePort.EXPECT().FindByID("user-1").Return(user, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/users/user-1")
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+	})
+}
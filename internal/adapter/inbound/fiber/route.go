@@ -3,10 +3,61 @@ package fiber_inbound_adapter
 import (
 	"context"
 	"github.com/gofiber/fiber/v2"
+	"prabogo/internal/model"
 	inbound_port "prabogo/internal/port/inbound"
 )
 
 func InitRoute(ctx context.Context, app *fiber.App, port inbound_port.HttpPort) {
+	// Middleware
+	authMiddleware := func(c *fiber.Ctx) error { return port.Middleware().Auth(c) }
+	verifiedMiddleware := func(c *fiber.Ctx) error { return port.Middleware().RequireVerifiedEmail(c) }
+	// Personal access tokens only reach routes that name a scope they hold; sessionMiddleware
+	// keeps them, and admins impersonating a user, off account security routes entirely
+	sessionMiddleware := func(c *fiber.Ctx) error { return port.Middleware().RequireSession(c) }
+	scope := func(scope string) fiber.Handler {
+		return func(c *fiber.Ctx) error { return port.Middleware().RequireScope(c, scope) }
+	}
+	// Role checks: the caller's role must grant the permission, or the route targets the caller
+	permission := func(permission string) fiber.Handler {
+		return func(c *fiber.Ctx) error { return port.Middleware().RequirePermission(c, permission) }
+	}
+	permissionOrSelf := func(permission string) fiber.Handler {
+		return func(c *fiber.Ctx) error { return port.Middleware().RequirePermissionOrSelf(c, permission) }
+	}
+
+	// --- DISCOVERY ROUTES ---
+	app.Get("/.well-known/jwks.json", func(c *fiber.Ctx) error { return port.WellKnown().JWKS(c) })
+	app.Get("/.well-known/openid-configuration", func(c *fiber.Ctx) error { return port.WellKnown().OpenIDConfiguration(c) })
+
+	// --- OAUTH ROUTES ---
+	// Service clients authenticate with their ID and bearer key, see oauthAdapter
+	oauth := app.Group("/v1/oauth")
+	oauth.Post("/token", func(c *fiber.Ctx) error { return port.OAuth().Token(c) })
+	oauth.Post("/introspect", func(c *fiber.Ctx) error { return port.OAuth().Introspect(c) })
+	oauth.Post("/revoke", func(c *fiber.Ctx) error { return port.OAuth().Revoke(c) })
+
+	// --- MACHINE-TO-MACHINE ROUTES ---
+	// Bearer tokens from the client_credentials grant, authorized by the scopes of the client
+	clientScope := func(scope string) fiber.Handler {
+		return func(c *fiber.Ctx) error { return port.Middleware().RequireClientScope(c, scope) }
+	}
+	m2m := app.Group("/v1/m2m")
+	m2m.Get("/users/:id", clientScope(model.ScopeUsersRead), func(c *fiber.Ctx) error { return port.User().GetOne(c) })
+
+	// --- SERVICE ROUTES ---
+	// The same calls for services sending their bearer key itself, authorized by the client's scopes
+	service := app.Group("/v1/service")
+	service.Use(func(c *fiber.Ctx) error { return port.Middleware().ClientAuth(c) })
+	service.Get("/users/:id", clientScope(model.ScopeUsersRead), func(c *fiber.Ctx) error { return port.User().GetOne(c) })
+
+	// --- INTERNAL ROUTES ---
+	// Requests signed with INTERNAL_KEY by our own services, see utils/signature.SignRequest
+	internal := app.Group("/v1/internal")
+	internal.Use(func(c *fiber.Ctx) error { return port.Middleware().InternalAuth(c) })
+	internal.Post("/clients/upsert", func(c *fiber.Ctx) error { return port.Client().Upsert(c) })
+	internal.Post("/clients/find", func(c *fiber.Ctx) error { return port.Client().Find(c) })
+	internal.Post("/clients/delete", func(c *fiber.Ctx) error { return port.Client().Delete(c) })
+
 	// --- AUTH ROUTES ---
 	auth := app.Group("/v1/auth")
 	auth.Post("/register", func(c *fiber.Ctx) error { return port.Auth().Register(c) })
@@ -15,29 +66,94 @@ func InitRoute(ctx context.Context, app *fiber.App, port inbound_port.HttpPort)
 	auth.Post("/logout", func(c *fiber.Ctx) error { return port.Auth().Logout(c) })
 	auth.Post("/forgot-password", func(c *fiber.Ctx) error { return port.Auth().ForgotPassword(c) })
 	auth.Post("/reset-password", func(c *fiber.Ctx) error { return port.Auth().ResetPassword(c) })
+	auth.Post("/change-password", authMiddleware, sessionMiddleware, func(c *fiber.Ctx) error { return port.Auth().ChangePassword(c) })
+	auth.Post("/send-verification-email", authMiddleware, sessionMiddleware, func(c *fiber.Ctx) error { return port.Auth().SendVerificationEmail(c) })
+	auth.Post("/verify-email", func(c *fiber.Ctx) error { return port.Auth().VerifyEmail(c) })
+	auth.Post("/accept-invitation", func(c *fiber.Ctx) error { return port.Auth().AcceptInvitation(c) })
+
+	// Passwordless sign-in: request emails a single-use link, verify exchanges it for tokens
+	auth.Post("/magic-link", func(c *fiber.Ctx) error { return port.Auth().RequestMagicLink(c) })
+	auth.Post("/magic-link/verify", func(c *fiber.Ctx) error { return port.Auth().VerifyMagicLink(c) })
+
+	// Two-factor: verify completes a login, the rest manage the caller's own 2FA
+	auth.Post("/2fa/verify", func(c *fiber.Ctx) error { return port.Auth().VerifyTwoFactor(c) })
+	auth.Post("/2fa/enroll", authMiddleware, sessionMiddleware, func(c *fiber.Ctx) error { return port.Auth().EnrollTwoFactor(c) })
+	auth.Post("/2fa/confirm", authMiddleware, sessionMiddleware, func(c *fiber.Ctx) error { return port.Auth().ConfirmTwoFactor(c) })
+	auth.Post("/2fa/disable", authMiddleware, sessionMiddleware, func(c *fiber.Ctx) error { return port.Auth().DisableTwoFactor(c) })
+	auth.Post("/2fa/recovery-codes", authMiddleware, sessionMiddleware, func(c *fiber.Ctx) error { return port.Auth().RegenerateRecoveryCodes(c) })
 
 	// --- USER ROUTES ---
 	users := app.Group("/v1/users")
-	
-	// Middleware for Users
-	authMiddleware := func(c *fiber.Ctx) error { return port.Middleware().Auth(c) }
-	adminMiddleware := func(c *fiber.Ctx) error { return port.Middleware().RequireAdmin(c) }
-	adminOrSelfMiddleware := func(c *fiber.Ctx) error { return port.Middleware().RequireAdminOrSelf(c) }
 
+	// Changing other accounts needs a verified address; reads and the caller's own account
+	// stay open so unverified users keep access to their sessions, tokens and data
 	users.Use(authMiddleware)
 
-	// Create: Admin Only
-	users.Post("/", adminMiddleware, func(c *fiber.Ctx) error { return port.User().Create(c) })
-	
-	// Get List: Admin Only
-	users.Get("/", adminMiddleware, func(c *fiber.Ctx) error { return port.User().GetList(c) })
-	
-	// Get One: Admin OR Self
-	users.Get("/:id", adminOrSelfMiddleware, func(c *fiber.Ctx) error { return port.User().GetOne(c) })
-	
-	// Update: Admin Only
-	users.Patch("/:id", adminMiddleware, func(c *fiber.Ctx) error { return port.User().Update(c) })
-	
-	// Delete: Admin Only
-	users.Delete("/:id", adminMiddleware, func(c *fiber.Ctx) error { return port.User().Delete(c) })
+	// Create
+	users.Post("/", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.User().Create(c) })
+
+	// Get List
+	users.Get("/", scope(model.ScopeUsersRead), permission(model.PermissionUsersRead), func(c *fiber.Ctx) error { return port.User().GetList(c) })
+
+	// Personal access tokens: the caller's own, managed from a real session only
+	users.Get("/me/tokens", sessionMiddleware, func(c *fiber.Ctx) error { return port.AccessToken().GetList(c) })
+	users.Post("/me/tokens", sessionMiddleware, func(c *fiber.Ctx) error { return port.AccessToken().Create(c) })
+	users.Delete("/me/tokens/:tokenId", sessionMiddleware, func(c *fiber.Ctx) error { return port.AccessToken().Revoke(c) })
+
+	// Personal data export and self-service deletion, also from a real session only
+	users.Get("/me/export", sessionMiddleware, func(c *fiber.Ctx) error { return port.User().Export(c) })
+	users.Delete("/me", sessionMiddleware, func(c *fiber.Ctx) error { return port.User().DeleteMe(c) })
+
+	// Invitations, registered before "/:id" so "invitations" is not taken as an ID
+	users.Get("/invitations", scope(model.ScopeUsersRead), permission(model.PermissionUsersRead), func(c *fiber.Ctx) error { return port.Invitation().GetList(c) })
+	users.Post("/invitations", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.Invitation().Create(c) })
+	users.Post("/invitations/:invitationId/resend", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.Invitation().Resend(c) })
+	users.Delete("/invitations/:invitationId", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.Invitation().Revoke(c) })
+
+	// Lockouts, registered before "/:id" so "locked" is not taken as an ID
+	users.Get("/locked", scope(model.ScopeUsersRead), permission(model.PermissionUsersRead), func(c *fiber.Ctx) error { return port.User().GetLocked(c) })
+	users.Get("/:id/lock", scope(model.ScopeUsersRead), permission(model.PermissionUsersRead), func(c *fiber.Ctx) error { return port.User().GetLockout(c) })
+	users.Delete("/:id/lock", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.User().Unlock(c) })
+
+	// Get One: permission OR Self
+	users.Get("/:id", scope(model.ScopeUsersRead), permissionOrSelf(model.PermissionUsersRead), func(c *fiber.Ctx) error { return port.User().GetOne(c) })
+
+	// Sessions: permission OR Self
+	users.Get("/:id/sessions", scope(model.ScopeSessionsRead), permissionOrSelf(model.PermissionSessionsRead), func(c *fiber.Ctx) error { return port.Session().GetList(c) })
+	users.Get("/:id/sessions/:sessionId", scope(model.ScopeSessionsRead), permissionOrSelf(model.PermissionSessionsRead), func(c *fiber.Ctx) error { return port.Session().GetOne(c) })
+	users.Delete("/:id/sessions/:sessionId", scope(model.ScopeSessionsWrite), permissionOrSelf(model.PermissionSessionsWrite), func(c *fiber.Ctx) error { return port.Session().Revoke(c) })
+	// Log out everywhere
+	users.Delete("/:id/sessions", scope(model.ScopeSessionsWrite), permissionOrSelf(model.PermissionSessionsWrite), func(c *fiber.Ctx) error { return port.Session().RevokeAll(c) })
+
+	// Update
+	users.Patch("/:id", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.User().Update(c) })
+
+	// Delete
+	users.Delete("/:id", verifiedMiddleware, scope(model.ScopeUsersWrite), permission(model.PermissionUsersWrite), func(c *fiber.Ctx) error { return port.User().Delete(c) })
+
+	// Impersonation: a short-lived token acting as the user, never from an impersonated session
+	users.Post("/:id/impersonate", verifiedMiddleware, sessionMiddleware, permission(model.PermissionUsersImpersonate), func(c *fiber.Ctx) error { return port.User().Impersonate(c) })
+
+	// Role assignment
+	users.Put("/:id/role", verifiedMiddleware, scope(model.ScopeRolesWrite), permission(model.PermissionRolesWrite), func(c *fiber.Ctx) error { return port.User().AssignRole(c) })
+
+	// --- ROLE ROUTES ---
+	roles := app.Group("/v1/roles")
+	roles.Use(authMiddleware)
+
+	roles.Get("/", scope(model.ScopeRolesRead), permission(model.PermissionRolesRead), func(c *fiber.Ctx) error { return port.Role().GetList(c) })
+	roles.Post("/", verifiedMiddleware, scope(model.ScopeRolesWrite), permission(model.PermissionRolesWrite), func(c *fiber.Ctx) error { return port.Role().Create(c) })
+	roles.Get("/:name", scope(model.ScopeRolesRead), permission(model.PermissionRolesRead), func(c *fiber.Ctx) error { return port.Role().GetOne(c) })
+	roles.Patch("/:name", verifiedMiddleware, scope(model.ScopeRolesWrite), permission(model.PermissionRolesWrite), func(c *fiber.Ctx) error { return port.Role().Update(c) })
+	roles.Delete("/:name", verifiedMiddleware, scope(model.ScopeRolesWrite), permission(model.PermissionRolesWrite), func(c *fiber.Ctx) error { return port.Role().Delete(c) })
+
+	// --- SETTINGS ROUTES ---
+	settings := app.Group("/v1/settings")
+	settings.Use(authMiddleware)
+
+	// Registration policy: who may sign up through /v1/auth/register
+	settings.Get("/registration", scope(model.ScopeSettingsRead), permission(model.PermissionSettingsRead), func(c *fiber.Ctx) error { return port.Registration().GetPolicy(c) })
+	settings.Put("/registration", verifiedMiddleware, scope(model.ScopeSettingsWrite), permission(model.PermissionSettingsWrite), func(c *fiber.Ctx) error { return port.Registration().UpdatePolicy(c) })
+
+	app.Get("/v1/permissions", authMiddleware, scope(model.ScopeRolesRead), permission(model.PermissionRolesRead), func(c *fiber.Ctx) error { return port.Role().GetPermissions(c) })
 }
\ No newline at end of file
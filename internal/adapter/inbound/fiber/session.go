@@ -0,0 +1,71 @@
+package fiber_inbound_adapter
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+)
+
+type sessionAdapter struct {
+	domain domain.Domain
+}
+
+func NewSessionAdapter(domain domain.Domain) inbound_port.SessionHttpPort {
+	return &sessionAdapter{domain: domain}
+}
+
+func (h *sessionAdapter) GetList(a any) error {
+	c := a.(*fiber.Ctx)
+	userID := c.Params("id")
+
+	sessions, err := h.domain.Session().List(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	currentID, _ := c.Locals("sessionID").(string)
+	for i := range sessions {
+		sessions[i].Current = currentID != "" && sessions[i].ID == currentID
+	}
+
+	return c.JSON(model.Response{Success: true, Data: sessions})
+}
+
+func (h *sessionAdapter) GetOne(a any) error {
+	c := a.(*fiber.Ctx)
+	userID := c.Params("id")
+	sessionID := c.Params("sessionId")
+
+	session, err := h.domain.Session().Get(c.Context(), userID, sessionID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	currentID, _ := c.Locals("sessionID").(string)
+	session.Current = currentID != "" && session.ID == currentID
+
+	return c.JSON(model.Response{Success: true, Data: session})
+}
+
+func (h *sessionAdapter) Revoke(a any) error {
+	c := a.(*fiber.Ctx)
+	userID := c.Params("id")
+	sessionID := c.Params("sessionId")
+
+	if err := h.domain.Session().Revoke(c.Context(), userID, sessionID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true})
+}
+
+func (h *sessionAdapter) RevokeAll(a any) error {
+	c := a.(*fiber.Ctx)
+	userID := c.Params("id")
+
+	if err := h.domain.Session().RevokeAll(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Message: "Logged out of all sessions"})
+}
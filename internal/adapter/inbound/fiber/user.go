@@ -1,6 +1,7 @@
 package fiber_inbound_adapter
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,14 +20,15 @@ func NewUserAdapter(domain domain.Domain) inbound_port.UserHttpPort {
 
 func (h *userAdapter) Create(a any) error {
 	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
 	var req model.UserInput
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
 	}
 
-	user, err := h.domain.User().Create(c.Context(), req)
+	user, err := h.domain.User().Create(c.Context(), actorID, req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+		return badRequest(c, err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(model.Response{Success: true, Data: user})
 }
@@ -72,25 +74,144 @@ func (h *userAdapter) GetOne(a any) error {
 
 func (h *userAdapter) Update(a any) error {
 	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
 	id := c.Params("id")
 	var req model.UserInput
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
 	}
 
-	user, err := h.domain.User().Update(c.Context(), id, req)
+	user, err := h.domain.User().Update(c.Context(), actorID, id, req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+		return badRequest(c, err)
 	}
 	return c.JSON(model.Response{Success: true, Data: user})
 }
 
 func (h *userAdapter) Delete(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	id := c.Params("id")
+
+	if err := h.domain.User().Delete(c.Context(), actorID, id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true})
+}
+
+func (h *userAdapter) GetLocked(a any) error {
+	c := a.(*fiber.Ctx)
+
+	lockouts, err := h.domain.User().GetLocked(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: lockouts})
+}
+
+func (h *userAdapter) GetLockout(a any) error {
+	c := a.(*fiber.Ctx)
+	id := c.Params("id")
+
+	lockout, err := h.domain.User().GetLockout(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{
+		Success: true,
+		Data: fiber.Map{
+			"locked":  lockout != nil,
+			"lockout": lockout,
+		},
+	})
+}
+
+func (h *userAdapter) Unlock(a any) error {
 	c := a.(*fiber.Ctx)
 	id := c.Params("id")
 
-	if err := h.domain.User().Delete(c.Context(), id); err != nil {
+	if err := h.domain.User().Unlock(c.Context(), id); err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(model.Response{Success: false, Error: err.Error()})
 	}
 	return c.JSON(model.Response{Success: true})
-}
\ No newline at end of file
+}
+
+func (h *userAdapter) AssignRole(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, _ := c.Locals("userID").(string)
+	id := c.Params("id")
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+	}
+
+	user, err := h.domain.Role().Assign(c.Context(), actorID, id, req.Role)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{Success: true, Data: user})
+}
+
+func (h *userAdapter) Impersonate(a any) error {
+	c := a.(*fiber.Ctx)
+	actorID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+	id := c.Params("id")
+
+	tokens, err := h.domain.Auth().Impersonate(deviceContext(c), actorID, id)
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.JSON(model.Response{
+		Success: true,
+		Message: "Impersonation token issued, it cannot change the password or two-factor settings",
+		Data:    fiber.Map{"tokens": tokens},
+	})
+}
+
+func (h *userAdapter) Export(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+
+	export, err := h.domain.User().Export(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+
+	// Served as a download so browsers save the archive instead of rendering it
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="user-%s-export.json"`, userID))
+	return c.JSON(model.Response{Success: true, Data: export})
+}
+
+func (h *userAdapter) DeleteMe(a any) error {
+	c := a.(*fiber.Ctx)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{Success: false, Error: "Unauthorized"})
+	}
+	sessionID, _ := c.Locals("sessionID").(string)
+
+	var req model.DeleteAccountInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: "Invalid body"})
+		}
+	}
+
+	user, err := h.domain.Auth().DeleteAccount(c.Context(), userID, sessionID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.Response{Success: false, Error: err.Error()})
+	}
+	return c.Status(fiber.StatusAccepted).JSON(model.Response{
+		Success: true,
+		Message: "Account scheduled for deletion, sign in again before then to cancel",
+		Data:    fiber.Map{"deletion_scheduled_at": user.DeletionScheduledAt},
+	})
+}
@@ -0,0 +1,145 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+	"prabogo/utils/password"
+)
+
+func TestSelfService(t *testing.T) {
+	Convey("Test Data Export and Account Deletion", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		t.Setenv("JWT_SECRET", "test-secret")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockAuditDatabasePort := mock_outbound_port.NewMockAuditDatabasePort(mockCtrl)
+		mockIdentityDatabasePort := mock_outbound_port.NewMockIdentityDatabasePort(mockCtrl)
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+		mockTwoFactorDatabasePort := mock_outbound_port.NewMockTwoFactorDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Audit().Return(mockAuditDatabasePort).AnyTimes()
+		mockAuditDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+		mockDatabasePort.EXPECT().Identity().Return(mockIdentityDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().TwoFactor().Return(mockTwoFactorDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+		mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+		dom := domain.NewDomain(
+			mockDatabasePort,
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mockCachePort,
+			mockEmailPort,
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom))
+
+		hashed, _ := password.HashPassword("password1")
+		user := &model.User{ID: uuid.New().String(), Email: "me@example.com", Password: hashed, Role: model.RoleUser, IsEmailVerified: true}
+		sessionID := uuid.New().String()
+		access, _, _, _, err := jwt.GenerateAuthTokens(user.ID, sessionID)
+		So(err, ShouldBeNil)
+
+		request := func(method, path, body string) *http.Response {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+access)
+			if body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := app.Test(req)
+			So(err, ShouldBeNil)
+			return resp
+		}
+
+		Convey("Export is served as a download", func() {
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+			mockTokenDatabasePort.EXPECT().FindSessionsByUserID(user.ID).Return([]model.Session{{ID: sessionID, UserID: user.ID}}, nil).Times(1)
+			mockIdentityDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+			mockAccessTokenDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+			mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+			mockAuditDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/users/me/export", "")
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			So(resp.Header.Get(fiber.HeaderContentDisposition), ShouldContainSubstring, "attachment")
+
+			var body struct {
+				Data model.UserExport `json:"data"`
+			}
+			So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+			So(body.Data.Profile.Email, ShouldEqual, user.Email)
+			So(body.Data.Sessions, ShouldHaveLength, 1)
+			So(body.Data.Audit, ShouldNotBeNil)
+		})
+
+		Convey("Unverified users keep their own data", func() {
+			unverified := *user
+			unverified.IsEmailVerified = false
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&unverified, nil).Times(1)
+			mockTokenDatabasePort.EXPECT().FindSessionsByUserID(user.ID).Return(nil, nil).Times(1)
+			mockIdentityDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+			mockAccessTokenDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+			mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+			mockAuditDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+
+			resp := request(http.MethodGet, "/v1/users/me/export", "")
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Unverified users cannot change other accounts", func() {
+			unverified := *user
+			unverified.IsEmailVerified = false
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&unverified, nil).Times(1)
+			mockUserDatabasePort.EXPECT().Create(gomock.Any()).Times(0)
+
+			resp := request(http.MethodPost, "/v1/users/", `{"name":"New","email":"new@example.com","password":"correct7horse"}`)
+			So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Deletion with a wrong password", func() {
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+
+			resp := request(http.MethodDelete, "/v1/users/me", `{"password":"wrong"}`)
+			So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Deletion is scheduled, not carried out", func() {
+			mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+			mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+			mockUserDatabasePort.EXPECT().Delete(gomock.Any()).Times(0)
+			mockTokenDatabasePort.EXPECT().FindSessionsByUserID(user.ID).Return(nil, nil).Times(1)
+			mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeRefresh).Return(nil).Times(1)
+			mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeImpersonation).Return(nil).Times(1)
+			mockAccessTokenDatabasePort.EXPECT().DeleteByUserID(user.ID).Return(nil).Times(1)
+			mockEmailPort.EXPECT().SendEmail(user.Email, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+			resp := request(http.MethodDelete, "/v1/users/me", `{"password":"password1"}`)
+			So(resp.StatusCode, ShouldEqual, http.StatusAccepted)
+		})
+	})
+}
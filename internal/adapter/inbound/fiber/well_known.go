@@ -0,0 +1,64 @@
+package fiber_inbound_adapter
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/jwt"
+)
+
+type wellKnownAdapter struct{}
+
+func NewWellKnownAdapter() inbound_port.WellKnownHttpPort {
+	return &wellKnownAdapter{}
+}
+
+// JWKS publishes the public signing keys so other services can verify our tokens
+func (h *wellKnownAdapter) JWKS(a any) error {
+	c := a.(*fiber.Ctx)
+	keys, err := jwt.LoadSigningKeys()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: "Signing keys unavailable"})
+	}
+
+	set := &jwt.JWKSet{Keys: []jwt.JWK{}}
+	if keys != nil {
+		set = keys.JWKSet()
+	}
+
+	// Lets JWKSClient consumers cache the set between rotations
+	c.Set(fiber.HeaderCacheControl, "public, max-age=900")
+	return c.JSON(set)
+}
+
+func (h *wellKnownAdapter) OpenIDConfiguration(a any) error {
+	c := a.(*fiber.Ctx)
+	keys, err := jwt.LoadSigningKeys()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{Success: false, Error: "Signing keys unavailable"})
+	}
+
+	algs := []string{"HS256"}
+	if keys != nil {
+		algs = keys.Algorithms()
+	}
+
+	issuer := strings.TrimSuffix(os.Getenv("JWT_ISSUER"), "/")
+	if issuer == "" {
+		issuer = c.BaseURL()
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=900")
+	return c.JSON(fiber.Map{
+		"issuer":                                issuer,
+		"jwks_uri":                              issuer + "/.well-known/jwks.json",
+		"token_endpoint":                        issuer + "/v1/auth/login",
+		"response_types_supported":              []string{"token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": algs,
+	})
+}
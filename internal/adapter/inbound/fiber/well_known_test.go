@@ -0,0 +1,93 @@
+package fiber_inbound_adapter_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/domain"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+)
+
+func TestWellKnownAdapter(t *testing.T) {
+	Convey("Test Well-Known HTTP Adapter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		dom := domain.NewDomain(
+			mock_outbound_port.NewMockDatabasePort(mockCtrl),
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockEmailPort(mockCtrl),
+			mock_outbound_port.NewMockDirectoryPort(mockCtrl),
+		)
+		adapter := fiber_inbound_adapter.NewAdapter(dom)
+
+		app := fiber.New()
+		app.Get("/.well-known/jwks.json", func(c *fiber.Ctx) error { return adapter.WellKnown().JWKS(c) })
+		app.Get("/.well-known/openid-configuration", func(c *fiber.Ctx) error { return adapter.WellKnown().OpenIDConfiguration(c) })
+
+		_, key, _ := ed25519.GenerateKey(rand.Reader)
+		der, _ := x509.MarshalPKCS8PrivateKey(key)
+		path := filepath.Join(t.TempDir(), "ed.pem")
+		os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+
+		defer os.Unsetenv("JWT_SIGNING_KEYS")
+		defer os.Unsetenv("JWT_ISSUER")
+
+		Convey("JWKS without signing keys is empty", func() {
+			os.Unsetenv("JWT_SIGNING_KEYS")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+			var set jwt.JWKSet
+			json.NewDecoder(resp.Body).Decode(&set)
+			So(set.Keys, ShouldBeEmpty)
+		})
+
+		Convey("JWKS lists the public keys", func() {
+			os.Setenv("JWT_SIGNING_KEYS", "main="+path)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			So(resp.Header.Get("Cache-Control"), ShouldContainSubstring, "max-age")
+
+			var set jwt.JWKSet
+			json.NewDecoder(resp.Body).Decode(&set)
+			So(len(set.Keys), ShouldEqual, 1)
+			So(set.Keys[0].Kid, ShouldEqual, "main")
+			So(set.Keys[0].Kty, ShouldEqual, "OKP")
+		})
+
+		Convey("OpenID configuration points at the key set", func() {
+			os.Setenv("JWT_SIGNING_KEYS", "main="+path)
+			os.Setenv("JWT_ISSUER", "https://auth.example.com")
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+			var config map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&config)
+			So(config["issuer"], ShouldEqual, "https://auth.example.com")
+			So(config["jwks_uri"], ShouldEqual, "https://auth.example.com/.well-known/jwks.json")
+			So(config["id_token_signing_alg_values_supported"], ShouldResemble, []interface{}{"EdDSA"})
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package ldap_outbound_adapter
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const (
+	defaultUserFilter = "(|(uid={username})(mail={username}))"
+	timeout           = 5 * time.Second
+)
+
+type ldapAdapter struct{}
+
+func NewAdapter() outbound_port.DirectoryPort {
+	return &ldapAdapter{}
+}
+
+// Authenticate looks the user up with LDAP_USER_FILTER under LDAP_BASE_DN, bound as
+// LDAP_BIND_DN when set (anonymously otherwise), then binds as the entry found to check
+// the password
+func (a *ldapAdapter) Authenticate(username, password string) (*model.ExternalIdentity, error) {
+	// An empty password would be an unauthenticated bind, which servers accept
+	if username == "" || password == "" {
+		return nil, nil
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if bindDN := os.Getenv("LDAP_BIND_DN"); bindDN != "" {
+		if err := conn.Bind(bindDN, os.Getenv("LDAP_BIND_PASSWORD")); err != nil {
+			return nil, fmt.Errorf("ldap service bind failed: %w", err)
+		}
+	}
+
+	entry, err := findUser(conn, username)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ldap user bind failed: %w", err)
+	}
+
+	return identityFromEntry(entry), nil
+}
+
+// dial connects to LDAP_URL, upgrading with StartTLS when LDAP_START_TLS is true
+func dial() (*ldap.Conn, error) {
+	addr := os.Getenv("LDAP_URL")
+	if addr == "" {
+		return nil, fmt.Errorf("LDAP_URL is not set")
+	}
+
+	conn, err := ldap.DialURL(addr, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("ldap dial failed: %w", err)
+	}
+	conn.SetTimeout(timeout)
+
+	if os.Getenv("LDAP_START_TLS") == "true" {
+		parsed, err := url.Parse(addr)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: parsed.Hostname()}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap starttls failed: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// findUser returns nil when no entry matches; more than one match is an error rather than a guess
+func findUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	filter := os.Getenv("LDAP_USER_FILTER")
+	if filter == "" {
+		filter = defaultUserFilter
+	}
+	filter = strings.ReplaceAll(filter, "{username}", ldap.EscapeFilter(username))
+
+	attributes := []string{attribute("LDAP_ATTR_EMAIL", "mail"), attribute("LDAP_ATTR_NAME", "cn")}
+	if subject := os.Getenv("LDAP_ATTR_SUBJECT"); subject != "" {
+		attributes = append(attributes, subject)
+	}
+	if groups := groupsAttribute(); groups != "" {
+		attributes = append(attributes, groups)
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		os.Getenv("LDAP_BASE_DN"),
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(timeout.Seconds()), false,
+		filter, attributes, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ldap search failed: %w", err)
+	}
+
+	switch len(result.Entries) {
+	case 0:
+		return nil, nil
+	case 1:
+		return result.Entries[0], nil
+	default:
+		return nil, fmt.Errorf("ldap filter matched more than one entry for %q", username)
+	}
+}
+
+// identityFromEntry maps the directory entry; the directory vouches for the address, and its
+// groups are authoritative, so an entry in none of the mapped groups gets the user role
+func identityFromEntry(entry *ldap.Entry) *model.ExternalIdentity {
+	identity := &model.ExternalIdentity{
+		Provider:      model.IdentityProviderLDAP,
+		Subject:       entry.DN,
+		Email:         entry.GetEqualFoldAttributeValue(attribute("LDAP_ATTR_EMAIL", "mail")),
+		EmailVerified: true,
+		Name:          entry.GetEqualFoldAttributeValue(attribute("LDAP_ATTR_NAME", "cn")),
+	}
+	// The DN changes when the entry is moved or renamed, an immutable attribute is better
+	if subject := os.Getenv("LDAP_ATTR_SUBJECT"); subject != "" {
+		identity.Subject = entry.GetEqualFoldAttributeValue(subject)
+	}
+
+	if groups := groupsAttribute(); groups != "" {
+		identity.Groups = []string{}
+		for _, group := range entry.GetEqualFoldAttributeValues(groups) {
+			identity.Groups = append(identity.Groups, groupName(group))
+		}
+	}
+	return identity
+}
+
+// groupName reduces a group DN such as "cn=admins,ou=groups,dc=example,dc=com" to "admins"
+// so AUTH_GROUP_ROLES can list plain names; values that are not DNs are kept as they are
+func groupName(value string) string {
+	dn, err := ldap.ParseDN(value)
+	if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+		return value
+	}
+	return dn.RDNs[0].Attributes[0].Value
+}
+
+func attribute(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// groupsAttribute reads LDAP_ATTR_GROUPS, memberOf when unset; set to empty, groups are not
+// read and local roles are left alone
+func groupsAttribute() string {
+	if value, ok := os.LookupEnv("LDAP_ATTR_GROUPS"); ok {
+		return value
+	}
+	return "memberOf"
+}
@@ -0,0 +1,126 @@
+package ldap_outbound_adapter_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	ldap_outbound_adapter "prabogo/internal/adapter/outbound/ldap"
+	"prabogo/internal/model"
+)
+
+func TestLDAPAdapter(t *testing.T) {
+	Convey("Test LDAP Directory Adapter", t, func() {
+		server, err := newDirectoryServer(
+			directoryEntry{
+				dn:       "cn=reader,dc=example,dc=com",
+				password: "reader-secret",
+			},
+			directoryEntry{
+				dn:       "uid=jdoe,ou=people,dc=example,dc=com",
+				password: "correct7horse",
+				attributes: map[string][]string{
+					"uid":       {"jdoe"},
+					"mail":      {"jdoe@example.com"},
+					"cn":        {"Jane Doe"},
+					"entryUUID": {"5b0d6f0e-0c84-4f3b-9a47-3f5d2c1e7a10"},
+					"memberOf":  {"cn=admins,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"},
+				},
+			},
+			directoryEntry{
+				dn:       "uid=nogroups,ou=people,dc=example,dc=com",
+				password: "correct7horse",
+				attributes: map[string][]string{
+					"uid":  {"nogroups"},
+					"mail": {"nogroups@example.com"},
+					"cn":   {"No Groups"},
+				},
+			},
+		)
+		So(err, ShouldBeNil)
+		defer server.Close()
+
+		t.Setenv("LDAP_URL", server.URL())
+		t.Setenv("LDAP_BASE_DN", "ou=people,dc=example,dc=com")
+		t.Setenv("LDAP_BIND_DN", "cn=reader,dc=example,dc=com")
+		t.Setenv("LDAP_BIND_PASSWORD", "reader-secret")
+
+		adapter := ldap_outbound_adapter.NewAdapter()
+
+		Convey("Maps the entry after binding as the user", func() {
+			identity, err := adapter.Authenticate("jdoe", "correct7horse")
+			So(err, ShouldBeNil)
+			So(identity, ShouldNotBeNil)
+			So(identity.Provider, ShouldEqual, model.IdentityProviderLDAP)
+			So(identity.Subject, ShouldEqual, "uid=jdoe,ou=people,dc=example,dc=com")
+			So(identity.Email, ShouldEqual, "jdoe@example.com")
+			So(identity.EmailVerified, ShouldBeTrue)
+			So(identity.Name, ShouldEqual, "Jane Doe")
+			So(identity.Groups, ShouldResemble, []string{"admins", "staff"})
+			So(server.Binds(), ShouldResemble, []string{"cn=reader,dc=example,dc=com", "uid=jdoe,ou=people,dc=example,dc=com"})
+		})
+
+		Convey("Finds the user by email too", func() {
+			identity, err := adapter.Authenticate("jdoe@example.com", "correct7horse")
+			So(err, ShouldBeNil)
+			So(identity.Email, ShouldEqual, "jdoe@example.com")
+		})
+
+		Convey("Wrong password", func() {
+			identity, err := adapter.Authenticate("jdoe", "wrong")
+			So(err, ShouldBeNil)
+			So(identity, ShouldBeNil)
+		})
+
+		Convey("Unknown user", func() {
+			identity, err := adapter.Authenticate("nobody", "correct7horse")
+			So(err, ShouldBeNil)
+			So(identity, ShouldBeNil)
+		})
+
+		Convey("Empty password never reaches the directory", func() {
+			identity, err := adapter.Authenticate("jdoe", "")
+			So(err, ShouldBeNil)
+			So(identity, ShouldBeNil)
+			So(server.Binds(), ShouldBeEmpty)
+		})
+
+		Convey("Entry without groups has an empty, not a missing, group list", func() {
+			identity, err := adapter.Authenticate("nogroups", "correct7horse")
+			So(err, ShouldBeNil)
+			So(identity.Groups, ShouldNotBeNil)
+			So(identity.Groups, ShouldBeEmpty)
+		})
+
+		Convey("Configured attributes", func() {
+			t.Setenv("LDAP_ATTR_SUBJECT", "entryUUID")
+			t.Setenv("LDAP_ATTR_GROUPS", "")
+
+			identity, err := adapter.Authenticate("jdoe", "correct7horse")
+			So(err, ShouldBeNil)
+			So(identity.Subject, ShouldEqual, "5b0d6f0e-0c84-4f3b-9a47-3f5d2c1e7a10")
+			So(identity.Groups, ShouldBeNil)
+		})
+
+		Convey("Filter matching several entries is refused", func() {
+			t.Setenv("LDAP_USER_FILTER", "(|(uid={username})(objectClass=*)(mail=*))")
+
+			_, err := adapter.Authenticate("jdoe", "correct7horse")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Wrong service account", func() {
+			t.Setenv("LDAP_BIND_PASSWORD", "wrong")
+
+			_, err := adapter.Authenticate("jdoe", "correct7horse")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Directory unreachable", func() {
+			t.Setenv("LDAP_URL", "ldap://127.0.0.1:1")
+
+			_, err := adapter.Authenticate("jdoe", "correct7horse")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
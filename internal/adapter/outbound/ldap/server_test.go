@@ -0,0 +1,212 @@
+package ldap_outbound_adapter_test
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// LDAP protocol operations and result codes the stand-in server knows about
+const (
+	opBindRequest      = 0
+	opBindResponse     = 1
+	opUnbindRequest    = 2
+	opSearchRequest    = 3
+	opSearchResultItem = 4
+	opSearchResultDone = 5
+
+	resultSuccess            = 0
+	resultInvalidCredentials = 49
+	resultUnwillingToPerform = 53
+)
+
+type directoryEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// directoryServer is a stand-in LDAP server speaking just enough of the protocol for the
+// adapter: simple binds, subtree searches with and/or/not/equality/present filters, unbind
+type directoryServer struct {
+	listener net.Listener
+	entries  []directoryEntry
+
+	mu    sync.Mutex
+	binds []string
+}
+
+func newDirectoryServer(entries ...directoryEntry) (*directoryServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &directoryServer{listener: listener, entries: entries}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, nil
+}
+
+func (s *directoryServer) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *directoryServer) Close() {
+	s.listener.Close()
+}
+
+// Binds lists the DNs bound as, in order
+func (s *directoryServer) Binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.binds...)
+}
+
+func (s *directoryServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case opBindRequest:
+			conn.Write(response(messageID, result(opBindResponse, s.bind(op))).Bytes())
+		case opSearchRequest:
+			for _, entry := range s.search(op) {
+				conn.Write(response(messageID, entry).Bytes())
+			}
+			conn.Write(response(messageID, result(opSearchResultDone, resultSuccess)).Bytes())
+		case opUnbindRequest:
+			return
+		default:
+			conn.Write(response(messageID, result(op.Tag+1, resultUnwillingToPerform)).Bytes())
+		}
+	}
+}
+
+func (s *directoryServer) bind(op *ber.Packet) int64 {
+	dn, _ := op.Children[1].Value.(string)
+	password := op.Children[2].Data.String()
+
+	s.mu.Lock()
+	s.binds = append(s.binds, dn)
+	s.mu.Unlock()
+
+	if dn == "" && password == "" {
+		return resultSuccess // anonymous
+	}
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.dn, dn) && entry.password != "" && entry.password == password {
+			return resultSuccess
+		}
+	}
+	return resultInvalidCredentials
+}
+
+func (s *directoryServer) search(op *ber.Packet) []*ber.Packet {
+	base := strings.ToLower(op.Children[0].Value.(string))
+	filter := op.Children[6]
+
+	requested := map[string]bool{}
+	for _, attribute := range op.Children[7].Children {
+		requested[strings.ToLower(attribute.Value.(string))] = true
+	}
+
+	var found []*ber.Packet
+	for _, entry := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(entry.dn), base) || !matches(filter, entry) {
+			continue
+		}
+
+		item := ber.Encode(ber.ClassApplication, ber.TypeConstructed, opSearchResultItem, nil, "Search Result Entry")
+		item.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.dn, "DN"))
+		attributes := ber.NewSequence("Attributes")
+		for name, values := range entry.attributes {
+			if len(requested) > 0 && !requested[strings.ToLower(name)] {
+				continue
+			}
+			attribute := ber.NewSequence("Attribute")
+			attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attribute.AppendChild(set)
+			attributes.AppendChild(attribute)
+		}
+		item.AppendChild(attributes)
+		found = append(found, item)
+	}
+	return found
+}
+
+func matches(filter *ber.Packet, entry directoryEntry) bool {
+	switch filter.Tag {
+	case 0: // and
+		for _, child := range filter.Children {
+			if !matches(child, entry) {
+				return false
+			}
+		}
+		return true
+	case 1: // or
+		for _, child := range filter.Children {
+			if matches(child, entry) {
+				return true
+			}
+		}
+		return false
+	case 2: // not
+		return !matches(filter.Children[0], entry)
+	case 3: // equality
+		name := filter.Children[0].Value.(string)
+		value := filter.Children[1].Value.(string)
+		for _, v := range values(entry, name) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case 7: // present
+		return len(values(entry, filter.Data.String())) > 0
+	}
+	return false
+}
+
+func values(entry directoryEntry, name string) []string {
+	for attribute, values := range entry.attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func response(messageID int64, op *ber.Packet) *ber.Packet {
+	packet := ber.NewSequence("LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	packet.AppendChild(op)
+	return packet
+}
+
+func result(tag ber.Tag, code int64) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return packet
+}
@@ -0,0 +1,149 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const tablePersonalAccessToken = "personal_access_tokens"
+
+type accessTokenAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewAccessTokenAdapter(db outbound_port.DatabaseExecutor) outbound_port.AccessTokenDatabasePort {
+	return &accessTokenAdapter{db: db}
+}
+
+func (a *accessTokenAdapter) Create(token *model.PersonalAccessToken) error {
+	ds := goqu.Dialect("postgres").Insert(tablePersonalAccessToken).Rows(
+		goqu.Record{
+			"id":         token.ID,
+			"user_id":    token.UserID,
+			"name":       token.Name,
+			"token_hash": token.TokenHash,
+			"prefix":     token.Prefix,
+			"scopes":     strings.Join(token.Scopes, ","),
+			"expires_at": token.ExpiresAt,
+			"created_at": token.CreatedAt,
+		},
+	)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *accessTokenAdapter) FindByHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	ds := goqu.Dialect("postgres").From(tablePersonalAccessToken).Where(goqu.Ex{"token_hash": tokenHash})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := scanAccessToken(a.db.QueryRow(query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (a *accessTokenAdapter) FindByUserID(userID string) ([]model.PersonalAccessToken, error) {
+	ds := goqu.Dialect("postgres").From(tablePersonalAccessToken).
+		Where(goqu.Ex{"user_id": userID}).
+		Order(goqu.I("created_at").Desc())
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []model.PersonalAccessToken{}
+	for rows.Next() {
+		t, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, rows.Err()
+}
+
+func (a *accessTokenAdapter) Delete(userID, id string) (bool, error) {
+	ds := goqu.Dialect("postgres").Delete(tablePersonalAccessToken).Where(goqu.Ex{"id": id, "user_id": userID})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := a.db.Exec(query)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (a *accessTokenAdapter) DeleteByUserID(userID string) error {
+	ds := goqu.Dialect("postgres").Delete(tablePersonalAccessToken).Where(goqu.Ex{"user_id": userID})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *accessTokenAdapter) TouchLastUsed(id string, at time.Time) error {
+	ds := goqu.Dialect("postgres").Update(tablePersonalAccessToken).
+		Set(goqu.Record{"last_used_at": at}).
+		Where(goqu.Ex{"id": id})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAccessToken(row rowScanner) (*model.PersonalAccessToken, error) {
+	var t model.PersonalAccessToken
+	var scopes string
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.TokenHash, &t.Prefix, &scopes, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Scopes = []string{}
+	if scopes != "" {
+		t.Scopes = strings.Split(scopes, ",")
+	}
+	return &t, nil
+}
@@ -0,0 +1,95 @@
+package postgres_outbound_adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestAccessTokenAdapter(t *testing.T) {
+	Convey("Test Postgres Access Token Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewAccessTokenAdapter(db)
+
+		now := time.Now()
+		columns := []string{"id", "user_id", "name", "token_hash", "prefix", "scopes", "expires_at", "last_used_at", "created_at"}
+
+		Convey("Create joins the scopes", func() {
+			mock.ExpectExec("INSERT INTO \"personal_access_tokens\" .*'users:read,users:write'").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.Create(&model.PersonalAccessToken{
+				ID: "t", UserID: "u", Name: "ci", TokenHash: "hash", Prefix: "pat_1234",
+				Scopes: []string{model.ScopeUsersRead, model.ScopeUsersWrite}, ExpiresAt: now, CreatedAt: now,
+			})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("FindByHash", func() {
+			Convey("Success splits the scopes", func() {
+				rows := sqlmock.NewRows(columns).AddRow("t", "u", "ci", "hash", "pat_1234", "users:read,sessions:read", now, nil, now)
+				mock.ExpectQuery("SELECT \\* FROM \"personal_access_tokens\"").WillReturnRows(rows)
+
+				token, err := adapter.FindByHash("hash")
+				So(err, ShouldBeNil)
+				So(token.Scopes, ShouldResemble, []string{model.ScopeUsersRead, model.ScopeSessionsRead})
+				So(token.LastUsedAt, ShouldBeNil)
+			})
+
+			Convey("Not found", func() {
+				mock.ExpectQuery("SELECT \\* FROM \"personal_access_tokens\"").WillReturnRows(sqlmock.NewRows(columns))
+
+				token, err := adapter.FindByHash("hash")
+				So(err, ShouldBeNil)
+				So(token, ShouldBeNil)
+			})
+		})
+
+		Convey("FindByUserID", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("t1", "u", "ci", "h1", "pat_1111", "users:read", now, now, now).
+				AddRow("t2", "u", "backup", "h2", "pat_2222", "sessions:write", now, nil, now)
+			mock.ExpectQuery("SELECT \\* FROM \"personal_access_tokens\" .* ORDER BY \"created_at\" DESC").WillReturnRows(rows)
+
+			tokens, err := adapter.FindByUserID("u")
+			So(err, ShouldBeNil)
+			So(len(tokens), ShouldEqual, 2)
+			So(tokens[0].LastUsedAt, ShouldNotBeNil)
+		})
+
+		Convey("Delete", func() {
+			Convey("Own token", func() {
+				mock.ExpectExec("DELETE FROM \"personal_access_tokens\"").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				deleted, err := adapter.Delete("u", "t")
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+			})
+
+			Convey("Missing token", func() {
+				mock.ExpectExec("DELETE FROM \"personal_access_tokens\"").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				deleted, err := adapter.Delete("u", "t")
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeFalse)
+			})
+		})
+
+		Convey("DeleteByUserID", func() {
+			mock.ExpectExec("DELETE FROM \"personal_access_tokens\" WHERE .*\"user_id\" = 'u'").WillReturnResult(sqlmock.NewResult(0, 3))
+
+			err := adapter.DeleteByUserID("u")
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package postgres_outbound_adapter
+
+import (
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const tableAuditEntry = "audit_entries"
+
+type auditAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewAuditAdapter(db outbound_port.DatabaseExecutor) outbound_port.AuditDatabasePort {
+	return &auditAdapter{db: db}
+}
+
+func (a *auditAdapter) Create(entry *model.AuditEntry) error {
+	ds := goqu.Dialect("postgres").Insert(tableAuditEntry).Rows(
+		goqu.Record{
+			"user_id":    entry.UserID,
+			"actor_id":   entry.ActorID,
+			"action":     entry.Action,
+			"ip":         entry.IP,
+			"user_agent": entry.UserAgent,
+			"created_at": entry.CreatedAt,
+		},
+	)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *auditAdapter) FindByUserID(userID string) ([]model.AuditEntry, error) {
+	ds := goqu.Dialect("postgres").From(tableAuditEntry).
+		Where(goqu.Ex{"user_id": userID}).
+		Order(goqu.I("created_at").Asc(), goqu.I("id").Asc())
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var e model.AuditEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.ActorID, &e.Action, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
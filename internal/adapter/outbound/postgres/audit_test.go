@@ -0,0 +1,50 @@
+package postgres_outbound_adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestAuditAdapter(t *testing.T) {
+	Convey("Test Postgres Audit Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewAuditAdapter(db)
+
+		now := time.Now()
+		columns := []string{"id", "user_id", "actor_id", "action", "ip", "user_agent", "created_at"}
+
+		Convey("Create", func() {
+			admin := "admin-1"
+			mock.ExpectExec("INSERT INTO \"audit_entries\" .*'impersonated', 'admin-1'").WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.Create(&model.AuditEntry{
+				UserID: "u", ActorID: &admin, Action: model.AuditActionImpersonated, IP: "127.0.0.1", UserAgent: "agent", CreatedAt: now,
+			})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("FindByUserID", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow(1, "u", nil, model.AuditActionSignIn, "127.0.0.1", "agent", now).
+				AddRow(2, "u", "admin-1", model.AuditActionImpersonated, "10.0.0.1", "admin-agent", now)
+			mock.ExpectQuery("SELECT \\* FROM \"audit_entries\" WHERE .*\"user_id\" = 'u'.* ORDER BY \"created_at\" ASC").WillReturnRows(rows)
+
+			entries, err := adapter.FindByUserID("u")
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 2)
+			So(entries[0].ActorID, ShouldBeNil)
+			So(*entries[1].ActorID, ShouldEqual, "admin-1")
+			So(entries[1].Action, ShouldEqual, model.AuditActionImpersonated)
+		})
+	})
+}
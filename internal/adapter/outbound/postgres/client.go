@@ -8,7 +8,10 @@ import (
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
 )
 
-const tableClient = "clients"
+const (
+	tableClient      = "clients"
+	tableClientScope = "client_scopes"
+)
 
 type clientAdapter struct {
 	db outbound_port.DatabaseExecutor
@@ -118,6 +121,61 @@ func (adapter *clientAdapter) IsExists(bearerKey string) (bool, error) {
 	return res.Next(), nil
 }
 
+func (adapter *clientAdapter) FindScopes(clientID int) ([]string, error) {
+	dialect := goqu.Dialect("postgres")
+	dataset := dialect.From(tableClientScope).Select("scope").
+		Where(goqu.Ex{"client_id": clientID}).
+		Order(goqu.I("scope").Asc())
+
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := adapter.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	scopes := []string{}
+	for res.Next() {
+		var scope string
+		if err := res.Scan(&scope); err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, res.Err()
+}
+
+func (adapter *clientAdapter) SetScopes(clientID int, scopes []string) error {
+	dialect := goqu.Dialect("postgres")
+	query, _, err := dialect.Delete(tableClientScope).Where(goqu.Ex{"client_id": clientID}).ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err = adapter.db.Exec(query); err != nil {
+		return err
+	}
+
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, 0, len(scopes))
+	for _, scope := range scopes {
+		rows = append(rows, goqu.Record{"client_id": clientID, "scope": scope})
+	}
+	query, _, err = dialect.Insert(tableClientScope).Rows(rows...).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = adapter.db.Exec(query)
+	return err
+}
+
 func addFilter(dataset *goqu.SelectDataset, filter model.ClientFilter) *goqu.SelectDataset {
 	if filter.IDs != nil {
 		dataset = dataset.Where(goqu.Ex{"id": filter.IDs})
@@ -150,5 +150,37 @@ func TestClientAdapter(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 		})
+
+		Convey("FindScopes", func() {
+			rows := sqlmock.NewRows([]string{"scope"}).AddRow("sessions:read").AddRow("users:read")
+			mock.ExpectQuery("SELECT \"scope\" FROM \"client_scopes\"").
+				WillReturnRows(rows)
+
+			scopes, err := adapter.FindScopes(1)
+			So(err, ShouldBeNil)
+			So(scopes, ShouldResemble, []string{"sessions:read", "users:read"})
+		})
+
+		Convey("SetScopes", func() {
+			Convey("Replaces the scopes", func() {
+				mock.ExpectExec("DELETE FROM \"client_scopes\"").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO \"client_scopes\"").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				err := adapter.SetScopes(1, []string{"users:read"})
+				So(err, ShouldBeNil)
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+
+			Convey("Empty list only clears them", func() {
+				mock.ExpectExec("DELETE FROM \"client_scopes\"").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				err := adapter.SetScopes(1, []string{})
+				So(err, ShouldBeNil)
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+		})
 	})
 }
@@ -0,0 +1,84 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const tableUserIdentity = "user_identities"
+
+type identityAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewIdentityAdapter(db outbound_port.DatabaseExecutor) outbound_port.IdentityDatabasePort {
+	return &identityAdapter{db: db}
+}
+
+func (a *identityAdapter) FindByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	ds := goqu.Dialect("postgres").From(tableUserIdentity).
+		Where(goqu.Ex{"provider": provider, "subject": subject})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var i model.UserIdentity
+	err = a.db.QueryRow(query).Scan(&i.ID, &i.Provider, &i.Subject, &i.UserID, &i.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (a *identityAdapter) Create(identity *model.UserIdentity) error {
+	ds := goqu.Dialect("postgres").Insert(tableUserIdentity).Rows(
+		goqu.Record{
+			"provider":   identity.Provider,
+			"subject":    identity.Subject,
+			"user_id":    identity.UserID,
+			"created_at": identity.CreatedAt,
+		},
+	)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *identityAdapter) FindByUserID(userID string) ([]model.UserIdentity, error) {
+	ds := goqu.Dialect("postgres").From(tableUserIdentity).
+		Where(goqu.Ex{"user_id": userID}).
+		Order(goqu.I("created_at").Asc())
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []model.UserIdentity{}
+	for rows.Next() {
+		var i model.UserIdentity
+		if err := rows.Scan(&i.ID, &i.Provider, &i.Subject, &i.UserID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, i)
+	}
+	return identities, rows.Err()
+}
@@ -0,0 +1,136 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const tableInvitation = "invitations"
+
+type invitationAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewInvitationAdapter(db outbound_port.DatabaseExecutor) outbound_port.InvitationDatabasePort {
+	return &invitationAdapter{db: db}
+}
+
+func (a *invitationAdapter) Create(invitation *model.Invitation) error {
+	ds := goqu.Dialect("postgres").Insert(tableInvitation).Rows(
+		goqu.Record{
+			"id":         invitation.ID,
+			"user_id":    invitation.UserID,
+			"token_hash": invitation.TokenHash,
+			"invited_by": invitation.InvitedBy,
+			"expires_at": invitation.ExpiresAt,
+			"sent_at":    invitation.SentAt,
+			"created_at": invitation.CreatedAt,
+		},
+	)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *invitationAdapter) FindByID(id string) (*model.Invitation, error) {
+	return a.fetchOne(invitationSelect().Where(goqu.Ex{"i.id": id}))
+}
+
+func (a *invitationAdapter) FindByHash(tokenHash string) (*model.Invitation, error) {
+	return a.fetchOne(invitationSelect().Where(goqu.Ex{"i.token_hash": tokenHash}))
+}
+
+func (a *invitationAdapter) FindAll() ([]model.Invitation, error) {
+	query, _, err := invitationSelect().Order(goqu.I("i.created_at").Desc()).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []model.Invitation{}
+	for rows.Next() {
+		i, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *i)
+	}
+	return invitations, rows.Err()
+}
+
+func (a *invitationAdapter) Renew(id string, tokenHash string, expiresAt time.Time, sentAt time.Time) error {
+	ds := goqu.Dialect("postgres").Update(tableInvitation).
+		Set(goqu.Record{"token_hash": tokenHash, "expires_at": expiresAt, "sent_at": sentAt}).
+		Where(goqu.Ex{"id": id})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *invitationAdapter) Consume(id string, tokenHash string) (bool, error) {
+	ds := goqu.Dialect("postgres").Delete(tableInvitation).Where(goqu.Ex{"id": id, "token_hash": tokenHash})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := a.db.Exec(query)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (a *invitationAdapter) fetchOne(ds *goqu.SelectDataset) (*model.Invitation, error) {
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := scanInvitation(a.db.QueryRow(query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func invitationSelect() *goqu.SelectDataset {
+	return goqu.Dialect("postgres").From(goqu.T(tableInvitation).As("i")).
+		Join(goqu.T(tableUser).As("u"), goqu.On(goqu.Ex{"u.id": goqu.I("i.user_id")})).
+		Select("i.id", "i.user_id", "i.token_hash", "i.invited_by", "i.expires_at", "i.sent_at", "i.created_at", "u.email", "u.name", "u.role")
+}
+
+func scanInvitation(row rowScanner) (*model.Invitation, error) {
+	var i model.Invitation
+	err := row.Scan(&i.ID, &i.UserID, &i.TokenHash, &i.InvitedBy, &i.ExpiresAt, &i.SentAt, &i.CreatedAt, &i.Email, &i.Name, &i.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
@@ -0,0 +1,95 @@
+package postgres_outbound_adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestInvitationAdapter(t *testing.T) {
+	Convey("Test Postgres Invitation Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewInvitationAdapter(db)
+
+		now := time.Now()
+		columns := []string{"id", "user_id", "token_hash", "invited_by", "expires_at", "sent_at", "created_at", "email", "name", "role"}
+
+		Convey("Create", func() {
+			admin := "admin-1"
+			mock.ExpectExec("INSERT INTO \"invitations\" .*'admin-1'").WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.Create(&model.Invitation{
+				ID: "i", UserID: "u", TokenHash: "hash", InvitedBy: &admin, ExpiresAt: now, SentAt: now, CreatedAt: now,
+			})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("FindByHash", func() {
+			Convey("Success joins the invited user", func() {
+				rows := sqlmock.NewRows(columns).AddRow("i", "u", "hash", nil, now, now, now, "new@example.com", "New", "support")
+				mock.ExpectQuery("FROM \"invitations\" AS \"i\" INNER JOIN \"users\" AS \"u\" .*\"i\".\"token_hash\" = 'hash'").WillReturnRows(rows)
+
+				invitation, err := adapter.FindByHash("hash")
+				So(err, ShouldBeNil)
+				So(invitation.Email, ShouldEqual, "new@example.com")
+				So(invitation.Role, ShouldEqual, "support")
+				So(invitation.InvitedBy, ShouldBeNil)
+			})
+
+			Convey("Not found", func() {
+				mock.ExpectQuery("FROM \"invitations\"").WillReturnRows(sqlmock.NewRows(columns))
+
+				invitation, err := adapter.FindByHash("hash")
+				So(err, ShouldBeNil)
+				So(invitation, ShouldBeNil)
+			})
+		})
+
+		Convey("FindAll", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("i1", "u1", "h1", "admin-1", now, now, now, "a@example.com", "A", "user").
+				AddRow("i2", "u2", "h2", nil, now, now, now, "b@example.com", "B", "user")
+			mock.ExpectQuery("FROM \"invitations\" .*ORDER BY \"i\".\"created_at\" DESC").WillReturnRows(rows)
+
+			invitations, err := adapter.FindAll()
+			So(err, ShouldBeNil)
+			So(len(invitations), ShouldEqual, 2)
+			So(*invitations[0].InvitedBy, ShouldEqual, "admin-1")
+		})
+
+		Convey("Renew replaces the token", func() {
+			mock.ExpectExec("UPDATE \"invitations\" SET .*\"token_hash\"='new-hash'.*WHERE \\(\"id\" = 'i'\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+
+			So(adapter.Renew("i", "new-hash", now, now), ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Consume", func() {
+			Convey("Token still held", func() {
+				mock.ExpectExec("DELETE FROM \"invitations\" WHERE .*\"id\" = 'i'.*\"token_hash\" = 'hash'").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				consumed, err := adapter.Consume("i", "hash")
+				So(err, ShouldBeNil)
+				So(consumed, ShouldBeTrue)
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+
+			Convey("Already consumed or resent", func() {
+				mock.ExpectExec("DELETE FROM \"invitations\"").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				consumed, err := adapter.Consume("i", "hash")
+				So(err, ShouldBeNil)
+				So(consumed, ShouldBeFalse)
+			})
+		})
+	})
+}
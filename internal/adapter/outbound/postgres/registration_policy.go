@@ -0,0 +1,78 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const (
+	tableRegistrationPolicy = "registration_policy"
+	// The table holds a single row
+	registrationPolicyID = 1
+)
+
+type registrationPolicyAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewRegistrationPolicyAdapter(db outbound_port.DatabaseExecutor) outbound_port.RegistrationPolicyDatabasePort {
+	return &registrationPolicyAdapter{db: db}
+}
+
+func (a *registrationPolicyAdapter) Get() (*model.RegistrationPolicy, error) {
+	ds := goqu.Dialect("postgres").From(tableRegistrationPolicy).
+		Select("mode", "allowed_domains", "denied_domains", "updated_by", "updated_at").
+		Where(goqu.Ex{"id": registrationPolicyID})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var p model.RegistrationPolicy
+	var allowed, denied string
+	err = a.db.QueryRow(query).Scan(&p.Mode, &allowed, &denied, &p.UpdatedBy, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.AllowedDomains = splitList(allowed)
+	p.DeniedDomains = splitList(denied)
+	return &p, nil
+}
+
+func (a *registrationPolicyAdapter) Save(policy *model.RegistrationPolicy) error {
+	ds := goqu.Dialect("postgres").Insert(tableRegistrationPolicy).Rows(
+		goqu.Record{
+			"id":              registrationPolicyID,
+			"mode":            policy.Mode,
+			"allowed_domains": strings.Join(policy.AllowedDomains, ","),
+			"denied_domains":  strings.Join(policy.DeniedDomains, ","),
+			"updated_by":      policy.UpdatedBy,
+			"updated_at":      policy.UpdatedAt,
+		},
+	)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	query += ` ON CONFLICT (id) DO UPDATE SET mode = EXCLUDED.mode, allowed_domains = EXCLUDED.allowed_domains, denied_domains = EXCLUDED.denied_domains, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,62 @@
+package postgres_outbound_adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestRegistrationPolicyAdapter(t *testing.T) {
+	Convey("Test Postgres Registration Policy Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewRegistrationPolicyAdapter(db)
+
+		now := time.Now()
+		columns := []string{"mode", "allowed_domains", "denied_domains", "updated_by", "updated_at"}
+
+		Convey("Get", func() {
+			Convey("Success splits the domain lists", func() {
+				rows := sqlmock.NewRows(columns).AddRow("restricted", "example.com,example.org", "", "admin-1", now)
+				mock.ExpectQuery("FROM \"registration_policy\" WHERE \\(\"id\" = 1\\)").WillReturnRows(rows)
+
+				policy, err := adapter.Get()
+				So(err, ShouldBeNil)
+				So(policy.Mode, ShouldEqual, model.RegistrationModeRestricted)
+				So(policy.AllowedDomains, ShouldResemble, []string{"example.com", "example.org"})
+				So(policy.DeniedDomains, ShouldNotBeNil)
+				So(policy.DeniedDomains, ShouldBeEmpty)
+				So(*policy.UpdatedBy, ShouldEqual, "admin-1")
+			})
+
+			Convey("Never saved", func() {
+				mock.ExpectQuery("FROM \"registration_policy\"").WillReturnRows(sqlmock.NewRows(columns))
+
+				policy, err := adapter.Get()
+				So(err, ShouldBeNil)
+				So(policy, ShouldBeNil)
+			})
+		})
+
+		Convey("Save upserts the single row", func() {
+			mock.ExpectExec("INSERT INTO \"registration_policy\" .*'example.com,example.org'.* ON CONFLICT \\(id\\) DO UPDATE").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.Save(&model.RegistrationPolicy{
+				Mode:           model.RegistrationModeRestricted,
+				AllowedDomains: []string{"example.com", "example.org"},
+				DeniedDomains:  []string{},
+				UpdatedAt:      &now,
+			})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
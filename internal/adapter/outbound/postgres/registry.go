@@ -83,4 +83,52 @@ func (s *adapter) Token() outbound_port.TokenDatabasePort {
 		return NewTokenAdapter(s.dbexecutor)
 	}
 	return NewTokenAdapter(s.db)
-}
\ No newline at end of file
+}
+
+func (s *adapter) TwoFactor() outbound_port.TwoFactorDatabasePort {
+	if s.dbexecutor != nil {
+		return NewTwoFactorAdapter(s.dbexecutor)
+	}
+	return NewTwoFactorAdapter(s.db)
+}
+func (s *adapter) Identity() outbound_port.IdentityDatabasePort {
+	if s.dbexecutor != nil {
+		return NewIdentityAdapter(s.dbexecutor)
+	}
+	return NewIdentityAdapter(s.db)
+}
+
+func (s *adapter) AccessToken() outbound_port.AccessTokenDatabasePort {
+	if s.dbexecutor != nil {
+		return NewAccessTokenAdapter(s.dbexecutor)
+	}
+	return NewAccessTokenAdapter(s.db)
+}
+
+func (s *adapter) Role() outbound_port.RoleDatabasePort {
+	if s.dbexecutor != nil {
+		return NewRoleAdapter(s.dbexecutor)
+	}
+	return NewRoleAdapter(s.db)
+}
+
+func (s *adapter) Invitation() outbound_port.InvitationDatabasePort {
+	if s.dbexecutor != nil {
+		return NewInvitationAdapter(s.dbexecutor)
+	}
+	return NewInvitationAdapter(s.db)
+}
+
+
+func (s *adapter) RegistrationPolicy() outbound_port.RegistrationPolicyDatabasePort {
+	if s.dbexecutor != nil {
+		return NewRegistrationPolicyAdapter(s.dbexecutor)
+	}
+	return NewRegistrationPolicyAdapter(s.db)
+}
+func (s *adapter) Audit() outbound_port.AuditDatabasePort {
+	if s.dbexecutor != nil {
+		return NewAuditAdapter(s.dbexecutor)
+	}
+	return NewAuditAdapter(s.db)
+}
@@ -0,0 +1,247 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const (
+	tableRole           = "roles"
+	tablePermission     = "permissions"
+	tableRolePermission = "role_permissions"
+)
+
+type roleAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewRoleAdapter(db outbound_port.DatabaseExecutor) outbound_port.RoleDatabasePort {
+	return &roleAdapter{db: db}
+}
+
+func (a *roleAdapter) FindAll() ([]model.Role, error) {
+	ds := goqu.Dialect("postgres").From(tableRole).Order(goqu.I("name").Asc())
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []model.Role{}
+	for rows.Next() {
+		var r model.Role
+		if err := rows.Scan(&r.Name, &r.Description, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	permissions, err := a.permissionsByRole(nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		roles[i].Permissions = permissions[roles[i].Name]
+		if roles[i].Permissions == nil {
+			roles[i].Permissions = []string{}
+		}
+	}
+	return roles, nil
+}
+
+func (a *roleAdapter) FindByName(name string) (*model.Role, error) {
+	ds := goqu.Dialect("postgres").From(tableRole).Where(goqu.Ex{"name": name})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var r model.Role
+	err = a.db.QueryRow(query).Scan(&r.Name, &r.Description, &r.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := a.permissionsByRole(goqu.Ex{"role": name})
+	if err != nil {
+		return nil, err
+	}
+	r.Permissions = permissions[name]
+	if r.Permissions == nil {
+		r.Permissions = []string{}
+	}
+	return &r, nil
+}
+
+func (a *roleAdapter) Create(role *model.Role) error {
+	ds := goqu.Dialect("postgres").Insert(tableRole).Rows(
+		goqu.Record{
+			"name":        role.Name,
+			"description": role.Description,
+			"created_at":  role.CreatedAt,
+		},
+	)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err = a.db.Exec(query); err != nil {
+		return err
+	}
+
+	return a.SetPermissions(role.Name, role.Permissions)
+}
+
+func (a *roleAdapter) Update(role *model.Role) error {
+	ds := goqu.Dialect("postgres").Update(tableRole).
+		Set(goqu.Record{"description": role.Description}).
+		Where(goqu.Ex{"name": role.Name})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *roleAdapter) Delete(name string) error {
+	ds := goqu.Dialect("postgres").Delete(tableRole).Where(goqu.Ex{"name": name})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *roleAdapter) SetPermissions(role string, permissions []string) error {
+	ds := goqu.Dialect("postgres").Delete(tableRolePermission).Where(goqu.Ex{"role": role})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err = a.db.Exec(query); err != nil {
+		return err
+	}
+
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, 0, len(permissions))
+	for _, permission := range permissions {
+		rows = append(rows, goqu.Record{
+			"role":       role,
+			"permission": permission,
+		})
+	}
+
+	query, _, err = goqu.Dialect("postgres").Insert(tableRolePermission).Rows(rows...).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *roleAdapter) FindPermissions() ([]model.Permission, error) {
+	ds := goqu.Dialect("postgres").From(tablePermission).Order(goqu.I("name").Asc())
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []model.Permission{}
+	for rows.Next() {
+		var p model.Permission
+		if err := rows.Scan(&p.Name, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+func (a *roleAdapter) HasPermission(role, permission string) (bool, error) {
+	ds := goqu.Dialect("postgres").From(tableRolePermission).Select(goqu.L("1")).
+		Where(goqu.Ex{"role": role, "permission": permission})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	var exists int
+	err = a.db.QueryRow(query).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *roleAdapter) CountUsers(role string) (int64, error) {
+	ds := goqu.Dialect("postgres").From(tableUser).Select(goqu.COUNT("*")).Where(goqu.Ex{"role": role})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = a.db.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// permissionsByRole groups role_permissions rows, optionally filtered
+func (a *roleAdapter) permissionsByRole(filter goqu.Ex) (map[string][]string, error) {
+	ds := goqu.Dialect("postgres").From(tableRolePermission).Select("role", "permission").
+		Order(goqu.I("permission").Asc())
+	if filter != nil {
+		ds = ds.Where(filter)
+	}
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[string][]string{}
+	for rows.Next() {
+		var role, permission string
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, err
+		}
+		result[role] = append(result[role], permission)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,79 @@
+package postgres_outbound_adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestRoleAdapter(t *testing.T) {
+	Convey("Test Postgres Role Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewRoleAdapter(db)
+
+		now := time.Now()
+		columns := []string{"name", "description", "created_at"}
+
+		Convey("FindAll attaches the permissions", func() {
+			mock.ExpectQuery("SELECT \\* FROM \"roles\"").WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("admin", "Administrator", now).
+				AddRow("support", "Help desk", now))
+			mock.ExpectQuery("SELECT \"role\", \"permission\" FROM \"role_permissions\"").WillReturnRows(
+				sqlmock.NewRows([]string{"role", "permission"}).
+					AddRow("admin", "users:read").
+					AddRow("admin", "users:write"))
+
+			roles, err := adapter.FindAll()
+			So(err, ShouldBeNil)
+			So(roles, ShouldHaveLength, 2)
+			So(roles[0].Permissions, ShouldResemble, []string{model.PermissionUsersRead, model.PermissionUsersWrite})
+			So(roles[1].Permissions, ShouldBeEmpty)
+		})
+
+		Convey("FindByName", func() {
+			Convey("Not found", func() {
+				mock.ExpectQuery("SELECT \\* FROM \"roles\"").WillReturnRows(sqlmock.NewRows(columns))
+
+				role, err := adapter.FindByName("ghost")
+				So(err, ShouldBeNil)
+				So(role, ShouldBeNil)
+			})
+		})
+
+		Convey("Create stores the permissions", func() {
+			mock.ExpectExec("INSERT INTO \"roles\"").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("DELETE FROM \"role_permissions\"").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("INSERT INTO \"role_permissions\" .*'users:read', 'support'").WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.Create(&model.Role{Name: "support", Permissions: []string{model.PermissionUsersRead}, CreatedAt: now})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("HasPermission", func() {
+			Convey("Granted", func() {
+				mock.ExpectQuery("SELECT 1 FROM \"role_permissions\"").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+				granted, err := adapter.HasPermission("support", model.PermissionUsersRead)
+				So(err, ShouldBeNil)
+				So(granted, ShouldBeTrue)
+			})
+
+			Convey("Not granted", func() {
+				mock.ExpectQuery("SELECT 1 FROM \"role_permissions\"").WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+
+				granted, err := adapter.HasPermission("support", model.PermissionUsersWrite)
+				So(err, ShouldBeNil)
+				So(granted, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -2,6 +2,7 @@ package postgres_outbound_adapter
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
@@ -30,6 +31,9 @@ func (a *tokenAdapter) Create(token *model.Token) error {
 			"expires":     token.Expires,
 			"blacklisted": token.Blacklisted,
 			"created_at":  token.CreatedAt,
+			"family":      token.Family,
+			"ip":          token.IP,
+			"user_agent":  token.UserAgent,
 		},
 	)
 	query, _, err := ds.ToSQL()
@@ -48,23 +52,87 @@ func (a *tokenAdapter) FindByToken(tokenStr string, tokenType string) (*model.To
 			"type":        tokenType,
 			"blacklisted": false,
 		})
+	return a.fetchOne(ds)
+}
+
+func (a *tokenAdapter) FindByTokenIncludingBlacklisted(tokenStr string, tokenType string) (*model.Token, error) {
+	ds := goqu.Dialect("postgres").From(tableToken).
+		Where(goqu.Ex{
+			"token": tokenStr,
+			"type":  tokenType,
+		}).
+		Order(goqu.I("id").Desc()).
+		Limit(1)
+	return a.fetchOne(ds)
+}
+
+func (a *tokenAdapter) FindSessionsByUserID(userID string) ([]model.Session, error) {
+	ds := sessionDataset().
+		Where(goqu.Ex{"t.user_id": userID}).
+		Order(goqu.I("t.created_at").Desc())
 
 	query, _, err := ds.ToSQL()
 	if err != nil {
 		return nil, err
 	}
 
-	var t model.Token
-	err = a.db.QueryRow(query).Scan(
-		&t.ID, &t.Token, &t.UserID, &t.Type, &t.Expires, &t.Blacklisted, &t.CreatedAt,
-	)
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		var s model.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastUsedAt, &s.Expires); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (a *tokenAdapter) FindSessionByFamily(family string) (*model.Session, error) {
+	ds := sessionDataset().Where(goqu.Ex{"t.family": family}).Limit(1)
+
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var s model.Session
+	err = a.db.QueryRow(query).Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastUsedAt, &s.Expires)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &t, nil
+	return &s, nil
+}
+
+// sessionDataset selects the live refresh token of each family; the session started
+// when the first token of the family was issued
+func sessionDataset() *goqu.SelectDataset {
+	return goqu.Dialect("postgres").From(goqu.T(tableToken).As("t")).
+		Select(
+			goqu.I("t.family"),
+			goqu.I("t.user_id"),
+			goqu.I("t.ip"),
+			goqu.I("t.user_agent"),
+			goqu.L(`(SELECT MIN("f"."created_at") FROM "tokens" AS "f" WHERE "f"."family" = "t"."family")`).As("created_at"),
+			goqu.I("t.created_at").As("last_used_at"),
+			goqu.I("t.expires"),
+		).
+		Where(
+			goqu.Ex{
+				"t.type":        model.TokenTypeRefresh,
+				"t.blacklisted": false,
+			},
+			goqu.I("t.expires").Gt(goqu.L("NOW()")),
+		)
 }
 
 func (a *tokenAdapter) DeleteByUserIDAndType(userID string, tokenType string) error {
@@ -80,6 +148,16 @@ func (a *tokenAdapter) DeleteByUserIDAndType(userID string, tokenType string) er
 	return err
 }
 
+func (a *tokenAdapter) DeleteByFamily(family string) error {
+	ds := goqu.Dialect("postgres").Delete(tableToken).Where(goqu.Ex{"family": family})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
 func (a *tokenAdapter) Delete(tokenID int) error {
 	ds := goqu.Dialect("postgres").Delete(tableToken).Where(goqu.Ex{"id": tokenID})
 	query, _, err := ds.ToSQL()
@@ -88,4 +166,96 @@ func (a *tokenAdapter) Delete(tokenID int) error {
 	}
 	_, err = a.db.Exec(query)
 	return err
-}
\ No newline at end of file
+}
+
+func (a *tokenAdapter) Blacklist(tokenID int) (bool, error) {
+	ds := goqu.Dialect("postgres").Update(tableToken).
+		Set(goqu.Record{"blacklisted": true}).
+		Where(goqu.Ex{"id": tokenID, "blacklisted": false})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := a.db.Exec(query)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (a *tokenAdapter) BlacklistByFamily(family string) error {
+	ds := goqu.Dialect("postgres").Update(tableToken).
+		Set(goqu.Record{"blacklisted": true}).
+		Where(goqu.Ex{"family": family})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *tokenAdapter) PurgeExpired(now time.Time, limit int) (int, error) {
+	live := goqu.Dialect("postgres").From(goqu.T(tableToken).As("l")).
+		Select(goqu.L("1")).
+		Where(
+			goqu.I("l.family").Eq(goqu.I("t.family")),
+			goqu.Ex{
+				"l.type":        model.TokenTypeRefresh,
+				"l.blacklisted": false,
+			},
+			goqu.I("l.expires").Gt(now),
+		)
+
+	purgeable := goqu.Dialect("postgres").From(goqu.T(tableToken).As("t")).
+		Select(goqu.I("t.id")).
+		Where(
+			goqu.Or(
+				goqu.I("t.expires").Lte(now),
+				goqu.I("t.blacklisted").IsTrue(),
+			),
+			goqu.Or(
+				goqu.I("t.family").Eq(""),
+				goqu.L("NOT EXISTS ?", live),
+			),
+		).
+		Limit(uint(limit))
+
+	ds := goqu.Dialect("postgres").Delete(tableToken).Where(goqu.I("id").In(purgeable))
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := a.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// Helper to fetch single token
+func (a *tokenAdapter) fetchOne(ds *goqu.SelectDataset) (*model.Token, error) {
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var t model.Token
+	err = a.db.QueryRow(query).Scan(
+		&t.ID, &t.Token, &t.UserID, &t.Type, &t.Expires, &t.Blacklisted, &t.CreatedAt, &t.Family, &t.IP, &t.UserAgent,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
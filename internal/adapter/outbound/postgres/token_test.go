@@ -0,0 +1,154 @@
+package postgres_outbound_adapter_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestTokenAdapter(t *testing.T) {
+	Convey("Test Postgres Token Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewTokenAdapter(db)
+
+		now := time.Now()
+		columns := []string{"id", "token", "user_id", "type", "expires", "blacklisted", "created_at", "family", "ip", "user_agent"}
+		sessionColumns := []string{"family", "user_id", "ip", "user_agent", "created_at", "last_used_at", "expires"}
+
+		Convey("Create", func() {
+			mock.ExpectExec("INSERT INTO \"tokens\"").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err := adapter.Create(&model.Token{Token: "t", UserID: "u", Type: model.TokenTypeRefresh, Expires: now, CreatedAt: now, Family: "f"})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("FindByToken", func() {
+			Convey("Success", func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "t", "u", model.TokenTypeRefresh, now, false, now, "f", "127.0.0.1", "agent")
+
+				mock.ExpectQuery("SELECT \\* FROM \"tokens\"").
+					WillReturnRows(rows)
+
+				token, err := adapter.FindByToken("t", model.TokenTypeRefresh)
+				So(err, ShouldBeNil)
+				So(token, ShouldNotBeNil)
+				So(token.Family, ShouldEqual, "f")
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+
+			Convey("Not found", func() {
+				mock.ExpectQuery("SELECT \\* FROM \"tokens\"").
+					WillReturnRows(sqlmock.NewRows(columns))
+
+				token, err := adapter.FindByToken("t", model.TokenTypeRefresh)
+				So(err, ShouldBeNil)
+				So(token, ShouldBeNil)
+			})
+		})
+
+		Convey("FindByTokenIncludingBlacklisted", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow(1, "t", "u", model.TokenTypeRefresh, now, true, now, "f", "127.0.0.1", "agent")
+
+			mock.ExpectQuery("SELECT \\* FROM \"tokens\"").
+				WillReturnRows(rows)
+
+			token, err := adapter.FindByTokenIncludingBlacklisted("t", model.TokenTypeRefresh)
+			So(err, ShouldBeNil)
+			So(token.Blacklisted, ShouldBeTrue)
+		})
+
+		Convey("FindSessionsByUserID", func() {
+			rows := sqlmock.NewRows(sessionColumns).
+				AddRow("f", "u", "127.0.0.1", "agent", now, now, now)
+
+			mock.ExpectQuery("SELECT \"t\".\"family\", .* FROM \"tokens\" AS \"t\" WHERE").
+				WillReturnRows(rows)
+
+			sessions, err := adapter.FindSessionsByUserID("u")
+			So(err, ShouldBeNil)
+			So(len(sessions), ShouldEqual, 1)
+			So(sessions[0].ID, ShouldEqual, "f")
+			So(sessions[0].UserAgent, ShouldEqual, "agent")
+		})
+
+		Convey("FindSessionByFamily", func() {
+			mock.ExpectQuery("SELECT \"t\".\"family\", .* FROM \"tokens\" AS \"t\" WHERE").
+				WillReturnRows(sqlmock.NewRows(sessionColumns))
+
+			session, err := adapter.FindSessionByFamily("f")
+			So(err, ShouldBeNil)
+			So(session, ShouldBeNil)
+		})
+
+		Convey("Blacklist", func() {
+			Convey("Live token", func() {
+				mock.ExpectExec("UPDATE \"tokens\" SET \"blacklisted\"=TRUE WHERE .*\"blacklisted\" IS FALSE").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				rotated, err := adapter.Blacklist(1)
+				So(err, ShouldBeNil)
+				So(rotated, ShouldBeTrue)
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+
+			Convey("Already rotated", func() {
+				mock.ExpectExec("UPDATE \"tokens\" SET \"blacklisted\"=TRUE").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				rotated, err := adapter.Blacklist(1)
+				So(err, ShouldBeNil)
+				So(rotated, ShouldBeFalse)
+			})
+		})
+
+		Convey("BlacklistByFamily", func() {
+			mock.ExpectExec("UPDATE \"tokens\" SET \"blacklisted\"=TRUE WHERE \\(\"family\" = 'f'\\)").
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			err := adapter.BlacklistByFamily("f")
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("DeleteByFamily", func() {
+			mock.ExpectExec("DELETE FROM \"tokens\"").
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			err := adapter.DeleteByFamily("f")
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("PurgeExpired", func() {
+			Convey("Spares families with a live refresh token and reports the count", func() {
+				mock.ExpectExec("DELETE FROM \"tokens\" WHERE \\(\"id\" IN .*\"t\".\"blacklisted\" IS TRUE.*NOT EXISTS \\(SELECT 1 FROM \"tokens\" AS \"l\".* LIMIT 500").
+					WillReturnResult(sqlmock.NewResult(0, 42))
+
+				purged, err := adapter.PurgeExpired(now, 500)
+				So(err, ShouldBeNil)
+				So(purged, ShouldEqual, 42)
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+
+			Convey("Error", func() {
+				mock.ExpectExec("DELETE FROM \"tokens\"").WillReturnError(sql.ErrConnDone)
+
+				_, err := adapter.PurgeExpired(now, 500)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+const (
+	tableTwoFactor    = "user_two_factors"
+	tableRecoveryCode = "user_recovery_codes"
+)
+
+type twoFactorAdapter struct {
+	db outbound_port.DatabaseExecutor
+}
+
+func NewTwoFactorAdapter(db outbound_port.DatabaseExecutor) outbound_port.TwoFactorDatabasePort {
+	return &twoFactorAdapter{db: db}
+}
+
+func (a *twoFactorAdapter) FindByUserID(userID string) (*model.TwoFactor, error) {
+	ds := goqu.Dialect("postgres").From(tableTwoFactor).Where(goqu.Ex{"user_id": userID})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var t model.TwoFactor
+	err = a.db.QueryRow(query).Scan(
+		&t.UserID, &t.Secret, &t.Enabled, &t.LastUsedStep, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (a *twoFactorAdapter) Upsert(twoFactor *model.TwoFactor) error {
+	ds := goqu.Dialect("postgres").Insert(tableTwoFactor).Rows(twoFactor)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	query += ` ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled, last_used_step = EXCLUDED.last_used_step, updated_at = EXCLUDED.updated_at`
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *twoFactorAdapter) UseStep(userID string, step int64) (bool, error) {
+	// Conditional so two requests racing with the same code cannot both get through
+	ds := goqu.Dialect("postgres").Update(tableTwoFactor).
+		Set(goqu.Record{"last_used_step": step, "updated_at": time.Now()}).
+		Where(
+			goqu.Ex{"user_id": userID},
+			goqu.C("last_used_step").Lt(step),
+		)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := a.db.Exec(query)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (a *twoFactorAdapter) DeleteByUserID(userID string) error {
+	ds := goqu.Dialect("postgres").Delete(tableRecoveryCode).Where(goqu.Ex{"user_id": userID})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err = a.db.Exec(query); err != nil {
+		return err
+	}
+
+	ds = goqu.Dialect("postgres").Delete(tableTwoFactor).Where(goqu.Ex{"user_id": userID})
+	query, _, err = ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *twoFactorAdapter) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	ds := goqu.Dialect("postgres").Delete(tableRecoveryCode).Where(goqu.Ex{"user_id": userID})
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err = a.db.Exec(query); err != nil {
+		return err
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]interface{}, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		rows = append(rows, goqu.Record{
+			"user_id":    userID,
+			"code_hash":  hash,
+			"created_at": now,
+		})
+	}
+
+	query, _, err = goqu.Dialect("postgres").Insert(tableRecoveryCode).Rows(rows...).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *twoFactorAdapter) UseRecoveryCode(userID string, codeHash string) (bool, error) {
+	ds := goqu.Dialect("postgres").Update(tableRecoveryCode).
+		Set(goqu.Record{"used_at": time.Now()}).
+		Where(
+			goqu.Ex{"user_id": userID, "code_hash": codeHash},
+			goqu.C("used_at").IsNull(),
+		)
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := a.db.Exec(query)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
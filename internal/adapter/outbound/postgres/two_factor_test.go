@@ -0,0 +1,99 @@
+package postgres_outbound_adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestTwoFactorAdapter(t *testing.T) {
+	Convey("Test Postgres Two-Factor Adapter", t, func() {
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewTwoFactorAdapter(db)
+
+		now := time.Now()
+		columns := []string{"user_id", "secret", "enabled", "last_used_step", "created_at", "updated_at"}
+
+		Convey("FindByUserID", func() {
+			Convey("Success", func() {
+				rows := sqlmock.NewRows(columns).AddRow("u", "SECRET", true, 42, now, now)
+				mock.ExpectQuery("SELECT \\* FROM \"user_two_factors\"").WillReturnRows(rows)
+
+				twoFactor, err := adapter.FindByUserID("u")
+				So(err, ShouldBeNil)
+				So(twoFactor.Enabled, ShouldBeTrue)
+				So(twoFactor.LastUsedStep, ShouldEqual, 42)
+			})
+
+			Convey("Not found", func() {
+				mock.ExpectQuery("SELECT \\* FROM \"user_two_factors\"").WillReturnRows(sqlmock.NewRows(columns))
+
+				twoFactor, err := adapter.FindByUserID("u")
+				So(err, ShouldBeNil)
+				So(twoFactor, ShouldBeNil)
+			})
+		})
+
+		Convey("Upsert", func() {
+			mock.ExpectExec("INSERT INTO \"user_two_factors\" .* ON CONFLICT \\(user_id\\) DO UPDATE").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.Upsert(&model.TwoFactor{UserID: "u", Secret: "SECRET", CreatedAt: now, UpdatedAt: now})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("ReplaceRecoveryCodes", func() {
+			mock.ExpectExec("DELETE FROM \"user_recovery_codes\"").WillReturnResult(sqlmock.NewResult(0, 10))
+			mock.ExpectExec("INSERT INTO \"user_recovery_codes\"").WillReturnResult(sqlmock.NewResult(0, 2))
+
+			err := adapter.ReplaceRecoveryCodes("u", []string{"a", "b"})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("UseStep", func() {
+			Convey("Newer step", func() {
+				mock.ExpectExec("UPDATE \"user_two_factors\" .*\"last_used_step\" < 42").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				used, err := adapter.UseStep("u", 42)
+				So(err, ShouldBeNil)
+				So(used, ShouldBeTrue)
+			})
+
+			Convey("Step already used", func() {
+				mock.ExpectExec("UPDATE \"user_two_factors\"").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				used, err := adapter.UseStep("u", 42)
+				So(err, ShouldBeNil)
+				So(used, ShouldBeFalse)
+			})
+		})
+
+		Convey("UseRecoveryCode", func() {
+			Convey("Unused code", func() {
+				mock.ExpectExec("UPDATE \"user_recovery_codes\" .* IS NULL").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				used, err := adapter.UseRecoveryCode("u", "a")
+				So(err, ShouldBeNil)
+				So(used, ShouldBeTrue)
+			})
+
+			Convey("Already used code", func() {
+				mock.ExpectExec("UPDATE \"user_recovery_codes\"").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				used, err := adapter.UseRecoveryCode("u", "a")
+				So(err, ShouldBeNil)
+				So(used, ShouldBeFalse)
+			})
+		})
+	})
+}
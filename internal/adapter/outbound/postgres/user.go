@@ -3,6 +3,7 @@ package postgres_outbound_adapter
 import (
 	"database/sql"
 	"strings"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
@@ -147,7 +148,7 @@ func (a *userAdapter) FindAll(filters model.UserFilter, page, limit int, sort st
 	var users []model.User
 	for rows.Next() {
 		var u model.User
-		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.IsEmailVerified, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.IsEmailVerified, &u.CreatedAt, &u.UpdatedAt, &u.Passwordless, &u.Pending, &u.DeletionScheduledAt); err != nil {
 			return nil, 0, err
 		}
 		users = append(users, u)
@@ -156,6 +157,33 @@ func (a *userAdapter) FindAll(filters model.UserFilter, page, limit int, sort st
 	return users, total, nil
 }
 
+func (a *userAdapter) FindDeletionDue(now time.Time, limit int) ([]model.User, error) {
+	ds := goqu.Dialect("postgres").From(tableUser).
+		Where(goqu.C("deletion_scheduled_at").Lte(now)).
+		Order(goqu.I("deletion_scheduled_at").Asc()).
+		Limit(uint(limit))
+	query, _, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.IsEmailVerified, &u.CreatedAt, &u.UpdatedAt, &u.Passwordless, &u.Pending, &u.DeletionScheduledAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
 // Helper to fetch single user
 func (a *userAdapter) fetchOne(ds *goqu.SelectDataset) (*model.User, error) {
 	query, _, err := ds.ToSQL()
@@ -165,7 +193,7 @@ func (a *userAdapter) fetchOne(ds *goqu.SelectDataset) (*model.User, error) {
 
 	var u model.User
 	err = a.db.QueryRow(query).Scan(
-		&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.IsEmailVerified, &u.CreatedAt, &u.UpdatedAt,
+		&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.IsEmailVerified, &u.CreatedAt, &u.UpdatedAt, &u.Passwordless, &u.Pending, &u.DeletionScheduledAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil // Return nil if not found (Domain layer handles 404)
@@ -39,3 +39,7 @@ func (adapter *clientAdapter) Get(bearerKey string) (model.Client, error) {
 
 	return client, nil
 }
+
+func (adapter *clientAdapter) Delete(bearerKey string) error {
+	return redis.Del(context.Background(), bearerKey)
+}
@@ -0,0 +1,73 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/redis"
+)
+
+const (
+	loginFailurePrefix = "login:failures:"
+	loginLockPrefix    = "login:lock:"
+)
+
+type loginAttemptAdapter struct{}
+
+func NewLoginAttemptAdapter() outbound_port.LoginAttemptCachePort {
+	return &loginAttemptAdapter{}
+}
+
+func (adapter *loginAttemptAdapter) RegisterFailure(key string, window time.Duration) (int64, error) {
+	return redis.IncrWithTTL(context.Background(), loginFailurePrefix+key, window)
+}
+
+func (adapter *loginAttemptAdapter) Lock(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return redis.SetWithTTL(context.Background(), loginLockPrefix+key, "1", ttl)
+}
+
+func (adapter *loginAttemptAdapter) LockedFor(key string) (time.Duration, error) {
+	ttl, err := redis.TTL(context.Background(), loginLockPrefix+key)
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (adapter *loginAttemptAdapter) Reset(key string) error {
+	ctx := context.Background()
+	if err := redis.Del(ctx, loginFailurePrefix+key); err != nil {
+		return err
+	}
+	return redis.Del(ctx, loginLockPrefix+key)
+}
+
+func (adapter *loginAttemptAdapter) Locked(prefix string) (map[string]time.Duration, error) {
+	ctx := context.Background()
+	keys, err := redis.ScanKeys(ctx, loginLockPrefix+prefix+"*")
+	if err != nil {
+		return nil, err
+	}
+
+	locked := map[string]time.Duration{}
+	for _, key := range keys {
+		ttl, err := redis.TTL(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		// Expired between the scan and now
+		if ttl <= 0 {
+			continue
+		}
+		locked[strings.TrimPrefix(key, loginLockPrefix)] = ttl
+	}
+	return locked, nil
+}
@@ -0,0 +1,21 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/redis"
+)
+
+const noncePrefix = "signature:nonce:"
+
+type nonceAdapter struct{}
+
+func NewNonceAdapter() outbound_port.NonceCachePort {
+	return &nonceAdapter{}
+}
+
+func (adapter *nonceAdapter) Claim(nonce string, ttl time.Duration) (bool, error) {
+	return redis.SetNX(context.Background(), noncePrefix+nonce, "1", ttl)
+}
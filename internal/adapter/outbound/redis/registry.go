@@ -14,3 +14,15 @@ func NewAdapter() outbound_port.CachePort {
 func (s *adapter) Client() outbound_port.ClientCachePort {
 	return NewClientAdapter()
 }
+
+func (s *adapter) Revocation() outbound_port.RevocationCachePort {
+	return NewRevocationAdapter()
+}
+
+func (s *adapter) LoginAttempt() outbound_port.LoginAttemptCachePort {
+	return NewLoginAttemptAdapter()
+}
+
+func (s *adapter) Nonce() outbound_port.NonceCachePort {
+	return NewNonceAdapter()
+}
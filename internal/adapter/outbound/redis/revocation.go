@@ -0,0 +1,53 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/redis"
+)
+
+const (
+	revokedTokenPrefix   = "revoked:jti:"
+	revokedSessionPrefix = "revoked:sid:"
+)
+
+type revocationAdapter struct{}
+
+func NewRevocationAdapter() outbound_port.RevocationCachePort {
+	return &revocationAdapter{}
+}
+
+func (adapter *revocationAdapter) RevokeToken(tokenID string, ttl time.Duration) error {
+	if tokenID == "" || ttl <= 0 {
+		return nil
+	}
+	return redis.SetWithTTL(context.Background(), revokedTokenPrefix+tokenID, "1", ttl)
+}
+
+func (adapter *revocationAdapter) RevokeSession(sessionID string, ttl time.Duration) error {
+	if sessionID == "" || ttl <= 0 {
+		return nil
+	}
+	return redis.SetWithTTL(context.Background(), revokedSessionPrefix+sessionID, "1", ttl)
+}
+
+func (adapter *revocationAdapter) IsRevoked(tokenID string, sessionID string) (bool, error) {
+	keys := []string{}
+	if tokenID != "" {
+		keys = append(keys, revokedTokenPrefix+tokenID)
+	}
+	if sessionID != "" {
+		keys = append(keys, revokedSessionPrefix+sessionID)
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	count, err := redis.Exists(context.Background(), keys...)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,6 +16,7 @@ import (
 	command_inbound_adapter "prabogo/internal/adapter/inbound/command"
 	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
 	rabbitmq_inbound_adapter "prabogo/internal/adapter/inbound/rabbitmq"
+	ldap_outbound_adapter "prabogo/internal/adapter/outbound/ldap"
 	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
 	rabbitmq_outbound_adapter "prabogo/internal/adapter/outbound/rabbitmq"
 	redis_outbound_adapter "prabogo/internal/adapter/outbound/redis"
@@ -30,6 +32,9 @@ import (
 	smtp_outbound_adapter "prabogo/internal/adapter/outbound/smtp"
 )
 
+// deletionPurgeBatchSize bounds how many accounts the deletion sweeper loads per query
+const deletionPurgeBatchSize = 100
+
 var databaseDriverList = []string{"postgres"}
 var httpDriverList = []string{"fiber"}
 var messageDriverList = []string{"rabbitmq"}
@@ -62,6 +67,7 @@ func NewApp() *App {
 		messageOutbound(ctx),
 		cacheOutbound(ctx),
 		emailAdapter,
+		ldap_outbound_adapter.NewAdapter(),
 	)
 
 	return &App{
@@ -144,6 +150,11 @@ func (a *App) httpInbound() {
 		}()
 	}
 
+	stopSweeper := make(chan struct{})
+	defer close(stopSweeper)
+	go a.tokenSweeper(stopSweeper)
+	go a.deletionSweeper(stopSweeper)
+
 	ctx, shutdown := context.WithTimeout(ctx, 5*time.Second)
 	defer shutdown()
 	quit := make(chan os.Signal, 1)
@@ -153,6 +164,49 @@ func (a *App) httpInbound() {
 	log.WithContext(ctx).Info("http server stopped")
 }
 
+// tokenSweeper purges expired and blacklisted tokens every TOKEN_PURGE_INTERVAL_MINUTES while
+// the http server runs; unset or 0 leaves it to the purge_tokens command
+func (a *App) tokenSweeper(stop <-chan struct{}) {
+	minutes, _ := strconv.Atoi(os.Getenv("TOKEN_PURGE_INTERVAL_MINUTES"))
+	a.sweep(stop, "token_sweeper", minutes, a.domain.Auth().PurgeTokens)
+}
+
+// deletionSweeper deletes the accounts whose deletion grace period is over every
+// DELETION_PURGE_INTERVAL_MINUTES, hourly when unset; 0 leaves it to the purge_deleted_users command
+func (a *App) deletionSweeper(stop <-chan struct{}) {
+	minutes := 60
+	if value, ok := os.LookupEnv("DELETION_PURGE_INTERVAL_MINUTES"); ok {
+		minutes, _ = strconv.Atoi(value)
+	}
+	a.sweep(stop, "deletion_sweeper", minutes, func(ctx context.Context) (int, error) {
+		return a.domain.User().PurgeScheduledDeletions(ctx, deletionPurgeBatchSize)
+	})
+}
+
+// sweep runs purge every given minutes until stop is closed, logging under action
+func (a *App) sweep(stop <-chan struct{}, action string, minutes int, purge func(ctx context.Context) (int, error)) {
+	if minutes <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(minutes) * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx := activity.NewContext(action)
+			purged, err := purge(ctx)
+			if err != nil {
+				log.WithContext(ctx).Errorf("%s error after %d rows: %s", action, purged, err.Error())
+				continue
+			}
+			log.WithContext(ctx).Infof("%s success: %d rows deleted", action, purged)
+		}
+	}
+}
+
 func (a *App) messageInbound() {
 	ctx := a.ctx
 	if !utils.IsInList(messageDriverList, inboundMessageDriver) {
@@ -0,0 +1,162 @@
+package accesstoken
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
+	"prabogo/utils/log"
+	"prabogo/utils/password"
+)
+
+const (
+	tokenBytes   = 32
+	prefixLength = len(model.PersonalAccessTokenPrefix) + 8
+	// Last use is recorded at most this often so busy scripts do not write on every call
+	touchInterval = time.Minute
+)
+
+type AccessTokenDomain interface {
+	// Create returns the stored token and, only this once, its plain value
+	Create(ctx context.Context, userID string, input model.PersonalAccessTokenInput) (*model.PersonalAccessToken, string, error)
+	List(ctx context.Context, userID string) ([]model.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, id string) error
+	// Authenticate resolves a presented token, rejecting unknown and expired ones
+	Authenticate(ctx context.Context, token string) (*model.PersonalAccessToken, error)
+}
+
+type accessTokenDomain struct {
+	db outbound_port.DatabasePort
+}
+
+func NewAccessTokenDomain(db outbound_port.DatabasePort) AccessTokenDomain {
+	return &accessTokenDomain{
+		db: db,
+	}
+}
+
+func (d *accessTokenDomain) Create(ctx context.Context, userID string, input model.PersonalAccessTokenInput) (*model.PersonalAccessToken, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", stacktrace.NewError("name is required")
+	}
+	if len(name) > 255 {
+		return nil, "", stacktrace.NewError("name is too long")
+	}
+
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defaultDays, _ := strconv.Atoi(os.Getenv("PERSONAL_ACCESS_TOKEN_EXPIRATION_DAYS"))
+	if defaultDays == 0 {
+		defaultDays = 90
+	}
+	maxDays, _ := strconv.Atoi(os.Getenv("PERSONAL_ACCESS_TOKEN_MAX_EXPIRATION_DAYS"))
+	if maxDays == 0 {
+		maxDays = 365
+	}
+
+	days := input.ExpiresInDays
+	if days == 0 {
+		days = defaultDays
+	}
+	if days < 0 || days > maxDays {
+		return nil, "", stacktrace.NewError("expires_in_days must be between 1 and %d", maxDays)
+	}
+
+	raw := model.PersonalAccessTokenPrefix + utils.GenerateSecureToken(tokenBytes)
+	now := time.Now()
+	token := &model.PersonalAccessToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		TokenHash: password.HashToken(raw),
+		Prefix:    raw[:prefixLength],
+		Scopes:    scopes,
+		ExpiresAt: now.AddDate(0, 0, days),
+		CreatedAt: now,
+	}
+	if err := d.db.AccessToken().Create(token); err != nil {
+		return nil, "", stacktrace.Propagate(err, "save access token failed")
+	}
+
+	return token, raw, nil
+}
+
+func (d *accessTokenDomain) List(ctx context.Context, userID string) ([]model.PersonalAccessToken, error) {
+	tokens, err := d.db.AccessToken().FindByUserID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find access tokens failed")
+	}
+	return tokens, nil
+}
+
+func (d *accessTokenDomain) Revoke(ctx context.Context, userID, id string) error {
+	deleted, err := d.db.AccessToken().Delete(userID, id)
+	if err != nil {
+		return stacktrace.Propagate(err, "revoke access token failed")
+	}
+	if !deleted {
+		return stacktrace.NewError("access token not found")
+	}
+	return nil
+}
+
+func (d *accessTokenDomain) Authenticate(ctx context.Context, raw string) (*model.PersonalAccessToken, error) {
+	if !strings.HasPrefix(raw, model.PersonalAccessTokenPrefix) {
+		return nil, stacktrace.NewError("invalid access token")
+	}
+
+	repo := d.db.AccessToken()
+	token, err := repo.FindByHash(password.HashToken(raw))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find access token failed")
+	}
+	if token == nil {
+		return nil, stacktrace.NewError("invalid access token")
+	}
+
+	now := time.Now()
+	if !token.ExpiresAt.After(now) {
+		return nil, stacktrace.NewError("access token expired")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= touchInterval {
+		if err := repo.TouchLastUsed(token.ID, now); err != nil {
+			log.WithContext(ctx).Warnf("record access token use failed: %v", err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
+
+// normalizeScopes rejects unknown scopes and drops duplicates
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, stacktrace.NewError("at least one scope is required")
+	}
+
+	result := []string{}
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !utils.IsInList(model.AccessTokenScopes, scope) {
+			return nil, stacktrace.NewError("unknown scope %s", scope)
+		}
+		if !utils.IsInList(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
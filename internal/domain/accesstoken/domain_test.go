@@ -0,0 +1,137 @@
+package accesstoken_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/password"
+)
+
+func TestAccessToken(t *testing.T) {
+	Convey("Test Access Token", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+
+		accessTokenDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort)
+
+		Convey("Create", func() {
+			Convey("Unknown scope", func() {
+				_, _, err := accessTokenDomain.AccessToken().Create(context.Background(), "user-1", model.PersonalAccessTokenInput{
+					Name:   "ci",
+					Scopes: []string{"admin:everything"},
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Expiry beyond the maximum", func() {
+				_, _, err := accessTokenDomain.AccessToken().Create(context.Background(), "user-1", model.PersonalAccessTokenInput{
+					Name:          "ci",
+					Scopes:        []string{model.ScopeUsersRead},
+					ExpiresInDays: 1000,
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success stores only the hash", func() {
+				var stored *model.PersonalAccessToken
+				mockAccessTokenDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *model.PersonalAccessToken) error {
+					stored = token
+					return nil
+				}).Times(1)
+
+				token, raw, err := accessTokenDomain.AccessToken().Create(context.Background(), "user-1", model.PersonalAccessTokenInput{
+					Name:   "ci",
+					Scopes: []string{model.ScopeUsersRead, model.ScopeUsersRead},
+				})
+				So(err, ShouldBeNil)
+				So(strings.HasPrefix(raw, model.PersonalAccessTokenPrefix), ShouldBeTrue)
+				So(stored.TokenHash, ShouldEqual, password.HashToken(raw))
+				So(stored.TokenHash, ShouldNotContainSubstring, raw)
+				So(token.Scopes, ShouldResemble, []string{model.ScopeUsersRead})
+				So(token.Prefix, ShouldEqual, raw[:len(token.Prefix)])
+				So(token.ExpiresAt.Sub(time.Now()), ShouldBeGreaterThan, 89*24*time.Hour)
+			})
+		})
+
+		Convey("Revoke", func() {
+			Convey("Token of another user", func() {
+				mockAccessTokenDatabasePort.EXPECT().Delete("user-1", "token-1").Return(false, nil).Times(1)
+
+				err := accessTokenDomain.AccessToken().Revoke(context.Background(), "user-1", "token-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockAccessTokenDatabasePort.EXPECT().Delete("user-1", "token-1").Return(true, nil).Times(1)
+
+				err := accessTokenDomain.AccessToken().Revoke(context.Background(), "user-1", "token-1")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("Authenticate", func() {
+			raw := model.PersonalAccessTokenPrefix + "abc"
+			stored := &model.PersonalAccessToken{ID: "token-1", UserID: "user-1", Scopes: []string{model.ScopeUsersRead}, ExpiresAt: time.Now().Add(time.Hour)}
+
+			Convey("Unknown token", func() {
+				mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(nil, nil).Times(1)
+
+				_, err := accessTokenDomain.AccessToken().Authenticate(context.Background(), raw)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Expired token", func() {
+				stored.ExpiresAt = time.Now().Add(-time.Minute)
+				mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+
+				_, err := accessTokenDomain.AccessToken().Authenticate(context.Background(), raw)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Records the first use", func() {
+				mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+				mockAccessTokenDatabasePort.EXPECT().TouchLastUsed("token-1", gomock.Any()).Return(nil).Times(1)
+
+				token, err := accessTokenDomain.AccessToken().Authenticate(context.Background(), raw)
+				So(err, ShouldBeNil)
+				So(token.LastUsedAt, ShouldNotBeNil)
+			})
+
+			Convey("Recent use is not written again", func() {
+				recent := time.Now().Add(-10 * time.Second)
+				stored.LastUsedAt = &recent
+				mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+
+				_, err := accessTokenDomain.AccessToken().Authenticate(context.Background(), raw)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Failing to record use does not reject the token", func() {
+				mockAccessTokenDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(stored, nil).Times(1)
+				mockAccessTokenDatabasePort.EXPECT().TouchLastUsed("token-1", gomock.Any()).Return(errors.New("error")).Times(1)
+
+				_, err := accessTokenDomain.AccessToken().Authenticate(context.Background(), raw)
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
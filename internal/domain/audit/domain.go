@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/activity"
+	"prabogo/utils/log"
+)
+
+type AuditDomain interface {
+	// Record stores action against userID with the caller's address and user agent, and the
+	// actor when someone else is acting on the account. A failure is only logged, the event it
+	// describes has already happened
+	Record(ctx context.Context, userID, action string)
+}
+
+type auditDomain struct {
+	db outbound_port.DatabasePort
+}
+
+func NewAuditDomain(db outbound_port.DatabasePort) AuditDomain {
+	return &auditDomain{
+		db: db,
+	}
+}
+
+func (d *auditDomain) Record(ctx context.Context, userID, action string) {
+	ip, _ := activity.GetIPAddress(ctx)
+	userAgent, _ := activity.GetUserAgent(ctx)
+
+	entry := &model.AuditEntry{
+		UserID:    userID,
+		Action:    action,
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+	if actorID, ok := activity.GetActorID(ctx); ok && actorID != "" && actorID != userID {
+		entry.ActorID = &actorID
+	}
+
+	if err := d.db.Audit().Create(entry); err != nil {
+		log.WithContext(ctx).Warnf("record %s audit entry failed: %v", action, err)
+	}
+}
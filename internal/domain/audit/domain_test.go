@@ -0,0 +1,64 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/audit"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/activity"
+)
+
+func TestAudit(t *testing.T) {
+	Convey("Test Audit", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockAuditDatabasePort := mock_outbound_port.NewMockAuditDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().Audit().Return(mockAuditDatabasePort).AnyTimes()
+
+		auditDomain := audit.NewAuditDomain(mockDatabasePort)
+
+		Convey("Record", func() {
+			Convey("Stores the caller's address and user agent", func() {
+				ctx := activity.WithUserAgent(activity.WithIPAddress(context.Background(), "10.0.0.1"), "test-agent")
+
+				mockAuditDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *model.AuditEntry) error {
+					So(entry.UserID, ShouldEqual, "user-1")
+					So(entry.Action, ShouldEqual, model.AuditActionSignIn)
+					So(entry.IP, ShouldEqual, "10.0.0.1")
+					So(entry.UserAgent, ShouldEqual, "test-agent")
+					So(entry.ActorID, ShouldBeNil)
+					return nil
+				}).Times(1)
+
+				auditDomain.Record(ctx, "user-1", model.AuditActionSignIn)
+			})
+
+			Convey("Stores the actor acting on the account", func() {
+				ctx := activity.WithActorID(context.Background(), "admin-1")
+
+				mockAuditDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *model.AuditEntry) error {
+					So(entry.ActorID, ShouldNotBeNil)
+					So(*entry.ActorID, ShouldEqual, "admin-1")
+					return nil
+				}).Times(1)
+
+				auditDomain.Record(ctx, "user-1", model.AuditActionImpersonated)
+			})
+
+			Convey("Database error is not reported to the caller", func() {
+				mockAuditDatabasePort.EXPECT().Create(gomock.Any()).Return(errors.New("error")).Times(1)
+
+				So(func() { auditDomain.Record(context.Background(), "user-1", model.AuditActionSignIn) }, ShouldNotPanic)
+			})
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/domain/audit"
+	"prabogo/internal/domain/session"
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+	"prabogo/utils/password"
+)
+
+// Passwordless accounts confirm a deletion by having signed in this recently
+const deletionReauthWindow = 10 * time.Minute
+
+// deletionGracePeriod reads ACCOUNT_DELETION_GRACE_DAYS, 30 days when unset
+func deletionGracePeriod() time.Duration {
+	days, _ := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"))
+	if days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// DeleteAccount schedules the caller's own account for deletion once the grace period is
+// over and signs it out everywhere, personal access tokens included. The password is asked again; passwordless and directory
+// accounts must have signed in moments ago instead. Signing back in before the date cancels the deletion.
+func (d *authDomain) DeleteAccount(ctx context.Context, userID, sessionID string, input model.DeleteAccountInput) (*model.User, error) {
+	userRepo := d.db.User()
+	user, err := userRepo.FindByID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+
+	sessions := session.NewSessionDomain(d.db, d.cache)
+	// The password is not ours to check for directory users either
+	if user.Passwordless || directoryManaged() {
+		current, err := sessions.Get(ctx, user.ID, sessionID)
+		if err != nil || time.Since(current.CreatedAt) > deletionReauthWindow {
+			return nil, stacktrace.NewError("sign in again to confirm the deletion")
+		}
+	} else if !password.CheckPassword(input.Password, user.Password) {
+		return nil, stacktrace.NewError("password is incorrect")
+	}
+
+	scheduled := time.Now().Add(deletionGracePeriod())
+	user.DeletionScheduledAt = &scheduled
+	user.UpdatedAt = time.Now()
+	if err := userRepo.Update(user); err != nil {
+		return nil, stacktrace.Propagate(err, "schedule deletion failed")
+	}
+
+	if err := sessions.RevokeAll(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	// Personal access tokens sign in too; they would otherwise keep working until the purge
+	if err := d.db.AccessToken().DeleteByUserID(user.ID); err != nil {
+		return nil, stacktrace.Propagate(err, "revoke access tokens failed")
+	}
+	log.WithContext(ctx).Infof("account deletion scheduled for %s", scheduled.Format(time.RFC3339))
+	audit.NewAuditDomain(d.db).Record(ctx, user.ID, model.AuditActionDeletionScheduled)
+
+	// The deletion is already scheduled, a mail failure should not report it as failed
+	body := fmt.Sprintf("Your account and its data will be deleted on %s. Sign in before then to cancel.", scheduled.Format(time.RFC1123))
+	if err := d.email.SendEmail(user.Email, "Account Deletion Scheduled", body); err != nil {
+		log.WithContext(ctx).Warnf("send deletion scheduled email failed: %v", err)
+	}
+	return user, nil
+}
+
+// cancelScheduledDeletion is how signing in during the grace period keeps the account. It only
+// runs once the sign in is complete, after the second factor when 2FA is on
+func (d *authDomain) cancelScheduledDeletion(ctx context.Context, user *model.User) error {
+	if user.DeletionScheduledAt == nil {
+		return nil
+	}
+
+	user.DeletionScheduledAt = nil
+	user.UpdatedAt = time.Now()
+	if err := d.db.User().Update(user); err != nil {
+		return stacktrace.Propagate(err, "cancel scheduled deletion failed")
+	}
+	log.WithContext(ctx).Info("scheduled account deletion cancelled by sign in")
+	audit.NewAuditDomain(d.db).Record(ctx, user.ID, model.AuditActionDeletionCancelled)
+
+	body := "You signed in, so your account is no longer scheduled for deletion."
+	if err := d.email.SendEmail(user.Email, "Account Deletion Cancelled", body); err != nil {
+		log.WithContext(ctx).Warnf("send deletion cancelled email failed: %v", err)
+	}
+	return nil
+}
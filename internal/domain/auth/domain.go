@@ -5,12 +5,20 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/palantir/stacktrace"
 
+	"prabogo/internal/domain/audit"
+	"prabogo/internal/domain/lockout"
+	"prabogo/internal/domain/registration"
+	"prabogo/internal/domain/session"
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/activity"
 	"prabogo/utils/jwt"
+	"prabogo/utils/log"
 	"prabogo/utils/password"
+	"prabogo/utils/signature"
 )
 
 type AuthDomain interface {
@@ -18,33 +26,105 @@ type AuthDomain interface {
 	Register(ctx context.Context, input model.UserInput) (*model.User, map[string]interface{}, error)
 	RefreshToken(ctx context.Context, refreshToken string) (map[string]interface{}, error)
 	Logout(ctx context.Context, refreshToken string) error
+	RevokeAccessToken(ctx context.Context, accessToken string) error
+	IsTokenRevoked(ctx context.Context, payload *jwt.TokenPayload) (bool, error)
 	
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
+	ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error
+
+	SendVerificationEmail(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) error
+
+	RequestMagicLink(ctx context.Context, email string) error
+	VerifyMagicLink(ctx context.Context, token string) (*model.User, map[string]interface{}, error)
+
+	EnrollTwoFactor(ctx context.Context, userID string) (*model.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	VerifyTwoFactorLogin(ctx context.Context, mfaToken, code string) (*model.User, map[string]interface{}, error)
+
+	ProvisionExternalUser(ctx context.Context, identity model.ExternalIdentity) (*model.User, error)
+
+	Impersonate(ctx context.Context, actorID, userID string) (map[string]interface{}, error)
+	// CheckImpersonation is run on every request made with an impersonation token
+	CheckImpersonation(ctx context.Context, token string, payload *jwt.TokenPayload) error
+	AcceptInvitation(ctx context.Context, input model.AcceptInvitationInput) (*model.User, map[string]interface{}, error)
+	DeleteAccount(ctx context.Context, userID, sessionID string, input model.DeleteAccountInput) (*model.User, error)
+	PurgeTokens(ctx context.Context) (int, error)
+
+	VerifySignedRequest(ctx context.Context, request signature.Request, sig string) error
 }
 
 type authDomain struct {
-	db    outbound_port.DatabasePort
-	email outbound_port.EmailPort
+	db        outbound_port.DatabasePort
+	cache     outbound_port.CachePort
+	email     outbound_port.EmailPort
+	directory outbound_port.DirectoryPort
 }
 
-func NewAuthDomain(db outbound_port.DatabasePort, email outbound_port.EmailPort) AuthDomain {
+func NewAuthDomain(db outbound_port.DatabasePort, cache outbound_port.CachePort, email outbound_port.EmailPort, directory outbound_port.DirectoryPort) AuthDomain {
 	return &authDomain{
-		db:    db,
-		email: email,
+		db:        db,
+		cache:     cache,
+		email:     email,
+		directory: directory,
 	}
 }
 
 func (d *authDomain) Login(ctx context.Context, email, pass string) (*model.User, map[string]interface{}, error) {
-	user, err := d.db.User().FindByEmail(email)
+	ip, _ := activity.GetIPAddress(ctx)
+	guard := lockout.NewLockoutDomain(d.db, d.cache)
+	if err := guard.Check(ctx, email, ip); err != nil {
+		return nil, nil, err
+	}
+
+	var user *model.User
+	var err error
+	if directoryManaged() {
+		user, err = d.checkDirectoryCredentials(ctx, email, pass)
+	} else {
+		user, err = d.checkLocalCredentials(email, pass)
+	}
 	if err != nil {
-		return nil, nil, stacktrace.Propagate(err, "db error")
+		return nil, nil, err
 	}
-	if user == nil || !password.CheckPassword(pass, user.Password) {
+	if user == nil {
+		if err := guard.RegisterFailure(ctx, email, ip); err != nil {
+			log.WithContext(ctx).Warnf("register login failure failed: %v", err)
+		}
 		return nil, nil, stacktrace.NewError("incorrect email or password")
 	}
 
-	tokens, err := d.generateAndSaveTokens(user.ID)
+	if err := guard.Reset(ctx, email); err != nil {
+		log.WithContext(ctx).Warnf("reset login failures failed: %v", err)
+	}
+
+	// Moves stored hashes to the configured algorithm and cost while the plain password is at
+	// hand; the directory keeps the passwords of LDAP users
+	if !directoryManaged() && password.NeedsRehash(user.Password) {
+		d.rehashPassword(ctx, user, pass)
+	}
+
+	// With 2FA on, the password only earns a short-lived token for /2fa/verify
+	twoFactor, err := d.db.TwoFactor().FindByUserID(user.ID)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "find two-factor failed")
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		tokens, err := d.issueMfaPendingToken(user.ID)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "issue mfa token failed")
+		}
+		return user, tokens, nil
+	}
+
+	if err := d.cancelScheduledDeletion(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := d.generateAndSaveTokens(ctx, user.ID, "")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -52,13 +132,39 @@ func (d *authDomain) Login(ctx context.Context, email, pass string) (*model.User
 	return user, tokens, nil
 }
 
+// checkLocalCredentials returns nil when the password does not match the stored hash
+func (d *authDomain) checkLocalCredentials(email, pass string) (*model.User, error) {
+	user, err := d.db.User().FindByEmail(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "db error")
+	}
+	// Passwordless and pending accounts fail like a wrong password so the flag is not revealed
+	if user == nil || user.Passwordless || user.Pending || !password.CheckPassword(pass, user.Password) {
+		return nil, nil
+	}
+	return user, nil
+}
+
 func (d *authDomain) Register(ctx context.Context, input model.UserInput) (*model.User, map[string]interface{}, error) {
+	// Directory users get their local account on first login instead
+	if directoryManaged() {
+		return nil, nil, stacktrace.NewError("accounts are managed by the directory")
+	}
+
+	if err := registration.NewRegistrationDomain(d.db).CheckSignUp(ctx, input.Email); err != nil {
+		return nil, nil, err
+	}
+
 	repo := d.db.User()
 	exists, err := repo.ExistsByEmail(input.Email)
 	if exists {
 		return nil, nil, stacktrace.NewError("email already taken")
 	}
 
+	if err := password.ValidatePassword(input.Password, input.Name, input.Email); err != nil {
+		return nil, nil, err
+	}
+
 	hashed, err := password.HashPassword(input.Password)
 	if err != nil {
 		return nil, nil, err
@@ -68,7 +174,7 @@ func (d *authDomain) Register(ctx context.Context, input model.UserInput) (*mode
 		Name:     input.Name,
 		Email:    input.Email,
 		Password: hashed,
-		Role:     "user",
+		Role:     model.RoleUser,
 	}
 	model.UserPrepare(user)
 
@@ -76,7 +182,7 @@ func (d *authDomain) Register(ctx context.Context, input model.UserInput) (*mode
 		return nil, nil, err
 	}
 
-	tokens, err := d.generateAndSaveTokens(user.ID)
+	tokens, err := d.generateAndSaveTokens(ctx, user.ID, "")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -84,23 +190,56 @@ func (d *authDomain) Register(ctx context.Context, input model.UserInput) (*mode
 	return user, tokens, nil
 }
 
-func (d *authDomain) generateAndSaveTokens(userID string) (map[string]interface{}, error) {
-	accessToken, refreshToken, accessExp, refreshExp, err := jwt.GenerateAuthTokens(userID)
+// rehashPassword is best effort: a failure only delays the migration to the next login
+func (d *authDomain) rehashPassword(ctx context.Context, user *model.User, pass string) {
+	hashed, err := password.HashPassword(pass)
+	if err != nil {
+		log.WithContext(ctx).Warnf("rehash password failed: %v", err)
+		return
+	}
+
+	user.Password = hashed
+	user.UpdatedAt = time.Now()
+	if err := d.db.User().Update(user); err != nil {
+		log.WithContext(ctx).Warnf("save rehashed password failed: %v", err)
+	}
+}
+
+// generateAndSaveTokens issues a new pair. An empty family starts a new one (fresh login),
+// otherwise the refresh token joins the family of the token it replaces.
+// The family doubles as the session ID carried in the "sid" claim.
+func (d *authDomain) generateAndSaveTokens(ctx context.Context, userID string, family string) (map[string]interface{}, error) {
+	newSession := family == ""
+	if newSession {
+		family = uuid.New().String()
+	}
+
+	accessToken, refreshToken, accessExp, refreshExp, err := jwt.GenerateAuthTokens(userID, family)
 	if err != nil {
 		return nil, err
 	}
 
+	ip, _ := activity.GetIPAddress(ctx)
+	userAgent, _ := activity.GetUserAgent(ctx)
+
 	// Save Refresh Token
 	err = d.db.Token().Create(&model.Token{
-		Token:   refreshToken,
-		UserID:  userID,
-		Type:    model.TokenTypeRefresh,
-		Expires: refreshExp,
+		Token:     refreshToken,
+		UserID:    userID,
+		Type:      model.TokenTypeRefresh,
+		Expires:   refreshExp,
 		CreatedAt: time.Now(),
+		Family:    family,
+		IP:        ip,
+		UserAgent: userAgent,
 	})
 	if err != nil {
 		return nil, err
 	}
+	// Refreshing keeps the session, only starting one counts as signing in
+	if newSession {
+		audit.NewAuditDomain(d.db).Record(ctx, userID, model.AuditActionSignIn)
+	}
 
 	return map[string]interface{}{
 		"access": map[string]interface{}{
@@ -120,36 +259,98 @@ func (d *authDomain) Logout(ctx context.Context, refreshToken string) error {
 	if err != nil || token == nil {
 		return stacktrace.NewError("token not found")
 	}
-	return tokenRepo.Delete(token.ID)
+	if token.Family == "" {
+		return tokenRepo.Delete(token.ID)
+	}
+	// Ending the session drops every rotated ancestor as well
+	if err := tokenRepo.DeleteByFamily(token.Family); err != nil {
+		return stacktrace.Propagate(err, "delete token family failed")
+	}
+	return d.cache.Revocation().RevokeSession(token.Family, jwt.AccessTokenLifetime())
+}
+
+// RevokeAccessToken denies a single access token for the rest of its lifetime. A token that
+// does not validate as an access token is refused by Auth anyway, so there is nothing to deny
+func (d *authDomain) RevokeAccessToken(ctx context.Context, accessToken string) error {
+	payload, err := jwt.ValidateLocalToken(accessToken)
+	if err != nil || payload.Type != model.TokenTypeAccess || payload.ExpiresAt == nil {
+		return nil
+	}
+	if err := d.cache.Revocation().RevokeToken(payload.ID, time.Until(payload.ExpiresAt.Time)); err != nil {
+		return stacktrace.Propagate(err, "revoke access token failed")
+	}
+	return nil
+}
+
+func (d *authDomain) IsTokenRevoked(ctx context.Context, payload *jwt.TokenPayload) (bool, error) {
+	revoked, err := d.cache.Revocation().IsRevoked(payload.ID, payload.Sid)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "check token revocation failed")
+	}
+	return revoked, nil
 }
 
 func (d *authDomain) RefreshToken(ctx context.Context, refreshToken string) (map[string]interface{}, error) {
+	// Verify JWT validity
+	payload, err := jwt.ValidateLocalToken(refreshToken)
+	if err != nil || payload.Type != model.TokenTypeRefresh {
+		return nil, stacktrace.NewError("invalid token")
+	}
+
 	tokenRepo := d.db.Token()
-	token, err := tokenRepo.FindByToken(refreshToken, model.TokenTypeRefresh)
+	token, err := tokenRepo.FindByTokenIncludingBlacklisted(refreshToken, model.TokenTypeRefresh)
 	if err != nil || token == nil {
 		return nil, stacktrace.NewError("please authenticate")
 	}
 
-	// Verify JWT validity
-	payload, err := jwt.ValidateLocalToken(refreshToken)
-	if err != nil {
-		return nil, stacktrace.NewError("invalid token")
+	// A rotated token presented again means it was copied: revoke the whole family
+	if token.Blacklisted {
+		return nil, d.revokeReusedFamily(ctx, token)
 	}
 
-	// Delete old token
-	tokenRepo.Delete(token.ID)
+	// Keep the old token as rotated so a replay can be detected. Only one of two requests
+	// racing with the same token gets the row, the other is treated as a replay.
+	rotated, err := tokenRepo.Blacklist(token.ID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "rotate refresh token failed")
+	}
+	if !rotated {
+		return nil, d.revokeReusedFamily(ctx, token)
+	}
 
 	// Generate new pair
-	return d.generateAndSaveTokens(payload.Sub)
+	return d.generateAndSaveTokens(ctx, token.UserID, token.Family)
+}
+
+// revokeReusedFamily blacklists every token of the family and denies its access tokens
+func (d *authDomain) revokeReusedFamily(ctx context.Context, token *model.Token) error {
+	if token.Family != "" {
+		if err := d.db.Token().BlacklistByFamily(token.Family); err != nil {
+			return stacktrace.Propagate(err, "revoke token family failed")
+		}
+		if err := d.cache.Revocation().RevokeSession(token.Family, jwt.AccessTokenLifetime()); err != nil {
+			return stacktrace.Propagate(err, "deny session access tokens failed")
+		}
+	}
+	log.WithContext(ctx).
+		WithField("event", "refresh_token_reuse").
+		WithField("user_id", token.UserID).
+		WithField("family", token.Family).
+		Warn("refresh token reuse detected, token family revoked")
+	return stacktrace.NewError("please authenticate")
 }
 
 func (d *authDomain) ForgotPassword(ctx context.Context, email string) error {
+	if directoryManaged() {
+		return nil // The directory owns the password
+	}
+
 	user, err := d.db.User().FindByEmail(email)
-	if err != nil || user == nil {
+	if err != nil || user == nil || user.Passwordless || user.Pending {
 		return nil // Fail silently
 	}
 
-	token, exp, err := jwt.GenerateToken(user.ID, 10*time.Minute, model.TokenTypeResetPassword, "") // Secret handled inside
+	token, exp, err := jwt.GenerateResetPasswordToken(user.ID)
 	if err != nil {
 		return err
 	}
@@ -171,6 +372,10 @@ func (d *authDomain) ForgotPassword(ctx context.Context, email string) error {
 }
 
 func (d *authDomain) ResetPassword(ctx context.Context, tokenStr, newPassword string) error {
+	if directoryManaged() {
+		return stacktrace.NewError("passwords are managed by the directory")
+	}
+
 	tokenRepo := d.db.Token()
 	token, err := tokenRepo.FindByToken(tokenStr, model.TokenTypeResetPassword)
 	if err != nil || token == nil {
@@ -178,21 +383,166 @@ func (d *authDomain) ResetPassword(ctx context.Context, tokenStr, newPassword st
 	}
 
 	// Verify JWT
-	_, err = jwt.ValidateLocalToken(tokenStr)
+	payload, err := jwt.ValidateLocalToken(tokenStr)
+	if err != nil || payload.Type != model.TokenTypeResetPassword {
+		return stacktrace.NewError("invalid or expired token")
+	}
+
+	user, err := d.db.User().FindByID(token.UserID)
 	if err != nil {
+		return stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return stacktrace.NewError("user not found")
+	}
+	if user.Passwordless {
+		return stacktrace.NewError("account signs in by magic link only")
+	}
+
+	if err := password.ValidatePassword(newPassword, user.Name, user.Email); err != nil {
 		return err
 	}
+	hashed, err := password.HashPassword(newPassword)
+	if err != nil {
+		return stacktrace.Propagate(err, "hash password failed")
+	}
+
+	user.Password = hashed
+	user.UpdatedAt = time.Now()
+	if err := d.db.User().Update(user); err != nil {
+		return stacktrace.Propagate(err, "update user failed")
+	}
+
+	// Consume token
+	if err := tokenRepo.DeleteByUserIDAndType(user.ID, model.TokenTypeResetPassword); err != nil {
+		return stacktrace.Propagate(err, "consume reset token failed")
+	}
+
+	audit.NewAuditDomain(d.db).Record(ctx, user.ID, model.AuditActionPasswordReset)
+
+	// Whoever knew the old password is logged out everywhere
+	return session.NewSessionDomain(d.db, d.cache).RevokeAll(ctx, user.ID)
+}
+
+// ChangePassword replaces the password of a signed-in user. Every other session is ended;
+// sessionID, the caller's own, stays valid.
+func (d *authDomain) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error {
+	if directoryManaged() {
+		return stacktrace.NewError("passwords are managed by the directory")
+	}
 
-	hashed, _ := password.HashPassword(newPassword)
-	user, _ := d.db.User().FindByID(token.UserID)
+	userRepo := d.db.User()
+	user, err := userRepo.FindByID(userID)
+	if err != nil {
+		return stacktrace.Propagate(err, "find user failed")
+	}
 	if user == nil {
 		return stacktrace.NewError("user not found")
 	}
+	if user.Passwordless {
+		return stacktrace.NewError("account signs in by magic link only")
+	}
+
+	if !password.CheckPassword(currentPassword, user.Password) {
+		return stacktrace.NewError("current password is incorrect")
+	}
+	if currentPassword == newPassword {
+		return stacktrace.NewError("new password must differ from the current one")
+	}
+
+	if err := password.ValidatePassword(newPassword, user.Name, user.Email); err != nil {
+		return err
+	}
+	hashed, err := password.HashPassword(newPassword)
+	if err != nil {
+		return stacktrace.Propagate(err, "hash password failed")
+	}
 
 	user.Password = hashed
-	d.db.User().Update(user)
-	
-	// Consume token
-	tokenRepo.DeleteByUserIDAndType(user.ID, model.TokenTypeResetPassword)
+	user.UpdatedAt = time.Now()
+	if err := userRepo.Update(user); err != nil {
+		return stacktrace.Propagate(err, "update user failed")
+	}
+	audit.NewAuditDomain(d.db).Record(ctx, user.ID, model.AuditActionPasswordChanged)
+
+	if err := session.NewSessionDomain(d.db, d.cache).RevokeOthers(ctx, user.ID, sessionID); err != nil {
+		return err
+	}
+
+	// The change already happened, a mail failure should not report it as failed
+	body := "Your password was just changed and your other sessions were signed out. If this was not you, reset your password right away."
+	if err := d.email.SendEmail(user.Email, "Password Changed", body); err != nil {
+		log.WithContext(ctx).Warnf("send password changed email failed: %v", err)
+	}
 	return nil
+}
+
+func (d *authDomain) SendVerificationEmail(ctx context.Context, userID string) error {
+	user, err := d.db.User().FindByID(userID)
+	if err != nil {
+		return stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return stacktrace.NewError("user not found")
+	}
+	if user.IsEmailVerified {
+		return stacktrace.NewError("email already verified")
+	}
+
+	token, exp, err := jwt.GenerateVerifyEmailToken(user.ID)
+	if err != nil {
+		return stacktrace.Propagate(err, "generate verify email token failed")
+	}
+
+	tokenRepo := d.db.Token()
+	// Only the latest link stays valid
+	if err := tokenRepo.DeleteByUserIDAndType(user.ID, model.TokenTypeVerifyEmail); err != nil {
+		return stacktrace.Propagate(err, "delete old verify email tokens failed")
+	}
+
+	err = tokenRepo.Create(&model.Token{
+		Token:     token,
+		UserID:    user.ID,
+		Type:      model.TokenTypeVerifyEmail,
+		Expires:   exp,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "save verify email token failed")
+	}
+
+	verifyURL := fmt.Sprintf("http://localhost:3000/verify-email?token=%s", token)
+	body := fmt.Sprintf("Click here to verify your email: %s", verifyURL)
+	return d.email.SendEmail(user.Email, "Email Verification", body)
+}
+
+func (d *authDomain) VerifyEmail(ctx context.Context, tokenStr string) error {
+	tokenRepo := d.db.Token()
+	token, err := tokenRepo.FindByToken(tokenStr, model.TokenTypeVerifyEmail)
+	if err != nil || token == nil {
+		return stacktrace.NewError("invalid or expired token")
+	}
+
+	payload, err := jwt.ValidateLocalToken(tokenStr)
+	if err != nil || payload.Type != model.TokenTypeVerifyEmail {
+		return stacktrace.NewError("invalid or expired token")
+	}
+
+	userRepo := d.db.User()
+	user, err := userRepo.FindByID(token.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return stacktrace.NewError("user not found")
+	}
+
+	user.IsEmailVerified = true
+	user.UpdatedAt = time.Now()
+	if err := userRepo.Update(user); err != nil {
+		return stacktrace.Propagate(err, "update user failed")
+	}
+
+	// Consume token
+	return tokenRepo.DeleteByUserIDAndType(user.ID, model.TokenTypeVerifyEmail)
 }
\ No newline at end of file
@@ -0,0 +1,1194 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/activity"
+	"prabogo/utils/jwt"
+	"prabogo/utils/password"
+	"prabogo/utils/signature"
+	"prabogo/utils/totp"
+)
+
+func TestAuth(t *testing.T) {
+	Convey("Test Auth", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		os.Setenv("JWT_SECRET", "test-secret")
+		defer os.Unsetenv("JWT_SECRET")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockAuditDatabasePort := mock_outbound_port.NewMockAuditDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+		mockTwoFactorDatabasePort := mock_outbound_port.NewMockTwoFactorDatabasePort(mockCtrl)
+		mockIdentityDatabasePort := mock_outbound_port.NewMockIdentityDatabasePort(mockCtrl)
+		mockLoginAttemptCachePort := mock_outbound_port.NewMockLoginAttemptCachePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockNonceCachePort := mock_outbound_port.NewMockNonceCachePort(mockCtrl)
+		mockRegistrationPolicyDatabasePort := mock_outbound_port.NewMockRegistrationPolicyDatabasePort(mockCtrl)
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Audit().Return(mockAuditDatabasePort).AnyTimes()
+		mockAuditDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+		mockDatabasePort.EXPECT().TwoFactor().Return(mockTwoFactorDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Identity().Return(mockIdentityDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().RegistrationPolicy().Return(mockRegistrationPolicyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+		mockCachePort.EXPECT().LoginAttempt().Return(mockLoginAttemptCachePort).AnyTimes()
+		mockCachePort.EXPECT().Nonce().Return(mockNonceCachePort).AnyTimes()
+
+		authDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort)
+
+		user := &model.User{
+			ID:    uuid.New().String(),
+			Name:  "Test User",
+			Email: "test@example.com",
+			Role:  "user",
+		}
+
+		Convey("SendVerificationEmail", func() {
+			Convey("User not found", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().SendVerificationEmail(context.Background(), user.ID)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Email already verified", func() {
+				verified := *user
+				verified.IsEmailVerified = true
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&verified, nil).Times(1)
+
+				err := authDomain.Auth().SendVerificationEmail(context.Background(), user.ID)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Save token error", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeVerifyEmail).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(errors.New("error")).Times(1)
+
+				err := authDomain.Auth().SendVerificationEmail(context.Background(), user.ID)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeVerifyEmail).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *model.Token) error {
+					So(token.Type, ShouldEqual, model.TokenTypeVerifyEmail)
+					So(token.UserID, ShouldEqual, user.ID)
+					return nil
+				}).Times(1)
+				mockEmailPort.EXPECT().SendEmail(user.Email, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				err := authDomain.Auth().SendVerificationEmail(context.Background(), user.ID)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("RefreshToken", func() {
+			_, refreshToken, _, refreshExp, err := jwt.GenerateAuthTokens(user.ID, "family-1")
+			So(err, ShouldBeNil)
+
+			stored := &model.Token{ID: 1, Token: refreshToken, UserID: user.ID, Type: model.TokenTypeRefresh, Expires: refreshExp, Family: "family-1"}
+
+			Convey("Invalid token", func() {
+				_, err := authDomain.Auth().RefreshToken(context.Background(), "invalid")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Token not found", func() {
+				mockTokenDatabasePort.EXPECT().FindByTokenIncludingBlacklisted(refreshToken, model.TokenTypeRefresh).Return(nil, nil).Times(1)
+
+				_, err := authDomain.Auth().RefreshToken(context.Background(), refreshToken)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Reused token revokes the family", func() {
+				rotated := *stored
+				rotated.Blacklisted = true
+				mockTokenDatabasePort.EXPECT().FindByTokenIncludingBlacklisted(refreshToken, model.TokenTypeRefresh).Return(&rotated, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().BlacklistByFamily("family-1").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				_, err := authDomain.Auth().RefreshToken(context.Background(), refreshToken)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Token rotated by a concurrent request revokes the family", func() {
+				mockTokenDatabasePort.EXPECT().FindByTokenIncludingBlacklisted(refreshToken, model.TokenTypeRefresh).Return(stored, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Blacklist(1).Return(false, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().BlacklistByFamily("family-1").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Times(0)
+
+				_, err := authDomain.Auth().RefreshToken(context.Background(), refreshToken)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success keeps the family", func() {
+				mockTokenDatabasePort.EXPECT().FindByTokenIncludingBlacklisted(refreshToken, model.TokenTypeRefresh).Return(stored, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Blacklist(1).Return(true, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *model.Token) error {
+					So(token.Family, ShouldEqual, "family-1")
+					So(token.Type, ShouldEqual, model.TokenTypeRefresh)
+					return nil
+				}).Times(1)
+
+				tokens, err := authDomain.Auth().RefreshToken(context.Background(), refreshToken)
+				So(err, ShouldBeNil)
+				So(tokens["access"], ShouldNotBeNil)
+				So(tokens["refresh"], ShouldNotBeNil)
+			})
+		})
+
+		Convey("Logout", func() {
+			Convey("Token not found", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken("refresh", model.TokenTypeRefresh).Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().Logout(context.Background(), "refresh")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success drops the family", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken("refresh", model.TokenTypeRefresh).Return(&model.Token{ID: 1, Family: "family-1"}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByFamily("family-1").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				err := authDomain.Auth().Logout(context.Background(), "refresh")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("RevokeAccessToken", func() {
+			accessToken, _, _, _, err := jwt.GenerateAuthTokens(user.ID, "family-1")
+			So(err, ShouldBeNil)
+
+			Convey("Invalid token has nothing to revoke", func() {
+				mockRevocationCachePort.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(0)
+
+				err := authDomain.Auth().RevokeAccessToken(context.Background(), "invalid")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Cache failure", func() {
+				mockRevocationCachePort.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Return(errors.New("redis down")).Times(1)
+
+				err := authDomain.Auth().RevokeAccessToken(context.Background(), accessToken)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				payload, _ := jwt.ValidateLocalToken(accessToken)
+				mockRevocationCachePort.EXPECT().RevokeToken(payload.ID, gomock.Any()).DoAndReturn(func(tokenID string, ttl time.Duration) error {
+					So(ttl, ShouldBeGreaterThan, 0)
+					So(ttl, ShouldBeLessThanOrEqualTo, jwt.AccessTokenLifetime())
+					return nil
+				}).Times(1)
+
+				err := authDomain.Auth().RevokeAccessToken(context.Background(), accessToken)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("IsTokenRevoked", func() {
+			payload := &jwt.TokenPayload{Sub: user.ID, Type: model.TokenTypeAccess, Sid: "family-1"}
+			payload.ID = "jti-1"
+
+			Convey("Cache error", func() {
+				mockRevocationCachePort.EXPECT().IsRevoked("jti-1", "family-1").Return(false, errors.New("error")).Times(1)
+
+				_, err := authDomain.Auth().IsTokenRevoked(context.Background(), payload)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Revoked", func() {
+				mockRevocationCachePort.EXPECT().IsRevoked("jti-1", "family-1").Return(true, nil).Times(1)
+
+				revoked, err := authDomain.Auth().IsTokenRevoked(context.Background(), payload)
+				So(err, ShouldBeNil)
+				So(revoked, ShouldBeTrue)
+			})
+		})
+
+		Convey("ResetPassword", func() {
+			token, exp, err := jwt.GenerateResetPasswordToken(user.ID)
+			So(err, ShouldBeNil)
+
+			stored := &model.Token{ID: 1, Token: token, UserID: user.ID, Type: model.TokenTypeResetPassword, Expires: exp}
+
+			Convey("Token not found", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeResetPassword).Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().ResetPassword(context.Background(), token, "newpassword123")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Password rejected by the policy", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeResetPassword).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+
+				err := authDomain.Auth().ResetPassword(context.Background(), token, "testuser1")
+				var policyErr *password.PolicyError
+				So(errors.As(err, &policyErr), ShouldBeTrue)
+				So(policyErr.Violations[0].Rule, ShouldEqual, password.RulePersonalInfo)
+			})
+
+			Convey("Success revokes every session", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeResetPassword).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeResetPassword).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID(user.ID).Return([]model.Session{{ID: "family-1", UserID: user.ID}}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeRefresh).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeImpersonation).Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				err := authDomain.Auth().ResetPassword(context.Background(), token, "newpassword123")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("ChangePassword", func() {
+			hashed, _ := password.HashPassword("password1")
+			withPassword := *user
+			withPassword.Password = hashed
+
+			Convey("Wrong current password", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&withPassword, nil).Times(1)
+
+				err := authDomain.Auth().ChangePassword(context.Background(), user.ID, "family-1", "wrong", "correct7horse")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("New password rejected by the policy", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&withPassword, nil).Times(1)
+
+				err := authDomain.Auth().ChangePassword(context.Background(), user.ID, "family-1", "password1", "short")
+				var policyErr *password.PolicyError
+				So(errors.As(err, &policyErr), ShouldBeTrue)
+			})
+
+			Convey("Success keeps the current session and notifies the user", func() {
+				var updated *model.User
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&withPassword, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					updated = u
+					return nil
+				}).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID(user.ID).Return([]model.Session{{ID: "family-1", UserID: user.ID}, {ID: "family-2", UserID: user.ID}}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByFamily("family-2").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-2", gomock.Any()).Return(nil).Times(1)
+				mockEmailPort.EXPECT().SendEmail(user.Email, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				err := authDomain.Auth().ChangePassword(context.Background(), user.ID, "family-1", "password1", "correct7horse")
+				So(err, ShouldBeNil)
+				So(password.CheckPassword("correct7horse", updated.Password), ShouldBeTrue)
+			})
+		})
+
+		Convey("VerifyEmail", func() {
+			token, exp, err := jwt.GenerateVerifyEmailToken(user.ID)
+			So(err, ShouldBeNil)
+
+			stored := &model.Token{ID: 1, Token: token, UserID: user.ID, Type: model.TokenTypeVerifyEmail, Expires: exp}
+
+			Convey("Token not found", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeVerifyEmail).Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().VerifyEmail(context.Background(), token)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Token signature invalid", func() {
+				os.Setenv("JWT_SECRET", "another-secret")
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeVerifyEmail).Return(stored, nil).Times(1)
+
+				err := authDomain.Auth().VerifyEmail(context.Background(), token)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeVerifyEmail).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					So(u.IsEmailVerified, ShouldBeTrue)
+					return nil
+				}).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeVerifyEmail).Return(nil).Times(1)
+
+				err := authDomain.Auth().VerifyEmail(context.Background(), token)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("Login", func() {
+			hashed, _ := password.HashPassword("password1")
+			withPassword := *user
+			withPassword.Password = hashed
+
+			Convey("Locked account is throttled before the password is checked", func() {
+				mockLoginAttemptCachePort.EXPECT().LockedFor("account:test@example.com").Return(10*time.Minute, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+
+				_, _, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				var throttled *model.LoginThrottledError
+				So(errors.As(err, &throttled), ShouldBeTrue)
+				So(throttled.RetrySeconds(), ShouldEqual, 600)
+			})
+
+			Convey("Wrong password counts a failure", func() {
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&withPassword, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), user.Email, "wrong")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Passwordless account cannot sign in with a password", func() {
+				passwordless := withPassword
+				passwordless.Passwordless = true
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&passwordless, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Pending invitee cannot sign in", func() {
+				pending := withPassword
+				pending.Pending = true
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&pending, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Reaching the threshold locks the account and the address", func() {
+				os.Setenv("LOGIN_MAX_ATTEMPTS", "3")
+				os.Setenv("LOGIN_MAX_ATTEMPTS_PER_IP", "3")
+				defer os.Unsetenv("LOGIN_MAX_ATTEMPTS")
+				defer os.Unsetenv("LOGIN_MAX_ATTEMPTS_PER_IP")
+
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(nil, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(3), nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Lock("account:test@example.com", 15*time.Minute).Return(nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("ip:10.0.0.1", gomock.Any()).Return(int64(3), nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Lock("ip:10.0.0.1", 15*time.Minute).Return(nil).Times(1)
+
+				ctx := activity.WithIPAddress(context.Background(), "10.0.0.1")
+				_, _, err := authDomain.Auth().Login(ctx, user.Email, "wrong")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Without two-factor issues tokens", func() {
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&withPassword, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, tokens, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldBeNil)
+				So(tokens["access"], ShouldNotBeNil)
+			})
+
+			Convey("Signing in cancels a scheduled deletion", func() {
+				scheduled := time.Now().Add(24 * time.Hour)
+				leaving := withPassword
+				leaving.DeletionScheduledAt = &scheduled
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&leaving, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					So(u.DeletionScheduledAt, ShouldBeNil)
+					return nil
+				}).Times(1)
+				mockEmailPort.EXPECT().SendEmail(user.Email, "Account Deletion Cancelled", gomock.Any()).Return(nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Password alone keeps a scheduled deletion when 2FA is on", func() {
+				scheduled := time.Now().Add(24 * time.Hour)
+				leaving := withPassword
+				leaving.DeletionScheduledAt = &scheduled
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&leaving, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Enabled: true}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, tokens, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldBeNil)
+				So(tokens["mfa"], ShouldNotBeNil)
+				So(leaving.DeletionScheduledAt, ShouldNotBeNil)
+			})
+
+			Convey("Outdated hash is replaced with the configured algorithm", func() {
+				os.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
+				os.Setenv("PASSWORD_ARGON2_MEMORY_KB", "1024")
+				os.Setenv("PASSWORD_ARGON2_ITERATIONS", "1")
+				defer os.Unsetenv("PASSWORD_HASH_ALGORITHM")
+				defer os.Unsetenv("PASSWORD_ARGON2_MEMORY_KB")
+				defer os.Unsetenv("PASSWORD_ARGON2_ITERATIONS")
+
+				var rehashed string
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&withPassword, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					rehashed = u.Password
+					return nil
+				}).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldBeNil)
+				So(rehashed, ShouldStartWith, "$argon2id$")
+				So(password.CheckPassword("password1", rehashed), ShouldBeTrue)
+			})
+
+			Convey("With two-factor issues a pending token only", func() {
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(&withPassword, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Enabled: true}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *model.Token) error {
+					So(token.Type, ShouldEqual, model.TokenTypeMfaPending)
+					return nil
+				}).Times(1)
+
+				_, tokens, err := authDomain.Auth().Login(context.Background(), user.Email, "password1")
+				So(err, ShouldBeNil)
+				So(tokens["mfa"], ShouldNotBeNil)
+				So(tokens["access"], ShouldBeNil)
+			})
+		})
+
+		Convey("Login with AUTH_DRIVER=ldap", func() {
+			os.Setenv("AUTH_DRIVER", "ldap")
+			defer os.Unsetenv("AUTH_DRIVER")
+			mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+
+			entry := &model.ExternalIdentity{
+				Provider:      model.IdentityProviderLDAP,
+				Subject:       "uid=jdoe,ou=people,dc=example,dc=com",
+				Email:         "jdoe@example.com",
+				EmailVerified: true,
+				Name:          "Jane Doe",
+				Groups:        []string{},
+			}
+
+			Convey("Rejected by the directory counts a failure", func() {
+				mockDirectoryPort.EXPECT().Authenticate("jdoe", "wrong").Return(nil, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:jdoe", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), "jdoe", "wrong")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Directory down is an error, not a failed attempt", func() {
+				mockDirectoryPort.EXPECT().Authenticate("jdoe", "correct7horse").Return(nil, errors.New("ldap dial failed")).Times(1)
+
+				_, _, err := authDomain.Auth().Login(context.Background(), "jdoe", "correct7horse")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("First login creates the local user and issues tokens", func() {
+				var created *model.User
+				mockDirectoryPort.EXPECT().Authenticate("jdoe", "correct7horse").Return(entry, nil).Times(1)
+				mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderLDAP, entry.Subject).Return(nil, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByEmail("jdoe@example.com").Return(nil, nil).Times(1)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+					return fn(mockDatabasePort)
+				}).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					created = u
+					return nil
+				}).Times(1)
+				mockIdentityDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(gomock.Any()).Return(nil, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				signedIn, tokens, err := authDomain.Auth().Login(context.Background(), "jdoe", "correct7horse")
+				So(err, ShouldBeNil)
+				So(tokens["access"], ShouldNotBeNil)
+				So(signedIn.Name, ShouldEqual, "Jane Doe")
+				So(signedIn.Role, ShouldEqual, model.RoleUser)
+				// The directory password is never stored
+				So(password.CheckPassword("correct7horse", created.Password), ShouldBeFalse)
+			})
+
+			Convey("Registration and local password changes are refused", func() {
+				_, _, err := authDomain.Auth().Register(context.Background(), model.UserInput{Name: "New", Email: "new@example.com", Password: "correct7horse"})
+				So(err, ShouldNotBeNil)
+
+				err = authDomain.Auth().ChangePassword(context.Background(), user.ID, "family-1", "old", "correct7horse")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Register", func() {
+			input := model.UserInput{Name: "New", Email: "new@eu.example.com", Password: "correct7horse"}
+			refusedWith := func(err error) string {
+				var refused *model.RegistrationError
+				So(errors.As(err, &refused), ShouldBeTrue)
+				return refused.Code
+			}
+
+			Convey("Closed", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeClosed}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().ExistsByEmail(gomock.Any()).Times(0)
+
+				_, _, err := authDomain.Auth().Register(context.Background(), input)
+				So(refusedWith(err), ShouldEqual, model.RegistrationErrorClosed)
+			})
+
+			Convey("Invite only", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeInviteOnly}, nil).Times(1)
+
+				_, _, err := authDomain.Auth().Register(context.Background(), input)
+				So(refusedWith(err), ShouldEqual, model.RegistrationErrorInviteOnly)
+			})
+
+			Convey("Restricted to other domains", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeRestricted, AllowedDomains: []string{"example.org"}}, nil).Times(1)
+
+				_, _, err := authDomain.Auth().Register(context.Background(), input)
+				So(refusedWith(err), ShouldEqual, model.RegistrationErrorDomainNotAllowed)
+			})
+
+			Convey("Denied subdomain of an allowed domain", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{
+					Mode:           model.RegistrationModeRestricted,
+					AllowedDomains: []string{"example.com"},
+					DeniedDomains:  []string{"eu.example.com"},
+				}, nil).Times(1)
+
+				_, _, err := authDomain.Auth().Register(context.Background(), input)
+				So(refusedWith(err), ShouldEqual, model.RegistrationErrorDomainDenied)
+			})
+
+			Convey("Allowed domain covers its subdomains", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeRestricted, AllowedDomains: []string{"example.com"}}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@eu.example.com").Return(true, nil).Times(1)
+
+				_, _, err := authDomain.Auth().Register(context.Background(), input)
+				So(err.Error(), ShouldContainSubstring, "email already taken")
+			})
+
+			Convey("Open when no policy was saved", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(nil, nil).Times(1)
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@eu.example.com").Return(false, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				created, tokens, err := authDomain.Auth().Register(context.Background(), input)
+				So(err, ShouldBeNil)
+				So(created.Role, ShouldEqual, model.RoleUser)
+				So(tokens["access"], ShouldNotBeNil)
+			})
+
+			Convey("Policy lookup error", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(nil, errors.New("error")).Times(1)
+
+				_, _, err := authDomain.Auth().Register(context.Background(), input)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("EnrollTwoFactor", func() {
+			Convey("Already enabled", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Enabled: true}, nil).Times(1)
+
+				_, err := authDomain.Auth().EnrollTwoFactor(context.Background(), user.ID)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success stores a disabled secret", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(tf *model.TwoFactor) error {
+					So(tf.Enabled, ShouldBeFalse)
+					So(tf.Secret, ShouldNotBeEmpty)
+					return nil
+				}).Times(1)
+
+				enrollment, err := authDomain.Auth().EnrollTwoFactor(context.Background(), user.ID)
+				So(err, ShouldBeNil)
+				So(enrollment.URI, ShouldStartWith, "otpauth://totp/")
+			})
+		})
+
+		Convey("ConfirmTwoFactor", func() {
+			secret, _ := totp.GenerateSecret()
+
+			Convey("Invalid code", func() {
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret}, nil).Times(1)
+
+				_, err := authDomain.Auth().ConfirmTwoFactor(context.Background(), user.ID, "000000x")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success returns recovery codes", func() {
+				code, _ := totp.GenerateCode(secret, time.Now())
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret}, nil).Times(1)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+					return fn(mockDatabasePort)
+				}).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(tf *model.TwoFactor) error {
+					So(tf.Enabled, ShouldBeTrue)
+					return nil
+				}).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().ReplaceRecoveryCodes(user.ID, gomock.Len(10)).Return(nil).Times(1)
+
+				codes, err := authDomain.Auth().ConfirmTwoFactor(context.Background(), user.ID, code)
+				So(err, ShouldBeNil)
+				So(len(codes), ShouldEqual, 10)
+			})
+		})
+
+		Convey("VerifyTwoFactorLogin", func() {
+			secret, _ := totp.GenerateSecret()
+			mfaToken, exp, _ := jwt.GenerateMfaPendingToken(user.ID)
+			stored := &model.Token{ID: 7, Token: mfaToken, UserID: user.ID, Type: model.TokenTypeMfaPending, Expires: exp}
+
+			Convey("Token not found", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(nil, nil).Times(1)
+
+				_, _, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, "123456")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Locked account is throttled before the code is checked", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor("account:test@example.com").Return(10*time.Minute, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+
+				_, _, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, "123456")
+				var throttled *model.LoginThrottledError
+				So(errors.As(err, &throttled), ShouldBeTrue)
+			})
+
+			Convey("Replayed code is rejected", func() {
+				code, _ := totp.GenerateCode(secret, time.Now())
+				step, _ := totp.Validate(code, secret, time.Now())
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret, Enabled: true, LastUsedStep: step}, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(1), nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("mfa:7", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, code)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Code used by a concurrent request is rejected", func() {
+				code, _ := totp.GenerateCode(secret, time.Now())
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret, Enabled: true}, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().UseStep(user.ID, gomock.Any()).Return(false, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(2)
+
+				_, _, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, code)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Too many wrong codes drop the mfa token", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret, Enabled: true}, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().UseRecoveryCode(user.ID, gomock.Any()).Return(false, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(1), nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("mfa:7", gomock.Any()).Return(int64(5), nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Delete(stored.ID).Return(nil).Times(1)
+
+				_, _, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, "WRONG-CODE1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("TOTP code issues tokens", func() {
+				code, _ := totp.GenerateCode(secret, time.Now())
+				step, _ := totp.Validate(code, secret, time.Now())
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret, Enabled: true}, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().UseStep(user.ID, step).Return(true, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockTokenDatabasePort.EXPECT().Delete(stored.ID).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, tokens, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, code)
+				So(err, ShouldBeNil)
+				So(tokens["access"], ShouldNotBeNil)
+			})
+
+			Convey("Second factor cancels a scheduled deletion", func() {
+				scheduled := time.Now().Add(24 * time.Hour)
+				leaving := *user
+				leaving.DeletionScheduledAt = &scheduled
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&leaving, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret, Enabled: true}, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().UseRecoveryCode(user.ID, gomock.Any()).Return(true, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockTokenDatabasePort.EXPECT().Delete(stored.ID).Return(nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					So(u.DeletionScheduledAt, ShouldBeNil)
+					return nil
+				}).Times(1)
+				mockEmailPort.EXPECT().SendEmail(user.Email, "Account Deletion Cancelled", gomock.Any()).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, _, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, "ABCDE-12345")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Recovery code issues tokens", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(mfaToken, model.TokenTypeMfaPending).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: secret, Enabled: true}, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().UseRecoveryCode(user.ID, password.HashToken("abcde12345")).Return(true, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Reset(gomock.Any()).Return(nil).Times(2)
+				mockTokenDatabasePort.EXPECT().Delete(stored.ID).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, tokens, err := authDomain.Auth().VerifyTwoFactorLogin(context.Background(), mfaToken, "ABCDE-12345")
+				So(err, ShouldBeNil)
+				So(tokens["refresh"], ShouldNotBeNil)
+			})
+		})
+
+		Convey("RequestMagicLink", func() {
+			Convey("Unknown email fails silently", func() {
+				mockUserDatabasePort.EXPECT().FindByEmail("nobody@example.com").Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().RequestMagicLink(context.Background(), "nobody@example.com")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Success replaces older links and emails the new one", func() {
+				var created *model.Token
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(user, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeMagicLink).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *model.Token) error {
+					created = token
+					return nil
+				}).Times(1)
+				mockEmailPort.EXPECT().SendEmail(user.Email, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				err := authDomain.Auth().RequestMagicLink(context.Background(), user.Email)
+				So(err, ShouldBeNil)
+				So(created.Type, ShouldEqual, model.TokenTypeMagicLink)
+				So(created.UserID, ShouldEqual, user.ID)
+			})
+		})
+
+		Convey("VerifyMagicLink", func() {
+			token, exp, err := jwt.GenerateMagicLinkToken(user.ID)
+			So(err, ShouldBeNil)
+
+			stored := &model.Token{ID: 9, Token: token, UserID: user.ID, Type: model.TokenTypeMagicLink, Expires: exp}
+
+			Convey("Used or unknown token", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeMagicLink).Return(nil, nil).Times(1)
+
+				_, _, err := authDomain.Auth().VerifyMagicLink(context.Background(), token)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Token of another type", func() {
+				resetToken, _, _ := jwt.GenerateResetPasswordToken(user.ID)
+				mockTokenDatabasePort.EXPECT().FindByToken(resetToken, model.TokenTypeMagicLink).Return(stored, nil).Times(1)
+
+				_, _, err := authDomain.Auth().VerifyMagicLink(context.Background(), resetToken)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success consumes the link, verifies the email and issues tokens", func() {
+				var updated *model.User
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeMagicLink).Return(stored, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Delete(stored.ID).Return(nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					updated = u
+					return nil
+				}).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(nil, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, tokens, err := authDomain.Auth().VerifyMagicLink(context.Background(), token)
+				So(err, ShouldBeNil)
+				So(tokens["access"], ShouldNotBeNil)
+				So(updated.IsEmailVerified, ShouldBeTrue)
+			})
+
+			Convey("With two-factor issues a pending token only", func() {
+				verified := *user
+				verified.IsEmailVerified = true
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeMagicLink).Return(stored, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Delete(stored.ID).Return(nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&verified, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID(user.ID).Return(&model.TwoFactor{UserID: user.ID, Enabled: true}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				_, tokens, err := authDomain.Auth().VerifyMagicLink(context.Background(), token)
+				So(err, ShouldBeNil)
+				So(tokens["mfa"], ShouldNotBeNil)
+				So(tokens["access"], ShouldBeNil)
+			})
+		})
+
+		Convey("ProvisionExternalUser", func() {
+			identity := model.ExternalIdentity{
+				Provider:      model.IdentityProviderOIDC,
+				Subject:       "idp-subject",
+				Email:         user.Email,
+				EmailVerified: true,
+				Name:          user.Name,
+				Groups:        []string{"staff"},
+			}
+
+			Convey("Missing subject", func() {
+				identity.Subject = ""
+
+				_, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Unverified email cannot claim a local account", func() {
+				identity.EmailVerified = false
+				mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").Return(nil, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(user, nil).Times(1)
+
+				_, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("First login creates and links the user", func() {
+				os.Setenv("AUTH_GROUP_ROLES", "ops=support, staff=admin")
+				defer os.Unsetenv("AUTH_GROUP_ROLES")
+
+				mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").Return(nil, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByEmail(user.Email).Return(nil, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("admin").Return(&model.Role{Name: "admin"}, nil).Times(2)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+					return fn(mockDatabasePort)
+				}).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				mockIdentityDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(link *model.UserIdentity) error {
+					So(link.Subject, ShouldEqual, "idp-subject")
+					return nil
+				}).Times(1)
+
+				provisioned, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+				So(err, ShouldBeNil)
+				So(provisioned.Email, ShouldEqual, user.Email)
+				So(provisioned.Role, ShouldEqual, "admin")
+				So(provisioned.IsEmailVerified, ShouldBeTrue)
+			})
+
+			Convey("Linked user without a groups claim keeps the role", func() {
+				identity.Groups = nil
+				admin := *user
+				admin.Role = "admin"
+				admin.IsEmailVerified = true
+				mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").
+					Return(&model.UserIdentity{UserID: user.ID}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&admin, nil).Times(1)
+
+				provisioned, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+				So(err, ShouldBeNil)
+				So(provisioned.Role, ShouldEqual, "admin")
+			})
+
+			Convey("Linked user", func() {
+				os.Setenv("AUTH_GROUP_ROLES", "staff=support")
+				defer os.Unsetenv("AUTH_GROUP_ROLES")
+
+				linked := *user
+				linked.Role = "auditor"
+				linked.IsEmailVerified = true
+				mockIdentityDatabasePort.EXPECT().FindByProviderSubject(model.IdentityProviderOIDC, "idp-subject").
+					Return(&model.UserIdentity{UserID: user.ID}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&linked, nil).Times(1)
+
+				Convey("In a mapped group gets the role", func() {
+					mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(1)
+					mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+
+					provisioned, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+					So(err, ShouldBeNil)
+					So(provisioned.Role, ShouldEqual, "support")
+				})
+
+				Convey("In no mapped group is demoted to user", func() {
+					identity.Groups = []string{}
+					mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+
+					provisioned, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+					So(err, ShouldBeNil)
+					So(provisioned.Role, ShouldEqual, model.RoleUser)
+				})
+
+				Convey("Mapped to an unknown role is demoted to user", func() {
+					mockRoleDatabasePort.EXPECT().FindByName("support").Return(nil, nil).Times(1)
+					mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+
+					provisioned, err := authDomain.Auth().ProvisionExternalUser(context.Background(), identity)
+					So(err, ShouldBeNil)
+					So(provisioned.Role, ShouldEqual, model.RoleUser)
+				})
+			})
+		})
+
+		Convey("Impersonate", func() {
+			actor := &model.User{ID: uuid.New().String(), Role: "support"}
+
+			Convey("Cannot impersonate yourself", func() {
+				_, err := authDomain.Auth().Impersonate(context.Background(), user.ID, user.ID)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("User with a permission the actor lacks", func() {
+				target := *user
+				target.Role = "auditor"
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&target, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("auditor").Return(&model.Role{
+					Name:        "auditor",
+					Permissions: []string{model.PermissionSessionsRead},
+				}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(actor, nil).Times(2)
+				mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersImpersonate).Return(true, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support", Permissions: []string{model.PermissionUsersImpersonate}}, nil).Times(1)
+
+				_, err := authDomain.Auth().Impersonate(context.Background(), actor.ID, user.ID)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success names the actor in the act claim and stores the token", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(actor, nil).Times(2)
+				mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersImpersonate).Return(true, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support", Permissions: []string{model.PermissionUsersImpersonate}}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("user").Return(&model.Role{Name: "user", Permissions: []string{}}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *model.Token) error {
+					So(token.Type, ShouldEqual, model.TokenTypeImpersonation)
+					So(token.UserID, ShouldEqual, user.ID)
+					return nil
+				}).Times(1)
+
+				tokens, err := authDomain.Auth().Impersonate(context.Background(), actor.ID, user.ID)
+				So(err, ShouldBeNil)
+				So(tokens["refresh"], ShouldBeNil)
+
+				access := tokens["access"].(map[string]interface{})
+				payload, err := jwt.ValidateLocalToken(access["token"].(string))
+				So(err, ShouldBeNil)
+				So(payload.Sub, ShouldEqual, user.ID)
+				So(payload.Sid, ShouldBeEmpty)
+				So(payload.Act, ShouldNotBeNil)
+				So(payload.Act.Sub, ShouldEqual, actor.ID)
+			})
+		})
+
+		Convey("CheckImpersonation", func() {
+			actor := &model.User{ID: uuid.New().String(), Role: "support"}
+			token, _, _ := jwt.GenerateImpersonationToken(user.ID, actor.ID)
+			payload, _ := jwt.ValidateLocalToken(token)
+			stored := &model.Token{ID: 3, Token: token, UserID: user.ID, Type: model.TokenTypeImpersonation}
+
+			Convey("Dropped token", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeImpersonation).Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().CheckImpersonation(context.Background(), token, payload)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Deleted actor", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeImpersonation).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(nil, nil).Times(1)
+
+				err := authDomain.Auth().CheckImpersonation(context.Background(), token, payload)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Actor lost the permission", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeImpersonation).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(actor, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersImpersonate).Return(false, nil).Times(1)
+
+				err := authDomain.Auth().CheckImpersonation(context.Background(), token, payload)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Still allowed", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeImpersonation).Return(stored, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(actor, nil).Times(2)
+				mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersImpersonate).Return(true, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support", Permissions: []string{model.PermissionUsersImpersonate}}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("user").Return(&model.Role{Name: "user", Permissions: []string{}}, nil).Times(1)
+
+				err := authDomain.Auth().CheckImpersonation(context.Background(), token, payload)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("VerifySignedRequest", func() {
+			os.Setenv("INTERNAL_KEY", "internal-secret")
+			defer os.Unsetenv("INTERNAL_KEY")
+
+			request := signature.Request{
+				Method:    "POST",
+				Path:      "/v1/internal/clients/find",
+				Body:      []byte(`{}`),
+				Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+				Nonce:     "nonce-1",
+			}
+			sig := request.Signature("internal-secret")
+
+			Convey("Internal key not set", func() {
+				os.Unsetenv("INTERNAL_KEY")
+				err := authDomain.Auth().VerifySignedRequest(context.Background(), request, sig)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Invalid signature never claims the nonce", func() {
+				err := authDomain.Auth().VerifySignedRequest(context.Background(), request, request.Signature("other"))
+				So(err, ShouldEqual, signature.ErrInvalid)
+			})
+
+			Convey("Replayed nonce", func() {
+				mockNonceCachePort.EXPECT().Claim("nonce-1", 2*signature.DefaultMaxSkew).Return(false, nil).Times(1)
+				err := authDomain.Auth().VerifySignedRequest(context.Background(), request, sig)
+				So(err, ShouldEqual, signature.ErrReplayed)
+			})
+
+			Convey("Success", func() {
+				mockNonceCachePort.EXPECT().Claim("nonce-1", 2*signature.DefaultMaxSkew).Return(true, nil).Times(1)
+				err := authDomain.Auth().VerifySignedRequest(context.Background(), request, sig)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("DeleteAccount", func() {
+			hashed, _ := password.HashPassword("password1")
+			withPassword := *user
+			withPassword.Password = hashed
+
+			Convey("Wrong password", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&withPassword, nil).Times(1)
+
+				_, err := authDomain.Auth().DeleteAccount(context.Background(), user.ID, "family-1", model.DeleteAccountInput{Password: "wrong"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Passwordless account must have signed in recently", func() {
+				passwordless := *user
+				passwordless.Passwordless = true
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&passwordless, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionByFamily("family-1").Return(&model.Session{ID: "family-1", UserID: user.ID, CreatedAt: time.Now().Add(-time.Hour)}, nil).Times(1)
+
+				_, err := authDomain.Auth().DeleteAccount(context.Background(), user.ID, "family-1", model.DeleteAccountInput{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success schedules the deletion and signs out everywhere", func() {
+				os.Setenv("ACCOUNT_DELETION_GRACE_DAYS", "14")
+				defer os.Unsetenv("ACCOUNT_DELETION_GRACE_DAYS")
+
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(&withPassword, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID(user.ID).Return([]model.Session{{ID: "family-1", UserID: user.ID}}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeRefresh).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(user.ID, model.TokenTypeImpersonation).Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+				mockAccessTokenDatabasePort.EXPECT().DeleteByUserID(user.ID).Return(nil).Times(1)
+				mockEmailPort.EXPECT().SendEmail(user.Email, "Account Deletion Scheduled", gomock.Any()).Return(nil).Times(1)
+
+				deleted, err := authDomain.Auth().DeleteAccount(context.Background(), user.ID, "family-1", model.DeleteAccountInput{Password: "password1"})
+				So(err, ShouldBeNil)
+				So(deleted.DeletionScheduledAt, ShouldNotBeNil)
+				So(time.Until(*deleted.DeletionScheduledAt), ShouldAlmostEqual, 14*24*time.Hour, time.Minute)
+			})
+		})
+
+		Convey("PurgeTokens", func() {
+			os.Setenv("TOKEN_PURGE_BATCH_SIZE", "2")
+			defer os.Unsetenv("TOKEN_PURGE_BATCH_SIZE")
+
+			Convey("Error reports what was purged so far", func() {
+				gomock.InOrder(
+					mockTokenDatabasePort.EXPECT().PurgeExpired(gomock.Any(), 2).Return(2, nil),
+					mockTokenDatabasePort.EXPECT().PurgeExpired(gomock.Any(), 2).Return(0, errors.New("error")),
+				)
+
+				purged, err := authDomain.Auth().PurgeTokens(context.Background())
+				So(err, ShouldNotBeNil)
+				So(purged, ShouldEqual, 2)
+			})
+
+			Convey("Runs batches until one comes back short", func() {
+				gomock.InOrder(
+					mockTokenDatabasePort.EXPECT().PurgeExpired(gomock.Any(), 2).Return(2, nil),
+					mockTokenDatabasePort.EXPECT().PurgeExpired(gomock.Any(), 2).Return(2, nil),
+					mockTokenDatabasePort.EXPECT().PurgeExpired(gomock.Any(), 2).Return(1, nil),
+				)
+
+				purged, err := authDomain.Auth().PurgeTokens(context.Background())
+				So(err, ShouldBeNil)
+				So(purged, ShouldEqual, 5)
+			})
+		})
+	})
+}
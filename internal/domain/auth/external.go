@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
+	"prabogo/utils/log"
+	"prabogo/utils/password"
+)
+
+// ProvisionExternalUser resolves an identity asserted by an external provider to a local
+// user, creating the user on first sight and keeping name, verification and role in sync
+func (d *authDomain) ProvisionExternalUser(ctx context.Context, identity model.ExternalIdentity) (*model.User, error) {
+	if identity.Subject == "" {
+		return nil, stacktrace.NewError("identity subject is required")
+	}
+
+	link, err := d.db.Identity().FindByProviderSubject(identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find identity failed")
+	}
+	if link != nil {
+		user, err := d.db.User().FindByID(link.UserID)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "find user failed")
+		}
+		if user == nil {
+			return nil, stacktrace.NewError("user not found")
+		}
+		return d.syncExternalUser(ctx, user, identity)
+	}
+
+	if identity.Email == "" {
+		return nil, stacktrace.NewError("identity email is required")
+	}
+
+	user, err := d.db.User().FindByEmail(identity.Email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	// An unverified address at the provider must not take over a local account
+	if user != nil && !identity.EmailVerified {
+		return nil, stacktrace.NewError("email already taken")
+	}
+
+	role, err := d.roleFromGroups(ctx, identity.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		if user == nil {
+			created, err := newExternalUser(identity, role)
+			if err != nil {
+				return nil, err
+			}
+			if err := repo.User().Create(created); err != nil {
+				return nil, err
+			}
+			user = created
+		}
+
+		err := repo.Identity().Create(&model.UserIdentity{
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			UserID:    user.ID,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "provision user failed")
+	}
+
+	return d.syncExternalUser(ctx, out.(*model.User), identity)
+}
+
+// newExternalUser builds the local user, with the default role when no group was mapped
+func newExternalUser(identity model.ExternalIdentity, role string) (*model.User, error) {
+	// Nobody knows this password, the provider is the only way in
+	hashed, err := password.HashPassword(utils.GenerateSecureToken(32))
+	if err != nil {
+		return nil, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = identity.Email
+	}
+
+	user := &model.User{
+		Name:            name,
+		Email:           identity.Email,
+		Password:        hashed,
+		Role:            role,
+		IsEmailVerified: identity.EmailVerified,
+	}
+	model.UserPrepare(user)
+	return user, nil
+}
+
+// syncExternalUser copies what the provider asserts onto the local user, writing only on change
+func (d *authDomain) syncExternalUser(ctx context.Context, user *model.User, identity model.ExternalIdentity) (*model.User, error) {
+	changed := false
+
+	if identity.Name != "" && identity.Name != user.Name {
+		user.Name = identity.Name
+		changed = true
+	}
+	if identity.EmailVerified && !user.IsEmailVerified {
+		user.IsEmailVerified = true
+		changed = true
+	}
+	// The provider's groups are authoritative: in none of the mapped groups means the user
+	// role. Only without a groups claim is the local role left alone
+	if identity.Groups != nil {
+		role, err := d.roleFromGroups(ctx, identity.Groups)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			role = model.RoleUser
+		}
+		if role != user.Role {
+			user.Role = role
+			changed = true
+		}
+	}
+
+	if !changed {
+		return user, nil
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := d.db.User().Update(user); err != nil {
+		return nil, stacktrace.Propagate(err, "update user failed")
+	}
+	return user, nil
+}
+
+// roleFromGroups maps provider groups to a local role through AUTH_GROUP_ROLES, comma-separated
+// "group=role" pairs tried in order. Roles missing from the roles table are skipped, and an
+// empty role means no group matched
+func (d *authDomain) roleFromGroups(ctx context.Context, groups []string) (string, error) {
+	mapping := os.Getenv("AUTH_GROUP_ROLES")
+	if mapping == "" {
+		mapping = "admin=" + model.RoleAdmin
+	}
+
+	for _, pair := range strings.Split(mapping, ",") {
+		group, role, _ := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if group == "" || role == "" || !containsFold(groups, group) {
+			continue
+		}
+
+		found, err := d.db.Role().FindByName(role)
+		if err != nil {
+			return "", stacktrace.Propagate(err, "find role failed")
+		}
+		if found == nil {
+			log.WithContext(ctx).Warnf("AUTH_GROUP_ROLES maps group %s to unknown role %s", group, role)
+			continue
+		}
+		return found.Name, nil
+	}
+	return "", nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
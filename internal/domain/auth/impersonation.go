@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/domain/audit"
+	"prabogo/internal/domain/role"
+	"prabogo/internal/model"
+	"prabogo/utils/activity"
+	"prabogo/utils/jwt"
+	"prabogo/utils/log"
+)
+
+// Impersonate issues a short-lived access token for userID whose act claim names actorID.
+// Nobody can impersonate a user holding a permission they lack themselves.
+func (d *authDomain) Impersonate(ctx context.Context, actorID, userID string) (map[string]interface{}, error) {
+	if actorID == userID {
+		return nil, stacktrace.NewError("cannot impersonate yourself")
+	}
+
+	user, err := d.db.User().FindByID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+
+	if err := d.authorizeImpersonation(ctx, actorID, user); err != nil {
+		return nil, err
+	}
+
+	token, exp, err := jwt.GenerateImpersonationToken(user.ID, actorID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "generate impersonation token failed")
+	}
+
+	// Stored against the target so signing them out everywhere, or deleting them, ends it too
+	err = d.db.Token().Create(&model.Token{
+		Token:     token,
+		UserID:    user.ID,
+		Type:      model.TokenTypeImpersonation,
+		Expires:   exp,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "save impersonation token failed")
+	}
+
+	ctx = activity.WithActorID(activity.WithUserID(ctx, user.ID), actorID)
+	log.WithContext(ctx).Infof("impersonation started, token expires at %s", exp)
+	audit.NewAuditDomain(d.db).Record(ctx, user.ID, model.AuditActionImpersonated)
+
+	return map[string]interface{}{
+		"access": map[string]interface{}{
+			"token":   token,
+			"expires": exp,
+		},
+	}, nil
+}
+
+// CheckImpersonation fails once the token was dropped or the actor is no longer allowed to
+// impersonate the user, e.g. after being deleted or demoted
+func (d *authDomain) CheckImpersonation(ctx context.Context, token string, payload *jwt.TokenPayload) error {
+	if payload.Act == nil {
+		return stacktrace.NewError("not an impersonation token")
+	}
+
+	stored, err := d.db.Token().FindByToken(token, model.TokenTypeImpersonation)
+	if err != nil {
+		return stacktrace.Propagate(err, "find impersonation token failed")
+	}
+	if stored == nil || stored.UserID != payload.Sub {
+		return stacktrace.NewError("impersonation has ended")
+	}
+
+	user, err := d.db.User().FindByID(payload.Sub)
+	if err != nil {
+		return stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return stacktrace.NewError("user not found")
+	}
+	return d.authorizeImpersonation(ctx, payload.Act.Sub, user)
+}
+
+func (d *authDomain) authorizeImpersonation(ctx context.Context, actorID string, user *model.User) error {
+	roles := role.NewRoleDomain(d.db)
+	granted, err := roles.HasPermission(ctx, actorID, model.PermissionUsersImpersonate)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return stacktrace.NewError("missing permission %s", model.PermissionUsersImpersonate)
+	}
+	return roles.CanManage(ctx, actorID, user.Role)
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+
+	"prabogo/internal/domain/invitation"
+	"prabogo/internal/model"
+)
+
+// AcceptInvitation activates an invited user and signs them in, like Register does
+func (d *authDomain) AcceptInvitation(ctx context.Context, input model.AcceptInvitationInput) (*model.User, map[string]interface{}, error) {
+	user, err := invitation.NewInvitationDomain(d.db, d.email).Accept(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := d.generateAndSaveTokens(ctx, user.ID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"os"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+)
+
+// With AUTH_DRIVER=ldap, passwords are checked against the corporate directory
+const authDriverLDAP = "ldap"
+
+// directoryManaged reports whether passwords live in the directory rather than here
+func directoryManaged() bool {
+	return os.Getenv("AUTH_DRIVER") == authDriverLDAP
+}
+
+// checkDirectoryCredentials binds to the directory as the user and creates or updates the
+// local user from the entry; nil means the directory rejected the credentials
+func (d *authDomain) checkDirectoryCredentials(ctx context.Context, username, pass string) (*model.User, error) {
+	if d.directory == nil {
+		return nil, stacktrace.NewError("directory is not configured")
+	}
+
+	identity, err := d.directory.Authenticate(username, pass)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "directory authentication failed")
+	}
+	if identity == nil {
+		return nil, nil
+	}
+
+	user, err := d.ProvisionExternalUser(ctx, *identity)
+	if err != nil {
+		return nil, err
+	}
+	// Invitees still have to accept, like with local passwords
+	if user.Pending {
+		return nil, nil
+	}
+	return user, nil
+}
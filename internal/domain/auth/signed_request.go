@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/utils/signature"
+)
+
+// VerifySignedRequest authenticates an internal request signed with INTERNAL_KEY
+func (d *authDomain) VerifySignedRequest(ctx context.Context, request signature.Request, sig string) error {
+	key := os.Getenv("INTERNAL_KEY")
+	if key == "" {
+		return stacktrace.NewError("INTERNAL_KEY is not set")
+	}
+
+	maxSkew := signature.MaxSkew()
+	if err := signature.Verify(key, request, sig, time.Now(), maxSkew); err != nil {
+		return err
+	}
+
+	// A nonce must outlive every timestamp that would still be accepted with it
+	claimed, err := d.cache.Nonce().Claim(request.Nonce, 2*maxSkew)
+	if err != nil {
+		return stacktrace.Propagate(err, "claim request nonce failed")
+	}
+	if !claimed {
+		return signature.ErrReplayed
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// tokenPurgeBatchSize reads TOKEN_PURGE_BATCH_SIZE, 1000 when unset
+func tokenPurgeBatchSize() int {
+	size, _ := strconv.Atoi(os.Getenv("TOKEN_PURGE_BATCH_SIZE"))
+	if size <= 0 {
+		return 1000
+	}
+	return size
+}
+
+// PurgeTokens deletes expired and blacklisted tokens batch by batch, so one run never holds a
+// long lock on the tokens table, and returns how many rows were removed
+func (d *authDomain) PurgeTokens(ctx context.Context) (int, error) {
+	batchSize := tokenPurgeBatchSize()
+	purged := 0
+	for {
+		deleted, err := d.db.Token().PurgeExpired(time.Now(), batchSize)
+		if err != nil {
+			return purged, stacktrace.Propagate(err, "purge tokens failed")
+		}
+		purged += deleted
+
+		if deleted < batchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/domain/audit"
+	"prabogo/internal/domain/lockout"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
+	"prabogo/utils/activity"
+	"prabogo/utils/jwt"
+	"prabogo/utils/log"
+	"prabogo/utils/password"
+	"prabogo/utils/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+
+	// maxMfaAttempts wrong codes use up an mfa pending token
+	maxMfaAttempts   = 5
+	mfaAttemptPrefix = "mfa:"
+)
+
+func (d *authDomain) EnrollTwoFactor(ctx context.Context, userID string) (*model.TwoFactorEnrollment, error) {
+	user, err := d.db.User().FindByID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+
+	repo := d.db.TwoFactor()
+	existing, err := repo.FindByUserID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find two-factor failed")
+	}
+	if existing != nil && existing.Enabled {
+		return nil, stacktrace.NewError("two-factor authentication already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "generate secret failed")
+	}
+
+	// Stored disabled until the user proves the authenticator works
+	now := time.Now()
+	err = repo.Upsert(&model.TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		Enabled:   false,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "save two-factor failed")
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Prabogo"
+	}
+
+	return &model.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(issuer, user.Email, secret),
+	}, nil
+}
+
+func (d *authDomain) ConfirmTwoFactor(ctx context.Context, userID, code string) ([]string, error) {
+	twoFactor, err := d.db.TwoFactor().FindByUserID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find two-factor failed")
+	}
+	if twoFactor == nil {
+		return nil, stacktrace.NewError("two-factor enrollment not started")
+	}
+	if twoFactor.Enabled {
+		return nil, stacktrace.NewError("two-factor authentication already enabled")
+	}
+
+	step, ok := totp.Validate(code, twoFactor.Secret, time.Now())
+	if !ok {
+		return nil, stacktrace.NewError("invalid two-factor code")
+	}
+
+	codes, hashes := generateRecoveryCodes()
+	_, err = d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		twoFactor.Enabled = true
+		twoFactor.LastUsedStep = step
+		twoFactor.UpdatedAt = time.Now()
+		if err := repo.TwoFactor().Upsert(twoFactor); err != nil {
+			return nil, err
+		}
+		return nil, repo.TwoFactor().ReplaceRecoveryCodes(userID, hashes)
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "enable two-factor failed")
+	}
+	audit.NewAuditDomain(d.db).Record(ctx, userID, model.AuditActionTwoFactorEnabled)
+
+	// Plain codes are only ever shown here
+	return codes, nil
+}
+
+func (d *authDomain) DisableTwoFactor(ctx context.Context, userID, code string) error {
+	twoFactor, err := d.enabledTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := d.verifySecondFactor(twoFactor, code); err != nil || !ok {
+		return stacktrace.NewError("invalid two-factor code")
+	}
+
+	if err := d.db.TwoFactor().DeleteByUserID(userID); err != nil {
+		return stacktrace.Propagate(err, "disable two-factor failed")
+	}
+	audit.NewAuditDomain(d.db).Record(ctx, userID, model.AuditActionTwoFactorDisabled)
+	return nil
+}
+
+func (d *authDomain) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	twoFactor, err := d.enabledTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, err := d.verifySecondFactor(twoFactor, code); err != nil || !ok {
+		return nil, stacktrace.NewError("invalid two-factor code")
+	}
+
+	codes, hashes := generateRecoveryCodes()
+	if err := d.db.TwoFactor().ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, stacktrace.Propagate(err, "save recovery codes failed")
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactorLogin exchanges the mfa pending token from Login and a TOTP or recovery code
+// for the real token pair
+func (d *authDomain) VerifyTwoFactorLogin(ctx context.Context, mfaToken, code string) (*model.User, map[string]interface{}, error) {
+	tokenRepo := d.db.Token()
+	token, err := tokenRepo.FindByToken(mfaToken, model.TokenTypeMfaPending)
+	if err != nil || token == nil {
+		return nil, nil, stacktrace.NewError("invalid or expired token")
+	}
+
+	payload, err := jwt.ValidateLocalToken(mfaToken)
+	if err != nil || payload.Type != model.TokenTypeMfaPending {
+		return nil, nil, stacktrace.NewError("invalid or expired token")
+	}
+
+	user, err := d.db.User().FindByID(token.UserID)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, nil, stacktrace.NewError("user not found")
+	}
+
+	// Code guesses count against the same lockout as password guesses
+	ip, _ := activity.GetIPAddress(ctx)
+	guard := lockout.NewLockoutDomain(d.db, d.cache)
+	if err := guard.Check(ctx, user.Email, ip); err != nil {
+		return nil, nil, err
+	}
+
+	twoFactor, err := d.enabledTwoFactor(token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ok, err := d.verifySecondFactor(twoFactor, code); err != nil || !ok {
+		d.registerSecondFactorFailure(ctx, user, token)
+		return nil, nil, stacktrace.NewError("invalid two-factor code")
+	}
+
+	if err := guard.Reset(ctx, user.Email); err != nil {
+		log.WithContext(ctx).Warnf("reset login failures failed: %v", err)
+	}
+
+	// Consume token
+	if err := tokenRepo.Delete(token.ID); err != nil {
+		return nil, nil, stacktrace.Propagate(err, "consume mfa token failed")
+	}
+
+	if err := d.cancelScheduledDeletion(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := d.generateAndSaveTokens(ctx, user.ID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// registerSecondFactorFailure feeds the login lockout and drops the mfa pending token after
+// maxMfaAttempts wrong codes, so the password has to be entered again
+func (d *authDomain) registerSecondFactorFailure(ctx context.Context, user *model.User, token *model.Token) {
+	ip, _ := activity.GetIPAddress(ctx)
+	if err := lockout.NewLockoutDomain(d.db, d.cache).RegisterFailure(ctx, user.Email, ip); err != nil {
+		log.WithContext(ctx).Warnf("register login failure failed: %v", err)
+	}
+
+	failures, err := d.cache.LoginAttempt().RegisterFailure(mfaAttemptPrefix+strconv.Itoa(token.ID), time.Until(token.Expires))
+	if err != nil {
+		log.WithContext(ctx).Warnf("register two-factor failure failed: %v", err)
+		return
+	}
+	if failures < maxMfaAttempts {
+		return
+	}
+	if err := d.db.Token().Delete(token.ID); err != nil {
+		log.WithContext(ctx).Warnf("drop mfa token failed: %v", err)
+	}
+}
+
+// issueMfaPendingToken is what Login hands out instead of a token pair when 2FA is on
+func (d *authDomain) issueMfaPendingToken(userID string) (map[string]interface{}, error) {
+	token, exp, err := jwt.GenerateMfaPendingToken(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.db.Token().Create(&model.Token{
+		Token:     token,
+		UserID:    userID,
+		Type:      model.TokenTypeMfaPending,
+		Expires:   exp,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"mfa": map[string]interface{}{
+			"token":   token,
+			"expires": exp,
+		},
+	}, nil
+}
+
+func (d *authDomain) enabledTwoFactor(userID string) (*model.TwoFactor, error) {
+	twoFactor, err := d.db.TwoFactor().FindByUserID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find two-factor failed")
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return nil, stacktrace.NewError("two-factor authentication not enabled")
+	}
+	return twoFactor, nil
+}
+
+// verifySecondFactor accepts a TOTP code (each time step only once) or an unused recovery code
+func (d *authDomain) verifySecondFactor(twoFactor *model.TwoFactor, code string) (bool, error) {
+	repo := d.db.TwoFactor()
+
+	if step, ok := totp.Validate(code, twoFactor.Secret, time.Now()); ok {
+		if step <= twoFactor.LastUsedStep {
+			return false, nil
+		}
+		used, err := repo.UseStep(twoFactor.UserID, step)
+		if err != nil {
+			return false, stacktrace.Propagate(err, "save two-factor failed")
+		}
+		return used, nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	used, err := repo.UseRecoveryCode(twoFactor.UserID, password.HashToken(normalized))
+	if err != nil {
+		return false, stacktrace.Propagate(err, "use recovery code failed")
+	}
+	return used, nil
+}
+
+func generateRecoveryCodes() ([]string, []string) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := utils.GenerateSecureToken(5)
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, password.HashToken(raw))
+	}
+	return codes, hashes
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
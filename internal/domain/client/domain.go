@@ -8,6 +8,7 @@ import (
 
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
 )
 
 type ClientDomain interface {
@@ -16,6 +17,9 @@ type ClientDomain interface {
 	DeleteByFilter(ctx context.Context, filter model.ClientFilter) error
 	PublishUpsert(ctx context.Context, inputs []model.ClientInput) error
 	IsExists(ctx context.Context, bearerKey string) (bool, error)
+	// FindByBearerKey resolves a client and its scopes through the cache, then the database;
+	// nil when unknown
+	FindByBearerKey(ctx context.Context, bearerKey string) (*model.Client, error)
 }
 
 type clientDomain struct {
@@ -43,6 +47,11 @@ func (s *clientDomain) Upsert(ctx context.Context, inputs []model.ClientInput) (
 
 	var filter model.ClientFilter
 	for i := range inputs {
+		for _, scope := range inputs[i].Scopes {
+			if !utils.IsInList(model.ClientScopes, scope) {
+				return nil, stacktrace.NewError("unknown scope %s", scope)
+			}
+		}
 		model.ClientPrepare(&inputs[i])
 		filter.BearerKeys = append(filter.BearerKeys, inputs[i].BearerKey)
 	}
@@ -58,6 +67,24 @@ func (s *clientDomain) Upsert(ctx context.Context, inputs []model.ClientInput) (
 		return nil, stacktrace.Propagate(err, "find client by filter error")
 	}
 
+	for i := range results {
+		for _, input := range inputs {
+			if input.BearerKey != results[i].BearerKey || input.Scopes == nil {
+				continue
+			}
+			err = databaseClientPort.SetScopes(results[i].ID, input.Scopes)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "set client scopes error")
+			}
+			results[i].Scopes = input.Scopes
+		}
+	}
+
+	// ClientAuth must not keep serving the old name and scopes from the cache
+	if err := s.uncache(results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -81,11 +108,27 @@ func (s *clientDomain) DeleteByFilter(ctx context.Context, filter model.ClientFi
 	}
 
 	databaseClientPort := s.databasePort.Client()
-	err := databaseClientPort.DeleteByFilter(filter)
+	clients, err := databaseClientPort.FindByFilter(filter, false)
+	if err != nil {
+		return stacktrace.Propagate(err, "find client by filter error")
+	}
+
+	err = databaseClientPort.DeleteByFilter(filter)
 	if err != nil {
 		return stacktrace.Propagate(err, "delete client by filter error")
 	}
 
+	// A deleted client's bearer key would otherwise still pass ClientAuth from the cache
+	return s.uncache(clients)
+}
+
+func (s *clientDomain) uncache(clients []model.Client) error {
+	cacheClientPort := s.cachePort.Client()
+	for _, client := range clients {
+		if err := cacheClientPort.Delete(client.BearerKey); err != nil {
+			return stacktrace.Propagate(err, "delete client from cache error")
+		}
+	}
 	return nil
 }
 
@@ -104,40 +147,54 @@ func (s *clientDomain) PublishUpsert(ctx context.Context, inputs []model.ClientI
 }
 
 func (s *clientDomain) IsExists(ctx context.Context, bearerKey string) (bool, error) {
+	client, err := s.FindByBearerKey(ctx, bearerKey)
+	if err != nil {
+		return false, err
+	}
+
+	return client != nil, nil
+}
+
+func (s *clientDomain) FindByBearerKey(ctx context.Context, bearerKey string) (*model.Client, error) {
 	if bearerKey == "" {
-		return false, stacktrace.NewError("bearerKey is empty")
+		return nil, stacktrace.NewError("bearerKey is empty")
 	}
 
-	var exists bool
 	cacheClientPort := s.cachePort.Client()
-	_, err := cacheClientPort.Get(bearerKey)
+	cached, err := cacheClientPort.Get(bearerKey)
+	if err == nil {
+		return &cached, nil
+	}
+	if err != redis.Nil {
+		return nil, stacktrace.Propagate(err, "get client from cache error")
+	}
+
+	databaseClientPort := s.databasePort.Client()
+	exists, err := databaseClientPort.IsExists(bearerKey)
 	if err != nil {
-		if err == redis.Nil {
-			databaseClientPort := s.databasePort.Client()
-			exists, err = databaseClientPort.IsExists(bearerKey)
-			if err != nil {
-				return false, stacktrace.Propagate(err, "check if client exists error")
-			}
+		return nil, stacktrace.Propagate(err, "check if client exists error")
+	}
+	if !exists {
+		return nil, nil
+	}
 
-			if exists {
-				client, findErr := databaseClientPort.FindByFilter(model.ClientFilter{BearerKeys: []string{bearerKey}}, false)
-				if findErr != nil {
-					return false, stacktrace.Propagate(findErr, "find client by filter error")
-				}
-
-				if len(client) > 0 {
-					setErr := cacheClientPort.Set(client[0])
-					if setErr != nil {
-						return false, stacktrace.Propagate(setErr, "set client to cache error")
-					}
-				}
-			}
-		} else {
-			return false, stacktrace.Propagate(err, "get client from cache error")
-		}
-	} else {
-		exists = true
+	clients, err := databaseClientPort.FindByFilter(model.ClientFilter{BearerKeys: []string{bearerKey}}, false)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find client by filter error")
+	}
+	if len(clients) == 0 {
+		return nil, nil
+	}
+
+	clients[0].Scopes, err = databaseClientPort.FindScopes(clients[0].ID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find client scopes error")
+	}
+
+	err = cacheClientPort.Set(clients[0])
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "set client to cache error")
 	}
 
-	return exists, nil
+	return &clients[0], nil
 }
@@ -33,7 +33,7 @@ func TestClient(t *testing.T) {
 		mockMessagePort.EXPECT().Client().Return(mockClientMessagePort).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
 
-		clientDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort)
+		clientDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mock_outbound_port.NewMockEmailPort(mockCtrl), mock_outbound_port.NewMockDirectoryPort(mockCtrl))
 
 		inputs := []model.ClientInput{
 			{
@@ -83,12 +83,33 @@ func TestClient(t *testing.T) {
 			Convey("Success", func() {
 				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
 				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().Delete("test-bearer-key").Return(nil).Times(1)
 
 				results, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldBeNil)
 				So(results, ShouldNotBeEmpty)
 				So(results[0].Name, ShouldEqual, "Test Client")
 			})
+
+			Convey("Unknown scope", func() {
+				_, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{
+					{Name: "Test Client", Scopes: []string{"everything"}},
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success with scopes", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().SetScopes(1, []string{model.ScopeUsersRead}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete("test-bearer-key").Return(nil).Times(1)
+
+				results, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{
+					{Name: "Test Client", BearerKey: "test-bearer-key", Scopes: []string{model.ScopeUsersRead}},
+				})
+				So(err, ShouldBeNil)
+				So(results[0].Scopes, ShouldResemble, []string{model.ScopeUsersRead})
+			})
 		})
 
 		Convey("FindByFilter", func() {
@@ -121,14 +142,26 @@ func TestClient(t *testing.T) {
 			})
 
 			Convey("Database client delete by filter error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(filter, false).Return(outputs, nil).Times(1)
 				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(errors.New("error")).Times(1)
 
 				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Success", func() {
+			Convey("Cache client delete error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(filter, false).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete("test-bearer-key").Return(errors.New("error")).Times(1)
+
+				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success drops the cached bearer key", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(filter, false).Return(outputs, nil).Times(1)
 				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete("test-bearer-key").Return(nil).Times(1)
 
 				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
 				So(err, ShouldBeNil)
@@ -192,6 +225,7 @@ func TestClient(t *testing.T) {
 				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
 
 				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindScopes(1).Return([]string{}, nil).Times(1)
 				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
@@ -202,6 +236,7 @@ func TestClient(t *testing.T) {
 				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
 				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
 				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindScopes(1).Return([]string{}, nil).Times(1)
 				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(nil).Times(1)
 
 				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
@@ -216,5 +251,38 @@ func TestClient(t *testing.T) {
 				So(err, ShouldBeNil)
 			})
 		})
+
+		Convey("FindByBearerKey", func() {
+			Convey("Cache client exists", func() {
+				mockClientCachePort.EXPECT().Get("test-bearer-key").Return(outputs[0], nil).Times(1)
+
+				result, err := clientDomain.Client().FindByBearerKey(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, &outputs[0])
+			})
+
+			Convey("Database client exists", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().IsExists("test-bearer-key").Return(true, nil).Times(1)
+				withScopes := outputs[0]
+				withScopes.Scopes = []string{model.ScopeUsersRead}
+				mockClientDatabasePort.EXPECT().FindByFilter(model.ClientFilter{BearerKeys: []string{"test-bearer-key"}}, false).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindScopes(1).Return([]string{model.ScopeUsersRead}, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(withScopes).Return(nil).Times(1)
+
+				result, err := clientDomain.Client().FindByBearerKey(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, &withScopes)
+			})
+
+			Convey("Unknown bearer key", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(false, nil).Times(1)
+
+				result, err := clientDomain.Client().FindByBearerKey(context.Background(), "unknown-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeNil)
+			})
+		})
 	})
 }
@@ -0,0 +1,256 @@
+package invitation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/domain/registration"
+	"prabogo/internal/domain/role"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
+	"prabogo/utils/password"
+)
+
+const tokenBytes = 32
+
+type InvitationDomain interface {
+	// Invite creates a pending user with the role and emails them a single-use link. Like user
+	// creation it refuses actors lacking any permission of the role, see RoleDomain.CanManage
+	Invite(ctx context.Context, actorID string, input model.InvitationInput) (*model.Invitation, error)
+	List(ctx context.Context) ([]model.Invitation, error)
+	// Resend emails a fresh link with a new expiry; earlier links stop working
+	Resend(ctx context.Context, id string) (*model.Invitation, error)
+	// Revoke withdraws the invitation and removes the pending user
+	Revoke(ctx context.Context, id string) error
+	// Accept activates the invited user with the name and password they chose
+	Accept(ctx context.Context, input model.AcceptInvitationInput) (*model.User, error)
+}
+
+type invitationDomain struct {
+	db    outbound_port.DatabasePort
+	email outbound_port.EmailPort
+}
+
+func NewInvitationDomain(db outbound_port.DatabasePort, email outbound_port.EmailPort) InvitationDomain {
+	return &invitationDomain{
+		db:    db,
+		email: email,
+	}
+}
+
+// lifetime reads INVITATION_EXPIRATION_DAYS, 7 days when unset
+func lifetime() time.Duration {
+	days, _ := strconv.Atoi(os.Getenv("INVITATION_EXPIRATION_DAYS"))
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (d *invitationDomain) Invite(ctx context.Context, actorID string, input model.InvitationInput) (*model.Invitation, error) {
+	email := strings.TrimSpace(input.Email)
+	if email == "" {
+		return nil, stacktrace.NewError("email is required")
+	}
+
+	if err := registration.NewRegistrationDomain(d.db).CheckInvite(ctx); err != nil {
+		return nil, err
+	}
+
+	exists, err := d.db.User().ExistsByEmail(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "check email failed")
+	}
+	if exists {
+		return nil, stacktrace.NewError("email already taken")
+	}
+
+	roleName := input.Role
+	if roleName == "" {
+		roleName = model.RoleUser
+	}
+	if err := role.NewRoleDomain(d.db).CanManage(ctx, actorID, roleName); err != nil {
+		return nil, err
+	}
+
+	// Never handed out, it only fills the column until the invitee picks a password
+	hashed, err := password.HashPassword(utils.GenerateSecureToken(32))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "hash password failed")
+	}
+
+	user := &model.User{
+		Name:     strings.TrimSpace(input.Name),
+		Email:    email,
+		Password: hashed,
+		Role:     roleName,
+		Pending:  true,
+	}
+	model.UserPrepare(user)
+
+	raw := utils.GenerateSecureToken(tokenBytes)
+	now := time.Now()
+	invitation := &model.Invitation{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: password.HashToken(raw),
+		ExpiresAt: now.Add(lifetime()),
+		SentAt:    now,
+		CreatedAt: now,
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      user.Role,
+	}
+	if actorID != "" {
+		invitation.InvitedBy = &actorID
+	}
+
+	// Sending last rolls everything back when the email cannot go out
+	_, err = d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		if err := repo.User().Create(user); err != nil {
+			return nil, err
+		}
+		if err := repo.Invitation().Create(invitation); err != nil {
+			return nil, err
+		}
+		return nil, d.send(invitation, raw)
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "create invitation failed")
+	}
+
+	return invitation, nil
+}
+
+func (d *invitationDomain) List(ctx context.Context) ([]model.Invitation, error) {
+	invitations, err := d.db.Invitation().FindAll()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find invitations failed")
+	}
+	return invitations, nil
+}
+
+func (d *invitationDomain) Resend(ctx context.Context, id string) (*model.Invitation, error) {
+	invitation, err := d.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := utils.GenerateSecureToken(tokenBytes)
+	now := time.Now()
+	invitation.TokenHash = password.HashToken(raw)
+	invitation.ExpiresAt = now.Add(lifetime())
+	invitation.SentAt = now
+
+	_, err = d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		if err := repo.Invitation().Renew(invitation.ID, invitation.TokenHash, invitation.ExpiresAt, invitation.SentAt); err != nil {
+			return nil, err
+		}
+		return nil, d.send(invitation, raw)
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "resend invitation failed")
+	}
+
+	return invitation, nil
+}
+
+func (d *invitationDomain) Revoke(ctx context.Context, id string) error {
+	invitation, err := d.get(id)
+	if err != nil {
+		return err
+	}
+
+	// The invitation row cascades away with the user
+	if err := d.db.User().Delete(invitation.UserID); err != nil {
+		return stacktrace.Propagate(err, "delete pending user failed")
+	}
+	return nil
+}
+
+func (d *invitationDomain) Accept(ctx context.Context, input model.AcceptInvitationInput) (*model.User, error) {
+	if input.Token == "" {
+		return nil, stacktrace.NewError("invalid or expired invitation")
+	}
+
+	tokenHash := password.HashToken(input.Token)
+	invitation, err := d.db.Invitation().FindByHash(tokenHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find invitation failed")
+	}
+	if invitation == nil || invitation.Expired() {
+		return nil, stacktrace.NewError("invalid or expired invitation")
+	}
+
+	user, err := d.db.User().FindByID(invitation.UserID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+
+	if name := strings.TrimSpace(input.Name); name != "" {
+		user.Name = name
+	}
+	if user.Name == "" {
+		return nil, stacktrace.NewError("name is required")
+	}
+
+	if err := password.ValidatePassword(input.Password, user.Name, user.Email); err != nil {
+		return nil, err
+	}
+	hashed, err := password.HashPassword(input.Password)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "hash password failed")
+	}
+
+	user.Password = hashed
+	user.Pending = false
+	// The token only ever reached the invitee's inbox
+	user.IsEmailVerified = true
+	user.UpdatedAt = time.Now()
+
+	// Consumed first, so of two racing accepts only one sets the password
+	_, err = d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		consumed, err := repo.Invitation().Consume(invitation.ID, tokenHash)
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			return nil, stacktrace.NewError("invalid or expired invitation")
+		}
+		return nil, repo.User().Update(user)
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "accept invitation failed")
+	}
+
+	return user, nil
+}
+
+func (d *invitationDomain) get(id string) (*model.Invitation, error) {
+	invitation, err := d.db.Invitation().FindByID(id)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find invitation failed")
+	}
+	if invitation == nil {
+		return nil, stacktrace.NewError("invitation not found")
+	}
+	return invitation, nil
+}
+
+func (d *invitationDomain) send(invitation *model.Invitation, raw string) error {
+	acceptURL := fmt.Sprintf("http://localhost:3000/accept-invitation?token=%s", raw)
+	body := fmt.Sprintf("You have been invited to Prabogo. Set your name and password here: %s\nThe link expires at %s and works once.",
+		acceptURL, invitation.ExpiresAt.Format(time.RFC1123))
+	return d.email.SendEmail(invitation.Email, "You're Invited", body)
+}
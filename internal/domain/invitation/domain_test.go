@@ -0,0 +1,238 @@
+package invitation_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/password"
+)
+
+var tokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+func TestInvitation(t *testing.T) {
+	Convey("Test Invitation", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockInvitationDatabasePort := mock_outbound_port.NewMockInvitationDatabasePort(mockCtrl)
+		mockRegistrationPolicyDatabasePort := mock_outbound_port.NewMockRegistrationPolicyDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Invitation().Return(mockInvitationDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().RegistrationPolicy().Return(mockRegistrationPolicyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+			return fn(mockDatabasePort)
+		}).AnyTimes()
+
+		invitationDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort).Invitation()
+
+		Convey("Invite", func() {
+			input := model.InvitationInput{Email: "new@example.com", Name: "New Hire", Role: "support"}
+			policy := model.DefaultRegistrationPolicy()
+			mockRegistrationPolicyDatabasePort.EXPECT().Get().DoAndReturn(func() (*model.RegistrationPolicy, error) { return policy, nil }).AnyTimes()
+			mockUserDatabasePort.EXPECT().FindByID("admin-1").Return(&model.User{ID: "admin-1", Role: "manager"}, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().FindByName("manager").Return(&model.Role{
+				Name:        "manager",
+				Permissions: []string{model.PermissionUsersRead, model.PermissionUsersWrite},
+			}, nil).AnyTimes()
+
+			Convey("Email is required", func() {
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", model.InvitationInput{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Closed registration refuses new invitations", func() {
+				policy.Mode = model.RegistrationModeClosed
+				mockUserDatabasePort.EXPECT().ExistsByEmail(gomock.Any()).Times(0)
+
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				var refused *model.RegistrationError
+				So(errors.As(err, &refused), ShouldBeTrue)
+				So(refused.Code, ShouldEqual, model.RegistrationErrorClosed)
+			})
+
+			Convey("Invite-only registration still accepts invitations", func() {
+				policy.Mode = model.RegistrationModeInviteOnly
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(true, nil).Times(1)
+
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				So(err.Error(), ShouldContainSubstring, "email already taken")
+			})
+
+			Convey("Email already taken", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(true, nil).Times(1)
+
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Role not found", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(nil, nil).Times(1)
+
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Role with a permission the actor lacks", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{
+					Name:        "support",
+					Permissions: []string{model.PermissionUsersImpersonate},
+				}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).Times(0)
+
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				So(err.Error(), ShouldContainSubstring, "cannot manage a user with more permissions than you")
+			})
+
+			Convey("Email failure rolls the invitation back", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				mockInvitationDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				mockEmailPort.EXPECT().SendEmail("new@example.com", gomock.Any(), gomock.Any()).Return(errors.New("smtp down")).Times(1)
+
+				_, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success creates a pending user and emails the token", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(1)
+				var user *model.User
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(u *model.User) error {
+					user = u
+					return nil
+				}).Times(1)
+				var stored *model.Invitation
+				mockInvitationDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(i *model.Invitation) error {
+					stored = i
+					return nil
+				}).Times(1)
+				var body string
+				mockEmailPort.EXPECT().SendEmail("new@example.com", gomock.Any(), gomock.Any()).DoAndReturn(func(to, subject, b string) error {
+					body = b
+					return nil
+				}).Times(1)
+
+				invitation, err := invitationDomain.Invite(context.Background(), "admin-1", input)
+				So(err, ShouldBeNil)
+				So(user.Pending, ShouldBeTrue)
+				So(user.IsEmailVerified, ShouldBeFalse)
+				So(user.Role, ShouldEqual, "support")
+				So(invitation.UserID, ShouldEqual, user.ID)
+				So(*invitation.InvitedBy, ShouldEqual, "admin-1")
+				So(invitation.ExpiresAt, ShouldHappenAfter, time.Now().Add(6*24*time.Hour))
+
+				// Only the hash of the emailed token is stored
+				match := tokenPattern.FindStringSubmatch(body)
+				So(match, ShouldHaveLength, 2)
+				So(stored.TokenHash, ShouldEqual, password.HashToken(match[1]))
+			})
+		})
+
+		Convey("Resend renews the token", func() {
+			existing := &model.Invitation{ID: "i", UserID: "u", TokenHash: "old-hash", Email: "new@example.com", ExpiresAt: time.Now().Add(-time.Hour)}
+
+			Convey("Invitation not found", func() {
+				mockInvitationDatabasePort.EXPECT().FindByID("i").Return(nil, nil).Times(1)
+
+				_, err := invitationDomain.Resend(context.Background(), "i")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockInvitationDatabasePort.EXPECT().FindByID("i").Return(existing, nil).Times(1)
+				mockInvitationDatabasePort.EXPECT().Renew("i", gomock.Not("old-hash"), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockEmailPort.EXPECT().SendEmail("new@example.com", gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				invitation, err := invitationDomain.Resend(context.Background(), "i")
+				So(err, ShouldBeNil)
+				So(invitation.Expired(), ShouldBeFalse)
+			})
+		})
+
+		Convey("Revoke removes the pending user", func() {
+			mockInvitationDatabasePort.EXPECT().FindByID("i").Return(&model.Invitation{ID: "i", UserID: "u"}, nil).Times(1)
+			mockUserDatabasePort.EXPECT().Delete("u").Return(nil).Times(1)
+
+			So(invitationDomain.Revoke(context.Background(), "i"), ShouldBeNil)
+		})
+
+		Convey("Accept", func() {
+			raw := "0123456789abcdef"
+			invitation := &model.Invitation{ID: "i", UserID: "u", ExpiresAt: time.Now().Add(time.Hour)}
+			pending := &model.User{ID: "u", Email: "new@example.com", Name: "New Hire", Pending: true}
+			input := model.AcceptInvitationInput{Token: raw, Name: "Jamie Doe", Password: "correct7horse"}
+
+			Convey("Unknown token", func() {
+				mockInvitationDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(nil, nil).Times(1)
+
+				_, err := invitationDomain.Accept(context.Background(), input)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Expired invitation", func() {
+				invitation.ExpiresAt = time.Now().Add(-time.Minute)
+				mockInvitationDatabasePort.EXPECT().FindByHash(gomock.Any()).Return(invitation, nil).Times(1)
+
+				_, err := invitationDomain.Accept(context.Background(), input)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Weak password", func() {
+				mockInvitationDatabasePort.EXPECT().FindByHash(gomock.Any()).Return(invitation, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID("u").Return(pending, nil).Times(1)
+
+				input.Password = "short"
+				_, err := invitationDomain.Accept(context.Background(), input)
+				var policy *password.PolicyError
+				So(errors.As(err, &policy), ShouldBeTrue)
+			})
+
+			Convey("Invitation consumed by a racing accept", func() {
+				mockInvitationDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(invitation, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID("u").Return(pending, nil).Times(1)
+				mockInvitationDatabasePort.EXPECT().Consume("i", password.HashToken(raw)).Return(false, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Times(0)
+
+				_, err := invitationDomain.Accept(context.Background(), input)
+				So(err.Error(), ShouldContainSubstring, "invalid or expired invitation")
+			})
+
+			Convey("Success activates the user and consumes the invitation", func() {
+				mockInvitationDatabasePort.EXPECT().FindByHash(password.HashToken(raw)).Return(invitation, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID("u").Return(pending, nil).Times(1)
+				mockInvitationDatabasePort.EXPECT().Consume("i", password.HashToken(raw)).Return(true, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+
+				user, err := invitationDomain.Accept(context.Background(), input)
+				So(err, ShouldBeNil)
+				So(user.Pending, ShouldBeFalse)
+				So(user.IsEmailVerified, ShouldBeTrue)
+				So(user.Name, ShouldEqual, "Jamie Doe")
+				So(password.CheckPassword("correct7horse", user.Password), ShouldBeTrue)
+			})
+		})
+	})
+}
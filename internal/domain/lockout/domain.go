@@ -0,0 +1,248 @@
+package lockout
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
+)
+
+const (
+	accountPrefix = "account:"
+	ipPrefix      = "ip:"
+	// delayPrefix keys the short waits between failures, kept apart from real lockouts
+	delayPrefix = "delay:"
+
+	maxDelay = 30 * time.Second
+)
+
+type LockoutDomain interface {
+	// Check returns a *model.LoginThrottledError while the account or address has to wait
+	Check(ctx context.Context, email, ip string) error
+	RegisterFailure(ctx context.Context, email, ip string) error
+	Reset(ctx context.Context, email string) error
+
+	List(ctx context.Context) ([]model.Lockout, error)
+	Get(ctx context.Context, userID string) (*model.Lockout, error)
+	Unlock(ctx context.Context, userID string) error
+}
+
+type lockoutDomain struct {
+	db    outbound_port.DatabasePort
+	cache outbound_port.CachePort
+}
+
+func NewLockoutDomain(db outbound_port.DatabasePort, cache outbound_port.CachePort) LockoutDomain {
+	return &lockoutDomain{
+		db:    db,
+		cache: cache,
+	}
+}
+
+func accountKey(email string) string {
+	return accountPrefix + strings.ToLower(strings.TrimSpace(email))
+}
+
+func (d *lockoutDomain) Check(ctx context.Context, email, ip string) error {
+	keys := []string{accountKey(email), delayPrefix + accountKey(email)}
+	if ip != "" {
+		keys = append(keys, ipPrefix+ip)
+	}
+
+	var wait time.Duration
+	for _, key := range keys {
+		left, err := d.cache.LoginAttempt().LockedFor(key)
+		if err != nil {
+			// Fail open: an unreachable cache must not lock everyone out
+			log.WithContext(ctx).Warnf("check login lockout failed: %v", err)
+			return nil
+		}
+		if left > wait {
+			wait = left
+		}
+	}
+
+	if wait > 0 {
+		return &model.LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RegisterFailure delays the next attempt on the account a little more after each failure
+// and locks it, or the address, once its threshold is reached
+func (d *lockoutDomain) RegisterFailure(ctx context.Context, email, ip string) error {
+	policy := loadPolicy()
+	attempts := d.cache.LoginAttempt()
+
+	key := accountKey(email)
+	failures, err := attempts.RegisterFailure(key, policy.window)
+	if err != nil {
+		return stacktrace.Propagate(err, "register login failure failed")
+	}
+
+	if failures >= policy.maxAttempts {
+		if err := attempts.Lock(key, policy.lockout); err != nil {
+			return stacktrace.Propagate(err, "lock account failed")
+		}
+		log.WithContext(ctx).
+			WithField("event", "account_locked").
+			WithField("email", email).
+			Warn("account locked after repeated login failures")
+	} else if delay := progressiveDelay(failures); delay > 0 {
+		if err := attempts.Lock(delayPrefix+key, delay); err != nil {
+			return stacktrace.Propagate(err, "delay account failed")
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	failures, err = attempts.RegisterFailure(ipPrefix+ip, policy.window)
+	if err != nil {
+		return stacktrace.Propagate(err, "register login failure failed")
+	}
+	if failures >= policy.maxAttemptsPerIP {
+		if err := attempts.Lock(ipPrefix+ip, policy.lockout); err != nil {
+			return stacktrace.Propagate(err, "lock address failed")
+		}
+		log.WithContext(ctx).
+			WithField("event", "address_locked").
+			WithField("ip", ip).
+			Warn("address locked after repeated login failures")
+	}
+	return nil
+}
+
+// Reset forgets the failures of an account after a successful login; the address
+// counter is left alone so one good account cannot launder guesses on others
+func (d *lockoutDomain) Reset(ctx context.Context, email string) error {
+	attempts := d.cache.LoginAttempt()
+	if err := attempts.Reset(accountKey(email)); err != nil {
+		return stacktrace.Propagate(err, "reset login failures failed")
+	}
+	return attempts.Reset(delayPrefix + accountKey(email))
+}
+
+func (d *lockoutDomain) List(ctx context.Context) ([]model.Lockout, error) {
+	locked, err := d.cache.LoginAttempt().Locked(accountPrefix)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "list locked accounts failed")
+	}
+
+	lockouts := []model.Lockout{}
+	for key, left := range locked {
+		email := strings.TrimPrefix(key, accountPrefix)
+		user, err := d.db.User().FindByEmail(email)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "find user failed")
+		}
+		// Guesses against unknown addresses are throttled too, but are no account
+		if user == nil {
+			continue
+		}
+		lockouts = append(lockouts, model.Lockout{
+			UserID:      user.ID,
+			Email:       user.Email,
+			LockedUntil: time.Now().Add(left),
+		})
+	}
+	return lockouts, nil
+}
+
+func (d *lockoutDomain) Get(ctx context.Context, userID string) (*model.Lockout, error) {
+	user, err := d.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := d.cache.LoginAttempt().LockedFor(accountKey(user.Email))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "check lockout failed")
+	}
+	if left <= 0 {
+		return nil, nil
+	}
+	return &model.Lockout{
+		UserID:      user.ID,
+		Email:       user.Email,
+		LockedUntil: time.Now().Add(left),
+	}, nil
+}
+
+func (d *lockoutDomain) Unlock(ctx context.Context, userID string) error {
+	user, err := d.findUser(userID)
+	if err != nil {
+		return err
+	}
+	return d.Reset(ctx, user.Email)
+}
+
+func (d *lockoutDomain) findUser(userID string) (*model.User, error) {
+	user, err := d.db.User().FindByID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+	return user, nil
+}
+
+// progressiveDelay doubles the wait with every failure past the first: 1s, 2s, 4s, ... up to maxDelay
+func progressiveDelay(failures int64) time.Duration {
+	if failures < 2 {
+		return 0
+	}
+	delay := time.Second
+	for i := int64(2); i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+type policy struct {
+	maxAttempts      int64
+	maxAttemptsPerIP int64
+	window           time.Duration
+	lockout          time.Duration
+}
+
+func loadPolicy() policy {
+	maxAttempts, _ := strconv.ParseInt(os.Getenv("LOGIN_MAX_ATTEMPTS"), 10, 64)
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	maxAttemptsPerIP, _ := strconv.ParseInt(os.Getenv("LOGIN_MAX_ATTEMPTS_PER_IP"), 10, 64)
+	if maxAttemptsPerIP <= 0 {
+		maxAttemptsPerIP = 20
+	}
+
+	windowMinutes, _ := strconv.Atoi(os.Getenv("LOGIN_ATTEMPT_WINDOW_MINUTES"))
+	if windowMinutes <= 0 {
+		windowMinutes = 15
+	}
+
+	lockoutMinutes, _ := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_MINUTES"))
+	if lockoutMinutes <= 0 {
+		lockoutMinutes = 15
+	}
+
+	return policy{
+		maxAttempts:      maxAttempts,
+		maxAttemptsPerIP: maxAttemptsPerIP,
+		window:           time.Duration(windowMinutes) * time.Minute,
+		lockout:          time.Duration(lockoutMinutes) * time.Minute,
+	}
+}
@@ -0,0 +1,119 @@
+package lockout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/lockout"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestLockout(t *testing.T) {
+	Convey("Test Lockout", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockLoginAttemptCachePort := mock_outbound_port.NewMockLoginAttemptCachePort(mockCtrl)
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().LoginAttempt().Return(mockLoginAttemptCachePort).AnyTimes()
+
+		lockoutDomain := lockout.NewLockoutDomain(mockDatabasePort, mockCachePort)
+
+		user := &model.User{ID: "user-1", Email: "Test@Example.com"}
+
+		Convey("Check", func() {
+			Convey("Cache error fails open", func() {
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), errors.New("error")).Times(1)
+
+				So(lockoutDomain.Check(context.Background(), user.Email, "10.0.0.1"), ShouldBeNil)
+			})
+
+			Convey("Address lock applies to any account", func() {
+				mockLoginAttemptCachePort.EXPECT().LockedFor("ip:10.0.0.1").Return(time.Minute, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+
+				err := lockoutDomain.Check(context.Background(), "other@example.com", "10.0.0.1")
+				var throttled *model.LoginThrottledError
+				So(errors.As(err, &throttled), ShouldBeTrue)
+				So(throttled.RetrySeconds(), ShouldEqual, 60)
+			})
+		})
+
+		Convey("RegisterFailure delays progressively", func() {
+			for failures, delay := range map[int64]time.Duration{2: time.Second, 3: 2 * time.Second, 4: 4 * time.Second} {
+				mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(failures, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Lock("delay:account:test@example.com", delay).Return(nil).Times(1)
+
+				So(lockoutDomain.RegisterFailure(context.Background(), user.Email, ""), ShouldBeNil)
+			}
+		})
+
+		Convey("First failure is free", func() {
+			mockLoginAttemptCachePort.EXPECT().RegisterFailure("account:test@example.com", gomock.Any()).Return(int64(1), nil).Times(1)
+			mockLoginAttemptCachePort.EXPECT().RegisterFailure("ip:10.0.0.1", gomock.Any()).Return(int64(1), nil).Times(1)
+
+			So(lockoutDomain.RegisterFailure(context.Background(), user.Email, "10.0.0.1"), ShouldBeNil)
+		})
+
+		Convey("List skips unknown addresses", func() {
+			mockLoginAttemptCachePort.EXPECT().Locked("account:").Return(map[string]time.Duration{
+				"account:test@example.com":   time.Minute,
+				"account:nobody@example.com": time.Minute,
+			}, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByEmail("test@example.com").Return(user, nil).Times(1)
+			mockUserDatabasePort.EXPECT().FindByEmail("nobody@example.com").Return(nil, nil).Times(1)
+
+			lockouts, err := lockoutDomain.List(context.Background())
+			So(err, ShouldBeNil)
+			So(len(lockouts), ShouldEqual, 1)
+			So(lockouts[0].UserID, ShouldEqual, user.ID)
+		})
+
+		Convey("Get", func() {
+			Convey("Not locked", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor("account:test@example.com").Return(time.Duration(0), nil).Times(1)
+
+				lock, err := lockoutDomain.Get(context.Background(), user.ID)
+				So(err, ShouldBeNil)
+				So(lock, ShouldBeNil)
+			})
+
+			Convey("Locked", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().LockedFor("account:test@example.com").Return(time.Minute, nil).Times(1)
+
+				lock, err := lockoutDomain.Get(context.Background(), user.ID)
+				So(err, ShouldBeNil)
+				So(lock.LockedUntil, ShouldHappenAfter, time.Now())
+			})
+		})
+
+		Convey("Unlock", func() {
+			Convey("User not found", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(nil, nil).Times(1)
+
+				So(lockoutDomain.Unlock(context.Background(), user.ID), ShouldNotBeNil)
+			})
+
+			Convey("Success clears lock and delay", func() {
+				mockUserDatabasePort.EXPECT().FindByID(user.ID).Return(user, nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Reset("account:test@example.com").Return(nil).Times(1)
+				mockLoginAttemptCachePort.EXPECT().Reset("delay:account:test@example.com").Return(nil).Times(1)
+
+				So(lockoutDomain.Unlock(context.Background(), user.ID), ShouldBeNil)
+			})
+		})
+	})
+}
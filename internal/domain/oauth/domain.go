@@ -0,0 +1,215 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/domain/auth"
+	"prabogo/internal/domain/session"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
+	"prabogo/utils/jwt"
+)
+
+type OAuthDomain interface {
+	// AuthenticateClient checks client credentials: the client ID and its bearer key as secret
+	AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*model.Client, error)
+	// IssueClientToken runs the client_credentials grant. An empty scope asks for every scope
+	// granted to the client.
+	IssueClientToken(ctx context.Context, client *model.Client, scope string) (*model.OAuthToken, error)
+	// Introspect never fails on a bad token, it reports it as inactive (RFC 7662)
+	Introspect(ctx context.Context, token string) (*model.TokenIntrospection, error)
+	// Revoke ignores unknown and invalid tokens (RFC 7009). A client may revoke the tokens
+	// issued to it; user tokens need the tokens:revoke scope.
+	Revoke(ctx context.Context, client *model.Client, token string) error
+}
+
+type oauthDomain struct {
+	db    outbound_port.DatabasePort
+	cache outbound_port.CachePort
+}
+
+func NewOAuthDomain(db outbound_port.DatabasePort, cache outbound_port.CachePort) OAuthDomain {
+	return &oauthDomain{
+		db:    db,
+		cache: cache,
+	}
+}
+
+func (d *oauthDomain) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*model.Client, error) {
+	invalid := &model.OAuthError{Code: model.OAuthErrorInvalidClient, Description: "client authentication failed"}
+
+	id, err := strconv.Atoi(clientID)
+	if err != nil || clientSecret == "" {
+		return nil, invalid
+	}
+
+	clients, err := d.db.Client().FindByFilter(model.ClientFilter{IDs: []int{id}}, false)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find client failed")
+	}
+	if len(clients) == 0 || subtle.ConstantTimeCompare([]byte(clients[0].BearerKey), []byte(clientSecret)) != 1 {
+		return nil, invalid
+	}
+	return &clients[0], nil
+}
+
+func (d *oauthDomain) IssueClientToken(ctx context.Context, client *model.Client, scope string) (*model.OAuthToken, error) {
+	granted, err := d.db.Client().FindScopes(client.ID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find client scopes failed")
+	}
+
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		requested = granted
+	}
+	if len(requested) == 0 {
+		return nil, &model.OAuthError{Code: model.OAuthErrorInvalidScope, Description: "client has no scopes granted"}
+	}
+	for _, s := range requested {
+		if !utils.IsInList(granted, s) {
+			return nil, &model.OAuthError{Code: model.OAuthErrorInvalidScope, Description: "scope " + s + " is not granted to this client"}
+		}
+	}
+
+	scope = strings.Join(requested, " ")
+	token, _, err := jwt.GenerateClientToken(strconv.Itoa(client.ID), scope)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "generate client token failed")
+	}
+
+	return &model.OAuthToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(jwt.AccessTokenLifetime() / time.Second),
+		Scope:       scope,
+	}, nil
+}
+
+func (d *oauthDomain) Introspect(ctx context.Context, token string) (*model.TokenIntrospection, error) {
+	inactive := &model.TokenIntrospection{Active: false}
+
+	payload, err := jwt.ValidateLocalToken(token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	switch payload.Type {
+	case model.TokenTypeAccess:
+		revoked, err := d.cache.Revocation().IsRevoked(payload.ID, payload.Sid)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "check token revocation failed")
+		}
+		if revoked {
+			return inactive, nil
+		}
+		// Same check as the Auth middleware; it sends no email and asks no directory
+		if payload.Act != nil {
+			if err := auth.NewAuthDomain(d.db, d.cache, nil, nil).CheckImpersonation(ctx, token, payload); err != nil {
+				return inactive, nil
+			}
+		}
+	case model.TokenTypeRefresh:
+		// Rotated and signed-out refresh tokens are gone from the table or blacklisted
+		stored, err := d.db.Token().FindByToken(token, model.TokenTypeRefresh)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "find token failed")
+		}
+		if stored == nil {
+			return inactive, nil
+		}
+	default:
+		// Single-purpose tokens (email links, 2FA steps) mean nothing to other services
+		return inactive, nil
+	}
+
+	return introspection(payload), nil
+}
+
+func (d *oauthDomain) Revoke(ctx context.Context, client *model.Client, token string) error {
+	payload, err := jwt.ValidateLocalToken(token)
+	if err != nil {
+		return nil
+	}
+	if payload.Type != model.TokenTypeAccess && payload.Type != model.TokenTypeRefresh {
+		return &model.OAuthError{Code: model.OAuthErrorUnsupportedTokenType, Description: "only access and refresh tokens can be revoked"}
+	}
+	if err := d.canRevoke(client, payload); err != nil {
+		return err
+	}
+
+	switch payload.Type {
+	case model.TokenTypeAccess:
+		if payload.ExpiresAt == nil {
+			return nil
+		}
+		return d.cache.Revocation().RevokeToken(payload.ID, time.Until(payload.ExpiresAt.Time))
+	case model.TokenTypeRefresh:
+		tokenRepo := d.db.Token()
+		stored, err := tokenRepo.FindByToken(token, model.TokenTypeRefresh)
+		if err != nil {
+			return stacktrace.Propagate(err, "find token failed")
+		}
+		if stored == nil {
+			return nil
+		}
+		if stored.Family == "" {
+			return tokenRepo.Delete(stored.ID)
+		}
+		// Revoking a refresh token ends its whole session, access tokens included
+		return session.NewSessionDomain(d.db, d.cache).Revoke(ctx, stored.UserID, stored.Family)
+	}
+	return nil
+}
+
+// canRevoke refuses tokens that were not issued to client (RFC 7009 section 2.1)
+func (d *oauthDomain) canRevoke(client *model.Client, payload *jwt.TokenPayload) error {
+	if payload.ClientID != "" {
+		if payload.ClientID != strconv.Itoa(client.ID) {
+			return &model.OAuthError{Code: model.OAuthErrorUnauthorizedClient, Description: "token was not issued to this client"}
+		}
+		return nil
+	}
+
+	granted, err := d.db.Client().FindScopes(client.ID)
+	if err != nil {
+		return stacktrace.Propagate(err, "find client scopes failed")
+	}
+	if !utils.IsInList(granted, model.ScopeTokensRevoke) {
+		return &model.OAuthError{Code: model.OAuthErrorUnauthorizedClient, Description: "revoking user tokens needs the " + model.ScopeTokensRevoke + " scope"}
+	}
+	return nil
+}
+
+func introspection(payload *jwt.TokenPayload) *model.TokenIntrospection {
+	result := &model.TokenIntrospection{
+		Active:    true,
+		TokenType: model.TokenTypeHintAccessToken,
+		Sub:       payload.Sub,
+		Iss:       payload.Issuer,
+		Jti:       payload.ID,
+		Sid:       payload.Sid,
+		ClientID:  payload.ClientID,
+		Scope:     payload.Scope,
+	}
+	if payload.Type == model.TokenTypeRefresh {
+		result.TokenType = model.TokenTypeHintRefreshToken
+	}
+	if payload.ExpiresAt != nil {
+		result.Exp = payload.ExpiresAt.Unix()
+	}
+	if payload.IssuedAt != nil {
+		result.Iat = payload.IssuedAt.Unix()
+	}
+	if payload.Act != nil {
+		result.Act = &model.TokenActor{Sub: payload.Act.Sub}
+	}
+	return result
+}
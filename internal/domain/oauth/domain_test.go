@@ -0,0 +1,267 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+)
+
+func TestOAuth(t *testing.T) {
+	Convey("Test OAuth", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		os.Setenv("JWT_SECRET", "test-secret")
+		defer os.Unsetenv("JWT_SECRET")
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+
+		oauthDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort).OAuth()
+
+		userID := uuid.New().String()
+		accessToken, refreshToken, _, _, err := jwt.GenerateAuthTokens(userID, "family-1")
+		So(err, ShouldBeNil)
+
+		isInvalidClient := func(err error) bool {
+			var oauthErr *model.OAuthError
+			return errors.As(err, &oauthErr) && oauthErr.Code == model.OAuthErrorInvalidClient
+		}
+
+		Convey("AuthenticateClient", func() {
+			client := model.Client{ID: 7, ClientInput: model.ClientInput{Name: "billing", BearerKey: "secret-key"}}
+
+			Convey("Client ID is not a number", func() {
+				_, err := oauthDomain.AuthenticateClient(context.Background(), "billing", "secret-key")
+				So(isInvalidClient(err), ShouldBeTrue)
+			})
+
+			Convey("Unknown client", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(model.ClientFilter{IDs: []int{7}}, false).Return(nil, nil).Times(1)
+
+				_, err := oauthDomain.AuthenticateClient(context.Background(), "7", "secret-key")
+				So(isInvalidClient(err), ShouldBeTrue)
+			})
+
+			Convey("Wrong secret", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+
+				_, err := oauthDomain.AuthenticateClient(context.Background(), "7", "other-key")
+				So(isInvalidClient(err), ShouldBeTrue)
+			})
+
+			Convey("Success", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), false).Return([]model.Client{client}, nil).Times(1)
+
+				authenticated, err := oauthDomain.AuthenticateClient(context.Background(), "7", "secret-key")
+				So(err, ShouldBeNil)
+				So(authenticated.Name, ShouldEqual, "billing")
+			})
+		})
+
+		Convey("IssueClientToken", func() {
+			client := &model.Client{ID: 7}
+
+			Convey("Client without scopes", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{}, nil).Times(1)
+
+				_, err := oauthDomain.IssueClientToken(context.Background(), client, "")
+				var oauthErr *model.OAuthError
+				So(errors.As(err, &oauthErr), ShouldBeTrue)
+				So(oauthErr.Code, ShouldEqual, model.OAuthErrorInvalidScope)
+			})
+
+			Convey("Scope not granted", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeUsersRead}, nil).Times(1)
+
+				_, err := oauthDomain.IssueClientToken(context.Background(), client, "users:read users:write")
+				var oauthErr *model.OAuthError
+				So(errors.As(err, &oauthErr), ShouldBeTrue)
+				So(oauthErr.Code, ShouldEqual, model.OAuthErrorInvalidScope)
+			})
+
+			Convey("Empty scope asks for every granted scope", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeUsersRead, model.ScopeSessionsRead}, nil).Times(1)
+
+				token, err := oauthDomain.IssueClientToken(context.Background(), client, "")
+				So(err, ShouldBeNil)
+				So(token.TokenType, ShouldEqual, "Bearer")
+				So(token.Scope, ShouldEqual, "users:read sessions:read")
+
+				payload, err := jwt.ValidateLocalToken(token.AccessToken)
+				So(err, ShouldBeNil)
+				So(payload.Sub, ShouldEqual, "7")
+				So(payload.ClientID, ShouldEqual, "7")
+				So(payload.Scope, ShouldEqual, "users:read sessions:read")
+			})
+
+			Convey("Narrower scope", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeUsersRead, model.ScopeSessionsRead}, nil).Times(1)
+
+				token, err := oauthDomain.IssueClientToken(context.Background(), client, "sessions:read")
+				So(err, ShouldBeNil)
+				So(token.Scope, ShouldEqual, "sessions:read")
+			})
+		})
+
+		Convey("Introspect", func() {
+			Convey("Garbage is inactive", func() {
+				result, err := oauthDomain.Introspect(context.Background(), "not-a-token")
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeFalse)
+				So(result.Sub, ShouldBeEmpty)
+			})
+
+			Convey("Revoked access token is inactive", func() {
+				mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), "family-1").Return(true, nil).Times(1)
+
+				result, err := oauthDomain.Introspect(context.Background(), accessToken)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeFalse)
+			})
+
+			Convey("Live access token", func() {
+				mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), "family-1").Return(false, nil).Times(1)
+
+				result, err := oauthDomain.Introspect(context.Background(), accessToken)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeTrue)
+				So(result.TokenType, ShouldEqual, model.TokenTypeHintAccessToken)
+				So(result.Sub, ShouldEqual, userID)
+				So(result.Sid, ShouldEqual, "family-1")
+				So(result.Exp, ShouldBeGreaterThan, time.Now().Unix())
+			})
+
+			Convey("Rotated refresh token is inactive", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(refreshToken, model.TokenTypeRefresh).Return(nil, nil).Times(1)
+
+				result, err := oauthDomain.Introspect(context.Background(), refreshToken)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeFalse)
+			})
+
+			Convey("Stored refresh token", func() {
+				mockTokenDatabasePort.EXPECT().FindByToken(refreshToken, model.TokenTypeRefresh).Return(&model.Token{ID: 1}, nil).Times(1)
+
+				result, err := oauthDomain.Introspect(context.Background(), refreshToken)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeTrue)
+				So(result.TokenType, ShouldEqual, model.TokenTypeHintRefreshToken)
+			})
+
+			Convey("Client token reports its client and scope", func() {
+				token, _, err := jwt.GenerateClientToken("7", "users:read")
+				So(err, ShouldBeNil)
+				mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), "").Return(false, nil).Times(1)
+
+				result, err := oauthDomain.Introspect(context.Background(), token)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeTrue)
+				So(result.ClientID, ShouldEqual, "7")
+				So(result.Scope, ShouldEqual, "users:read")
+			})
+
+			Convey("Ended impersonation is inactive", func() {
+				token, _, err := jwt.GenerateImpersonationToken(userID, uuid.New().String())
+				So(err, ShouldBeNil)
+				mockRevocationCachePort.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindByToken(token, model.TokenTypeImpersonation).Return(nil, nil).Times(1)
+
+				result, err := oauthDomain.Introspect(context.Background(), token)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeFalse)
+			})
+
+			Convey("Email link tokens are inactive", func() {
+				token, _, err := jwt.GenerateVerifyEmailToken(userID)
+				So(err, ShouldBeNil)
+
+				result, err := oauthDomain.Introspect(context.Background(), token)
+				So(err, ShouldBeNil)
+				So(result.Active, ShouldBeFalse)
+			})
+		})
+
+		Convey("Revoke", func() {
+			client := &model.Client{ID: 7}
+
+			Convey("Garbage is ignored", func() {
+				So(oauthDomain.Revoke(context.Background(), client, "not-a-token"), ShouldBeNil)
+			})
+
+			Convey("User token without the revoke scope is refused", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeUsersRead}, nil).Times(1)
+
+				err := oauthDomain.Revoke(context.Background(), client, accessToken)
+				var oauthErr *model.OAuthError
+				So(errors.As(err, &oauthErr), ShouldBeTrue)
+				So(oauthErr.Code, ShouldEqual, model.OAuthErrorUnauthorizedClient)
+			})
+
+			Convey("Access token is denied until it expires", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeTokensRevoke}, nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				So(oauthDomain.Revoke(context.Background(), client, accessToken), ShouldBeNil)
+			})
+
+			Convey("Refresh token ends the session", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeTokensRevoke}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindByToken(refreshToken, model.TokenTypeRefresh).
+					Return(&model.Token{ID: 1, UserID: userID, Family: "family-1"}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionByFamily("family-1").Return(&model.Session{ID: "family-1", UserID: userID}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByFamily("family-1").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				So(oauthDomain.Revoke(context.Background(), client, refreshToken), ShouldBeNil)
+			})
+
+			Convey("Unknown refresh token is ignored", func() {
+				mockClientDatabasePort.EXPECT().FindScopes(7).Return([]string{model.ScopeTokensRevoke}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindByToken(refreshToken, model.TokenTypeRefresh).Return(nil, nil).Times(1)
+
+				So(oauthDomain.Revoke(context.Background(), client, refreshToken), ShouldBeNil)
+			})
+
+			Convey("Client revokes its own token without any scope", func() {
+				token, _, err := jwt.GenerateClientToken("7", model.ScopeUsersRead)
+				So(err, ShouldBeNil)
+				mockRevocationCachePort.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				So(oauthDomain.Revoke(context.Background(), client, token), ShouldBeNil)
+			})
+
+			Convey("Token of another client is refused", func() {
+				token, _, err := jwt.GenerateClientToken("8", model.ScopeUsersRead)
+				So(err, ShouldBeNil)
+
+				err = oauthDomain.Revoke(context.Background(), client, token)
+				var oauthErr *model.OAuthError
+				So(errors.As(err, &oauthErr), ShouldBeTrue)
+				So(oauthErr.Code, ShouldEqual, model.OAuthErrorUnauthorizedClient)
+			})
+		})
+	})
+}
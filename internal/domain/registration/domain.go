@@ -0,0 +1,165 @@
+package registration
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+type RegistrationDomain interface {
+	// GetPolicy returns the saved policy, or the open default when none was saved
+	GetPolicy(ctx context.Context) (*model.RegistrationPolicy, error)
+	// UpdatePolicy replaces the policy; it applies to the next sign-up
+	UpdatePolicy(ctx context.Context, actorID string, input model.RegistrationPolicyInput) (*model.RegistrationPolicy, error)
+
+	// CheckSignUp returns a *model.RegistrationError when email may not register itself
+	CheckSignUp(ctx context.Context, email string) error
+	// CheckInvite returns a *model.RegistrationError once registration is closed
+	CheckInvite(ctx context.Context) error
+}
+
+type registrationDomain struct {
+	db outbound_port.DatabasePort
+}
+
+func NewRegistrationDomain(db outbound_port.DatabasePort) RegistrationDomain {
+	return &registrationDomain{
+		db: db,
+	}
+}
+
+func (d *registrationDomain) GetPolicy(ctx context.Context) (*model.RegistrationPolicy, error) {
+	policy, err := d.db.RegistrationPolicy().Get()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find registration policy failed")
+	}
+	if policy == nil {
+		return model.DefaultRegistrationPolicy(), nil
+	}
+	return policy, nil
+}
+
+func (d *registrationDomain) UpdatePolicy(ctx context.Context, actorID string, input model.RegistrationPolicyInput) (*model.RegistrationPolicy, error) {
+	if !validMode(input.Mode) {
+		return nil, stacktrace.NewError("mode must be one of %s", strings.Join(model.RegistrationModes, ", "))
+	}
+
+	allowed, err := normalizeDomains(input.AllowedDomains)
+	if err != nil {
+		return nil, err
+	}
+	denied, err := normalizeDomains(input.DeniedDomains)
+	if err != nil {
+		return nil, err
+	}
+	// Restricted with nothing allowed would be closed under another name
+	if input.Mode == model.RegistrationModeRestricted && len(allowed) == 0 {
+		return nil, stacktrace.NewError("restricted mode needs at least one allowed domain")
+	}
+
+	now := time.Now()
+	policy := &model.RegistrationPolicy{
+		Mode:           input.Mode,
+		AllowedDomains: allowed,
+		DeniedDomains:  denied,
+		UpdatedAt:      &now,
+	}
+	if actorID != "" {
+		policy.UpdatedBy = &actorID
+	}
+
+	if err := d.db.RegistrationPolicy().Save(policy); err != nil {
+		return nil, stacktrace.Propagate(err, "save registration policy failed")
+	}
+	return policy, nil
+}
+
+func (d *registrationDomain) CheckSignUp(ctx context.Context, email string) error {
+	policy, err := d.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch policy.Mode {
+	case model.RegistrationModeClosed:
+		return &model.RegistrationError{Code: model.RegistrationErrorClosed, Message: "registration is closed"}
+	case model.RegistrationModeInviteOnly:
+		return &model.RegistrationError{Code: model.RegistrationErrorInviteOnly, Message: "registration is by invitation only"}
+	}
+
+	// The deny list wins, so a subdomain can be carved out of an allowed domain
+	domain := emailDomain(email)
+	if matchesAny(domain, policy.DeniedDomains) {
+		return &model.RegistrationError{Code: model.RegistrationErrorDomainDenied, Message: "email domain is not accepted"}
+	}
+	if policy.Mode == model.RegistrationModeRestricted && !matchesAny(domain, policy.AllowedDomains) {
+		return &model.RegistrationError{Code: model.RegistrationErrorDomainNotAllowed, Message: "email domain is not allowed to register"}
+	}
+	return nil
+}
+
+func (d *registrationDomain) CheckInvite(ctx context.Context) error {
+	policy, err := d.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	if policy.Mode == model.RegistrationModeClosed {
+		return &model.RegistrationError{Code: model.RegistrationErrorClosed, Message: "registration is closed"}
+	}
+	return nil
+}
+
+func validMode(mode string) bool {
+	for _, m := range model.RegistrationModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDomains lowercases, accepts a leading "@" and drops duplicates
+func normalizeDomains(domains []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if !domainPattern.MatchString(domain) {
+			return nil, stacktrace.NewError("invalid email domain %q", domain)
+		}
+		if !seen[domain] {
+			seen[domain] = true
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized, nil
+}
+
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// matchesAny reports whether domain is one of domains or a subdomain of one
+func matchesAny(domain string, domains []string) bool {
+	if domain == "" {
+		return false
+	}
+	for _, d := range domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package registration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestRegistration(t *testing.T) {
+	Convey("Test Registration", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockRegistrationPolicyDatabasePort := mock_outbound_port.NewMockRegistrationPolicyDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().RegistrationPolicy().Return(mockRegistrationPolicyDatabasePort).AnyTimes()
+
+		registrationDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort).Registration()
+
+		Convey("GetPolicy", func() {
+			Convey("Open until saved", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(nil, nil).Times(1)
+
+				policy, err := registrationDomain.GetPolicy(context.Background())
+				So(err, ShouldBeNil)
+				So(policy.Mode, ShouldEqual, model.RegistrationModeOpen)
+				So(policy.UpdatedAt, ShouldBeNil)
+			})
+
+			Convey("Lookup error", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(nil, errors.New("error")).Times(1)
+
+				_, err := registrationDomain.GetPolicy(context.Background())
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("UpdatePolicy", func() {
+			Convey("Unknown mode", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).Times(0)
+
+				_, err := registrationDomain.UpdatePolicy(context.Background(), "admin-1", model.RegistrationPolicyInput{Mode: "sometimes"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Invalid domain", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).Times(0)
+
+				_, err := registrationDomain.UpdatePolicy(context.Background(), "admin-1", model.RegistrationPolicyInput{
+					Mode:          model.RegistrationModeOpen,
+					DeniedDomains: []string{"not a domain"},
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Restricted needs an allowed domain", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).Times(0)
+
+				_, err := registrationDomain.UpdatePolicy(context.Background(), "admin-1", model.RegistrationPolicyInput{Mode: model.RegistrationModeRestricted})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Normalizes the domains and records the admin", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Save(gomock.Any()).Return(nil).Times(1)
+
+				policy, err := registrationDomain.UpdatePolicy(context.Background(), "admin-1", model.RegistrationPolicyInput{
+					Mode:           model.RegistrationModeRestricted,
+					AllowedDomains: []string{" Example.com", "@example.com", "example.org"},
+				})
+				So(err, ShouldBeNil)
+				So(policy.AllowedDomains, ShouldResemble, []string{"example.com", "example.org"})
+				So(policy.DeniedDomains, ShouldNotBeNil)
+				So(policy.DeniedDomains, ShouldBeEmpty)
+				So(*policy.UpdatedBy, ShouldEqual, "admin-1")
+				So(policy.UpdatedAt, ShouldNotBeNil)
+			})
+		})
+
+		Convey("CheckSignUp", func() {
+			Convey("Open accepts any domain but the denied ones", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{
+					Mode:          model.RegistrationModeOpen,
+					DeniedDomains: []string{"mailinator.com"},
+				}, nil).Times(2)
+
+				So(registrationDomain.CheckSignUp(context.Background(), "new@example.com"), ShouldBeNil)
+
+				err := registrationDomain.CheckSignUp(context.Background(), "new@MAILINATOR.com")
+				var refused *model.RegistrationError
+				So(errors.As(err, &refused), ShouldBeTrue)
+				So(refused.Code, ShouldEqual, model.RegistrationErrorDomainDenied)
+			})
+
+			Convey("Restricted does not match on a suffix alone", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{
+					Mode:           model.RegistrationModeRestricted,
+					AllowedDomains: []string{"example.com"},
+				}, nil).Times(1)
+
+				err := registrationDomain.CheckSignUp(context.Background(), "new@notexample.com")
+				var refused *model.RegistrationError
+				So(errors.As(err, &refused), ShouldBeTrue)
+				So(refused.Code, ShouldEqual, model.RegistrationErrorDomainNotAllowed)
+			})
+		})
+
+		Convey("CheckInvite", func() {
+			Convey("Refused once closed", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeClosed}, nil).Times(1)
+
+				err := registrationDomain.CheckInvite(context.Background())
+				var refused *model.RegistrationError
+				So(errors.As(err, &refused), ShouldBeTrue)
+				So(refused.Code, ShouldEqual, model.RegistrationErrorClosed)
+			})
+
+			Convey("Allowed when invite only", func() {
+				mockRegistrationPolicyDatabasePort.EXPECT().Get().Return(&model.RegistrationPolicy{Mode: model.RegistrationModeInviteOnly}, nil).Times(1)
+
+				So(registrationDomain.CheckInvite(context.Background()), ShouldBeNil)
+			})
+		})
+	})
+}
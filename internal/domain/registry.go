@@ -1,8 +1,14 @@
 package domain
 
 import (
+	"prabogo/internal/domain/accesstoken"
 	"prabogo/internal/domain/auth"
 	"prabogo/internal/domain/client"
+	"prabogo/internal/domain/invitation"
+	"prabogo/internal/domain/oauth"
+	"prabogo/internal/domain/registration"
+	"prabogo/internal/domain/role"
+	"prabogo/internal/domain/session"
 	"prabogo/internal/domain/user"
 	outbound_port "prabogo/internal/port/outbound"
 )
@@ -11,13 +17,20 @@ type Domain interface {
 	Client() client.ClientDomain
 	User() user.UserDomain
 	Auth() auth.AuthDomain
+	Session() session.SessionDomain
+	AccessToken() accesstoken.AccessTokenDomain
+	Role() role.RoleDomain
+	OAuth() oauth.OAuthDomain
+	Invitation() invitation.InvitationDomain
+	Registration() registration.RegistrationDomain
 }
 
 type domain struct {
-	databasePort outbound_port.DatabasePort
-	messagePort  outbound_port.MessagePort
-	cachePort    outbound_port.CachePort
-	emailPort    outbound_port.EmailPort
+	databasePort  outbound_port.DatabasePort
+	messagePort   outbound_port.MessagePort
+	cachePort     outbound_port.CachePort
+	emailPort     outbound_port.EmailPort
+	directoryPort outbound_port.DirectoryPort
 }
 
 func NewDomain(
@@ -25,12 +38,14 @@ func NewDomain(
 	messagePort outbound_port.MessagePort,
 	cachePort outbound_port.CachePort,
 	emailPort outbound_port.EmailPort,
+	directoryPort outbound_port.DirectoryPort,
 ) Domain {
 	return &domain{
-		databasePort: databasePort,
-		messagePort:  messagePort,
-		cachePort:    cachePort,
-		emailPort:    emailPort,
+		databasePort:  databasePort,
+		messagePort:   messagePort,
+		cachePort:     cachePort,
+		emailPort:     emailPort,
+		directoryPort: directoryPort,
 	}
 }
 
@@ -39,9 +54,34 @@ func (d *domain) Client() client.ClientDomain {
 }
 
 func (d *domain) User() user.UserDomain {
-	return user.NewUserDomain(d.databasePort)
+	return user.NewUserDomain(d.databasePort, d.cachePort)
 }
 
 func (d *domain) Auth() auth.AuthDomain {
-	return auth.NewAuthDomain(d.databasePort, d.emailPort)
+	return auth.NewAuthDomain(d.databasePort, d.cachePort, d.emailPort, d.directoryPort)
+}
+
+func (d *domain) Session() session.SessionDomain {
+	return session.NewSessionDomain(d.databasePort, d.cachePort)
+}
+
+func (d *domain) AccessToken() accesstoken.AccessTokenDomain {
+	return accesstoken.NewAccessTokenDomain(d.databasePort)
+}
+
+func (d *domain) Role() role.RoleDomain {
+	return role.NewRoleDomain(d.databasePort)
+}
+
+func (d *domain) OAuth() oauth.OAuthDomain {
+	return oauth.NewOAuthDomain(d.databasePort, d.cachePort)
+}
+
+func (d *domain) Invitation() invitation.InvitationDomain {
+	return invitation.NewInvitationDomain(d.databasePort, d.emailPort)
+}
+
+
+func (d *domain) Registration() registration.RegistrationDomain {
+	return registration.NewRegistrationDomain(d.databasePort)
 }
\ No newline at end of file
@@ -0,0 +1,306 @@
+package role
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
+	"prabogo/utils/activity"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+type RoleDomain interface {
+	List(ctx context.Context) ([]model.Role, error)
+	Get(ctx context.Context, name string) (*model.Role, error)
+	// Create, Update and Delete refuse actors lacking any permission of the role, before or
+	// after the change, so nobody grants themselves access through a role they can edit
+	Create(ctx context.Context, actorID string, input model.RoleInput) (*model.Role, error)
+	Update(ctx context.Context, actorID, name string, input model.RoleInput) (*model.Role, error)
+	// Delete refuses the seeded roles and roles still held by a user
+	Delete(ctx context.Context, actorID, name string) error
+	ListPermissions(ctx context.Context) ([]model.Permission, error)
+
+	Assign(ctx context.Context, actorID, userID, role string) (*model.User, error)
+	// HasPermission reports whether the user's role grants permission
+	HasPermission(ctx context.Context, userID, permission string) (bool, error)
+	// CanManage returns an error unless the actor holds every permission of each role, so
+	// nobody creates, edits or promotes an account beyond their own access. A request made
+	// with a personal access token only counts the permissions the token has as scopes.
+	CanManage(ctx context.Context, actorID string, roles ...string) error
+}
+
+type roleDomain struct {
+	db outbound_port.DatabasePort
+}
+
+func NewRoleDomain(db outbound_port.DatabasePort) RoleDomain {
+	return &roleDomain{
+		db: db,
+	}
+}
+
+func (d *roleDomain) List(ctx context.Context) ([]model.Role, error) {
+	roles, err := d.db.Role().FindAll()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find roles failed")
+	}
+	return roles, nil
+}
+
+func (d *roleDomain) Get(ctx context.Context, name string) (*model.Role, error) {
+	role, err := d.db.Role().FindByName(name)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find role failed")
+	}
+	if role == nil {
+		return nil, stacktrace.NewError("role not found")
+	}
+	return role, nil
+}
+
+func (d *roleDomain) Create(ctx context.Context, actorID string, input model.RoleInput) (*model.Role, error) {
+	name := strings.TrimSpace(input.Name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, stacktrace.NewError("role name must be 2-50 lowercase letters, digits, '-' or '_'")
+	}
+
+	existing, err := d.db.Role().FindByName(name)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find role failed")
+	}
+	if existing != nil {
+		return nil, stacktrace.NewError("role already exists")
+	}
+
+	permissions, err := d.normalizePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.canGrant(ctx, actorID, permissions); err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		Permissions: permissions,
+		CreatedAt:   time.Now(),
+	}
+	_, err = d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		return nil, repo.Role().Create(role)
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "create role failed")
+	}
+
+	return role, nil
+}
+
+func (d *roleDomain) Update(ctx context.Context, actorID, name string, input model.RoleInput) (*model.Role, error) {
+	role, err := d.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.canGrant(ctx, actorID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if input.Permissions != nil {
+		if role.Name == model.RoleAdmin {
+			return nil, stacktrace.NewError("admin role always has every permission")
+		}
+		permissions, err := d.normalizePermissions(input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.canGrant(ctx, actorID, permissions); err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+	if input.Description != "" {
+		role.Description = strings.TrimSpace(input.Description)
+	}
+
+	_, err = d.db.DoInTransaction(func(repo outbound_port.DatabasePort) (interface{}, error) {
+		if err := repo.Role().Update(role); err != nil {
+			return nil, err
+		}
+		if input.Permissions != nil {
+			return nil, repo.Role().SetPermissions(role.Name, role.Permissions)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "update role failed")
+	}
+
+	return role, nil
+}
+
+func (d *roleDomain) Delete(ctx context.Context, actorID, name string) error {
+	if name == model.RoleAdmin || name == model.RoleUser {
+		return stacktrace.NewError("built-in roles cannot be deleted")
+	}
+	role, err := d.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := d.canGrant(ctx, actorID, role.Permissions); err != nil {
+		return err
+	}
+
+	count, err := d.db.Role().CountUsers(name)
+	if err != nil {
+		return stacktrace.Propagate(err, "count role users failed")
+	}
+	if count > 0 {
+		return stacktrace.NewError("role is still assigned to %d users", count)
+	}
+
+	return d.db.Role().Delete(name)
+}
+
+func (d *roleDomain) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	permissions, err := d.db.Role().FindPermissions()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find permissions failed")
+	}
+	return permissions, nil
+}
+
+func (d *roleDomain) Assign(ctx context.Context, actorID, userID, name string) (*model.User, error) {
+	if _, err := d.Get(ctx, name); err != nil {
+		return nil, err
+	}
+
+	repo := d.db.User()
+	user, err := repo.FindByID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+	if err := d.CanManage(ctx, actorID, user.Role, name); err != nil {
+		return nil, err
+	}
+
+	user.Role = name
+	user.UpdatedAt = time.Now()
+	if err := repo.Update(user); err != nil {
+		return nil, stacktrace.Propagate(err, "update user failed")
+	}
+	return user, nil
+}
+
+func (d *roleDomain) HasPermission(ctx context.Context, userID, permission string) (bool, error) {
+	user, err := d.db.User().FindByID(userID)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "find user failed")
+	}
+	if user == nil {
+		return false, stacktrace.NewError("user not found")
+	}
+	granted, err := d.db.Role().HasPermission(user.Role, permission)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "check permission failed")
+	}
+	return granted, nil
+}
+
+func (d *roleDomain) CanManage(ctx context.Context, actorID string, roles ...string) error {
+	held, err := d.heldPermissions(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range roles {
+		role, err := d.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !holdsAll(held, role.Permissions) {
+			return stacktrace.NewError("cannot manage a user with more permissions than you")
+		}
+	}
+	return nil
+}
+
+// canGrant returns an error unless the actor holds every one of permissions
+func (d *roleDomain) canGrant(ctx context.Context, actorID string, permissions []string) error {
+	held, err := d.heldPermissions(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if !holdsAll(held, permissions) {
+		return stacktrace.NewError("cannot manage a role with more permissions than you")
+	}
+	return nil
+}
+
+// heldPermissions lists the permissions of the actor's role, narrowed to the token's scopes
+func (d *roleDomain) heldPermissions(ctx context.Context, actorID string) ([]string, error) {
+	actor, err := d.db.User().FindByID(actorID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find user failed")
+	}
+	if actor == nil {
+		return nil, stacktrace.NewError("user not found")
+	}
+
+	actorRole, err := d.Get(ctx, actor.Role)
+	if err != nil {
+		return nil, err
+	}
+	held := actorRole.Permissions
+	if scopes, ok := activity.GetTokenScopes(ctx); ok {
+		held = []string{}
+		for _, permission := range actorRole.Permissions {
+			if utils.IsInList(scopes, permission) {
+				held = append(held, permission)
+			}
+		}
+	}
+	return held, nil
+}
+
+func holdsAll(held, permissions []string) bool {
+	for _, permission := range permissions {
+		if !utils.IsInList(held, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizePermissions rejects permissions missing from the permissions table and drops duplicates
+func (d *roleDomain) normalizePermissions(permissions []string) ([]string, error) {
+	known, err := d.db.Role().FindPermissions()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find permissions failed")
+	}
+	names := []string{}
+	for _, p := range known {
+		names = append(names, p.Name)
+	}
+
+	result := []string{}
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if !utils.IsInList(names, permission) {
+			return nil, stacktrace.NewError("unknown permission %s", permission)
+		}
+		if !utils.IsInList(result, permission) {
+			result = append(result, permission)
+		}
+	}
+	return result, nil
+}
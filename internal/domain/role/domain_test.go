@@ -0,0 +1,270 @@
+package role_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/activity"
+)
+
+func TestRole(t *testing.T) {
+	Convey("Test Role", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+
+		roleDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort).Role()
+
+		permissions := []model.Permission{
+			{Name: model.PermissionUsersRead},
+			{Name: model.PermissionUsersWrite},
+			{Name: model.PermissionUsersImpersonate},
+		}
+		// A roles:write holder whose own role grants less than the admin's
+		mockUserDatabasePort.EXPECT().FindByID("manager-1").Return(&model.User{ID: "manager-1", Role: "manager"}, nil).AnyTimes()
+		mockRoleDatabasePort.EXPECT().FindByName("manager").Return(&model.Role{
+			Name:        "manager",
+			Permissions: []string{model.PermissionUsersRead, model.PermissionUsersWrite, model.PermissionRolesWrite},
+		}, nil).AnyTimes()
+
+		Convey("Create", func() {
+			Convey("Invalid name", func() {
+				_, err := roleDomain.Create(context.Background(), "manager-1", model.RoleInput{Name: "Support Team"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Role already exists", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(1)
+
+				_, err := roleDomain.Create(context.Background(), "manager-1", model.RoleInput{Name: "support"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Unknown permission", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(nil, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindPermissions().Return(permissions, nil).Times(1)
+
+				_, err := roleDomain.Create(context.Background(), "manager-1", model.RoleInput{
+					Name:        "support",
+					Permissions: []string{"users:destroy"},
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Permission the actor lacks", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(nil, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindPermissions().Return(permissions, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().Create(gomock.Any()).Times(0)
+
+				_, err := roleDomain.Create(context.Background(), "manager-1", model.RoleInput{
+					Name:        "support",
+					Permissions: []string{model.PermissionUsersImpersonate},
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(nil, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindPermissions().Return(permissions, nil).Times(1)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+					return fn(mockDatabasePort)
+				}).Times(1)
+				var stored *model.Role
+				mockRoleDatabasePort.EXPECT().Create(gomock.Any()).DoAndReturn(func(role *model.Role) error {
+					stored = role
+					return nil
+				}).Times(1)
+
+				role, err := roleDomain.Create(context.Background(), "manager-1", model.RoleInput{
+					Name:        "support",
+					Description: "Help desk",
+					Permissions: []string{model.PermissionUsersRead, model.PermissionUsersRead},
+				})
+				So(err, ShouldBeNil)
+				So(role.Permissions, ShouldResemble, []string{model.PermissionUsersRead})
+				So(stored, ShouldEqual, role)
+			})
+		})
+
+		Convey("Update", func() {
+			Convey("Admin permissions cannot change", func() {
+				mockRoleDatabasePort.EXPECT().FindByName(model.RoleAdmin).Return(&model.Role{Name: model.RoleAdmin}, nil).Times(1)
+
+				_, err := roleDomain.Update(context.Background(), "manager-1", model.RoleAdmin, model.RoleInput{Permissions: []string{}})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Role beyond the actor's own", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("auditor").Return(&model.Role{
+					Name:        "auditor",
+					Permissions: []string{model.PermissionSettingsRead},
+				}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().Update(gomock.Any()).Times(0)
+
+				_, err := roleDomain.Update(context.Background(), "manager-1", "auditor", model.RoleInput{Description: "Tier 2"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Granting a permission the actor lacks", func() {
+				// The actor's own role, widened by its holder
+				mockRoleDatabasePort.EXPECT().FindPermissions().Return(permissions, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().Update(gomock.Any()).Times(0)
+
+				_, err := roleDomain.Update(context.Background(), "manager-1", "manager", model.RoleInput{
+					Permissions: []string{model.PermissionUsersRead, model.PermissionUsersImpersonate},
+				})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Description only keeps the permissions", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{
+					Name:        "support",
+					Permissions: []string{model.PermissionUsersRead},
+				}, nil).Times(1)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(func(fn outbound_port.InTransaction) (interface{}, error) {
+					return fn(mockDatabasePort)
+				}).Times(1)
+				mockRoleDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+
+				role, err := roleDomain.Update(context.Background(), "manager-1", "support", model.RoleInput{Description: "Tier 1"})
+				So(err, ShouldBeNil)
+				So(role.Description, ShouldEqual, "Tier 1")
+				So(role.Permissions, ShouldResemble, []string{model.PermissionUsersRead})
+			})
+		})
+
+		Convey("Delete", func() {
+			Convey("Built-in role", func() {
+				So(roleDomain.Delete(context.Background(), "manager-1", model.RoleUser), ShouldNotBeNil)
+			})
+
+			Convey("Role beyond the actor's own", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("auditor").Return(&model.Role{
+					Name:        "auditor",
+					Permissions: []string{model.PermissionSettingsRead},
+				}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().Delete(gomock.Any()).Times(0)
+
+				So(roleDomain.Delete(context.Background(), "manager-1", "auditor"), ShouldNotBeNil)
+			})
+
+			Convey("Role still assigned", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().CountUsers("support").Return(int64(2), nil).Times(1)
+
+				So(roleDomain.Delete(context.Background(), "manager-1", "support"), ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().CountUsers("support").Return(int64(0), nil).Times(1)
+				mockRoleDatabasePort.EXPECT().Delete("support").Return(nil).Times(1)
+
+				So(roleDomain.Delete(context.Background(), "manager-1", "support"), ShouldBeNil)
+			})
+		})
+
+		Convey("Assign", func() {
+			actor := &model.User{ID: "admin-1", Role: model.RoleAdmin}
+			admin := &model.Role{Name: model.RoleAdmin, Permissions: []string{model.PermissionUsersWrite, model.PermissionRolesWrite}}
+
+			Convey("Unknown role", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("ghost").Return(nil, nil).Times(1)
+
+				_, err := roleDomain.Assign(context.Background(), actor.ID, "user-1", "ghost")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Role beyond the actor's own", func() {
+				mockRoleDatabasePort.EXPECT().FindByName(model.RoleAdmin).Return(admin, nil).AnyTimes()
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support", Permissions: []string{model.PermissionRolesWrite}}, nil).AnyTimes()
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1", Role: "support"}, nil).Times(2)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Times(0)
+
+				// A roles:write holder promoting themselves
+				_, err := roleDomain.Assign(context.Background(), "user-1", "user-1", model.RoleAdmin)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support"}, nil).Times(2)
+				mockRoleDatabasePort.EXPECT().FindByName(model.RoleUser).Return(&model.Role{Name: model.RoleUser}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().FindByName(model.RoleAdmin).Return(admin, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1", Role: model.RoleUser}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().FindByID(actor.ID).Return(actor, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil).Times(1)
+
+				user, err := roleDomain.Assign(context.Background(), actor.ID, "user-1", "support")
+				So(err, ShouldBeNil)
+				So(user.Role, ShouldEqual, "support")
+			})
+		})
+
+		Convey("HasPermission", func() {
+			Convey("Admin is looked up like any role", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1", Role: model.RoleAdmin}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().HasPermission(model.RoleAdmin, model.PermissionRolesWrite).Return(true, nil).Times(1)
+
+				granted, err := roleDomain.HasPermission(context.Background(), "user-1", model.PermissionRolesWrite)
+				So(err, ShouldBeNil)
+				So(granted, ShouldBeTrue)
+			})
+
+			Convey("Other roles are looked up", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1", Role: "support"}, nil).Times(1)
+				mockRoleDatabasePort.EXPECT().HasPermission("support", model.PermissionUsersWrite).Return(false, nil).Times(1)
+
+				granted, err := roleDomain.HasPermission(context.Background(), "user-1", model.PermissionUsersWrite)
+				So(err, ShouldBeNil)
+				So(granted, ShouldBeFalse)
+			})
+		})
+
+		Convey("CanManage", func() {
+			mockUserDatabasePort.EXPECT().FindByID("admin-1").Return(&model.User{ID: "admin-1", Role: model.RoleAdmin}, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().FindByName(model.RoleAdmin).Return(&model.Role{
+				Name:        model.RoleAdmin,
+				Permissions: []string{model.PermissionUsersRead, model.PermissionUsersWrite, model.PermissionUsersImpersonate},
+			}, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{
+				Name:        "support",
+				Permissions: []string{model.PermissionUsersRead},
+			}, nil).AnyTimes()
+
+			Convey("Actor holding every permission", func() {
+				So(roleDomain.CanManage(context.Background(), "admin-1", "support", model.RoleAdmin), ShouldBeNil)
+			})
+
+			Convey("Unknown actor", func() {
+				mockUserDatabasePort.EXPECT().FindByID("ghost").Return(nil, nil).Times(1)
+
+				So(roleDomain.CanManage(context.Background(), "ghost", "support"), ShouldNotBeNil)
+			})
+
+			Convey("Personal access tokens count only their scopes", func() {
+				ctx := activity.WithTokenScopes(context.Background(), []string{model.ScopeUsersWrite})
+
+				So(roleDomain.CanManage(ctx, "admin-1", "support"), ShouldNotBeNil)
+				So(roleDomain.CanManage(activity.WithTokenScopes(context.Background(), []string{model.ScopeUsersRead}), "admin-1", "support"), ShouldBeNil)
+			})
+		})
+	})
+}
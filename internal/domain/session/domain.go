@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/jwt"
+)
+
+type SessionDomain interface {
+	List(ctx context.Context, userID string) ([]model.Session, error)
+	Get(ctx context.Context, userID, sessionID string) (*model.Session, error)
+	Revoke(ctx context.Context, userID, sessionID string) error
+	RevokeAll(ctx context.Context, userID string) error
+	RevokeOthers(ctx context.Context, userID, keepSessionID string) error
+}
+
+type sessionDomain struct {
+	db    outbound_port.DatabasePort
+	cache outbound_port.CachePort
+}
+
+func NewSessionDomain(db outbound_port.DatabasePort, cache outbound_port.CachePort) SessionDomain {
+	return &sessionDomain{
+		db:    db,
+		cache: cache,
+	}
+}
+
+func (d *sessionDomain) List(ctx context.Context, userID string) ([]model.Session, error) {
+	sessions, err := d.db.Token().FindSessionsByUserID(userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find sessions failed")
+	}
+	return sessions, nil
+}
+
+func (d *sessionDomain) Get(ctx context.Context, userID, sessionID string) (*model.Session, error) {
+	session, err := d.db.Token().FindSessionByFamily(sessionID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find session failed")
+	}
+	// Sessions of other users are reported as missing
+	if session == nil || session.UserID != userID {
+		return nil, stacktrace.NewError("session not found")
+	}
+	return session, nil
+}
+
+func (d *sessionDomain) Revoke(ctx context.Context, userID, sessionID string) error {
+	session, err := d.Get(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := d.db.Token().DeleteByFamily(session.ID); err != nil {
+		return stacktrace.Propagate(err, "revoke session failed")
+	}
+	// Access tokens already handed out for the session die with it
+	if err := d.cache.Revocation().RevokeSession(session.ID, jwt.AccessTokenLifetime()); err != nil {
+		return stacktrace.Propagate(err, "deny session access tokens failed")
+	}
+	return nil
+}
+
+func (d *sessionDomain) RevokeAll(ctx context.Context, userID string) error {
+	sessions, err := d.db.Token().FindSessionsByUserID(userID)
+	if err != nil {
+		return stacktrace.Propagate(err, "find sessions failed")
+	}
+
+	if err := d.db.Token().DeleteByUserIDAndType(userID, model.TokenTypeRefresh); err != nil {
+		return stacktrace.Propagate(err, "revoke sessions failed")
+	}
+	// Impersonations of the user are signed out with everything else
+	if err := d.db.Token().DeleteByUserIDAndType(userID, model.TokenTypeImpersonation); err != nil {
+		return stacktrace.Propagate(err, "revoke impersonations failed")
+	}
+
+	revocation := d.cache.Revocation()
+	for _, session := range sessions {
+		if err := revocation.RevokeSession(session.ID, jwt.AccessTokenLifetime()); err != nil {
+			return stacktrace.Propagate(err, "deny session access tokens failed")
+		}
+	}
+	return nil
+}
+
+// RevokeOthers ends every session of the user except keepSessionID, the caller's own
+func (d *sessionDomain) RevokeOthers(ctx context.Context, userID, keepSessionID string) error {
+	if keepSessionID == "" {
+		return d.RevokeAll(ctx, userID)
+	}
+
+	sessions, err := d.db.Token().FindSessionsByUserID(userID)
+	if err != nil {
+		return stacktrace.Propagate(err, "find sessions failed")
+	}
+
+	revocation := d.cache.Revocation()
+	for _, session := range sessions {
+		if session.ID == keepSessionID {
+			continue
+		}
+		if err := d.db.Token().DeleteByFamily(session.ID); err != nil {
+			return stacktrace.Propagate(err, "revoke session failed")
+		}
+		if err := revocation.RevokeSession(session.ID, jwt.AccessTokenLifetime()); err != nil {
+			return stacktrace.Propagate(err, "deny session access tokens failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestSession(t *testing.T) {
+	Convey("Test Session", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+
+		sessionDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort)
+
+		session := &model.Session{
+			ID:         "family-1",
+			UserID:     "user-1",
+			IP:         "127.0.0.1",
+			UserAgent:  "test-agent",
+			CreatedAt:  time.Now(),
+			LastUsedAt: time.Now(),
+			Expires:    time.Now().Add(time.Hour),
+		}
+
+		Convey("List", func() {
+			Convey("Database error", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return(nil, errors.New("error")).Times(1)
+
+				_, err := sessionDomain.Session().List(context.Background(), "user-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return([]model.Session{*session}, nil).Times(1)
+
+				results, err := sessionDomain.Session().List(context.Background(), "user-1")
+				So(err, ShouldBeNil)
+				So(len(results), ShouldEqual, 1)
+			})
+		})
+
+		Convey("Get", func() {
+			Convey("Session of another user", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionByFamily("family-1").Return(session, nil).Times(1)
+
+				_, err := sessionDomain.Session().Get(context.Background(), "user-2", "family-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionByFamily("family-1").Return(session, nil).Times(1)
+
+				result, err := sessionDomain.Session().Get(context.Background(), "user-1", "family-1")
+				So(err, ShouldBeNil)
+				So(result.IP, ShouldEqual, "127.0.0.1")
+			})
+		})
+
+		Convey("Revoke", func() {
+			Convey("Session not found", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionByFamily("family-1").Return(nil, nil).Times(1)
+
+				err := sessionDomain.Session().Revoke(context.Background(), "user-1", "family-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionByFamily("family-1").Return(session, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByFamily("family-1").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				err := sessionDomain.Session().Revoke(context.Background(), "user-1", "family-1")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("RevokeAll", func() {
+			Convey("Cache error", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return([]model.Session{*session}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeRefresh).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeImpersonation).Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(errors.New("error")).Times(1)
+
+				err := sessionDomain.Session().RevokeAll(context.Background(), "user-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return([]model.Session{*session}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeRefresh).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeImpersonation).Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				err := sessionDomain.Session().RevokeAll(context.Background(), "user-1")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("RevokeOthers", func() {
+			other := *session
+			other.ID = "family-2"
+
+			Convey("Keeps the current session", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return([]model.Session{*session, other}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByFamily("family-2").Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-2", gomock.Any()).Return(nil).Times(1)
+
+				err := sessionDomain.Session().RevokeOthers(context.Background(), "user-1", "family-1")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Without a current session revokes all", func() {
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return([]model.Session{*session}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeRefresh).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeImpersonation).Return(nil).Times(1)
+				mockRevocationCachePort.EXPECT().RevokeSession("family-1", gomock.Any()).Return(nil).Times(1)
+
+				err := sessionDomain.Session().RevokeOthers(context.Background(), "user-1", "")
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -6,28 +6,46 @@ import (
 
 	"github.com/palantir/stacktrace"
 
+	"prabogo/internal/domain/lockout"
+	"prabogo/internal/domain/role"
+	"prabogo/internal/domain/session"
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils"
 	"prabogo/utils/password"
 )
 
 type UserDomain interface {
-	Create(ctx context.Context, input model.UserInput) (*model.User, error)
+	// Create, Update and Delete refuse actors lacking any permission of the user's role, see RoleDomain.CanManage
+	Create(ctx context.Context, actorID string, input model.UserInput) (*model.User, error)
 	GetByID(ctx context.Context, id string) (*model.User, error)
 	GetAll(ctx context.Context, filters model.UserFilter, page, limit int, sort string) ([]model.User, int64, error)
-	Update(ctx context.Context, id string, input model.UserInput) (*model.User, error)
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, actorID, id string, input model.UserInput) (*model.User, error)
+	Delete(ctx context.Context, actorID, id string) error
+
+	GetLocked(ctx context.Context) ([]model.Lockout, error)
+	GetLockout(ctx context.Context, id string) (*model.Lockout, error)
+	Unlock(ctx context.Context, id string) error
+
+	// Export gathers everything stored about the user, for GDPR-style access requests
+	Export(ctx context.Context, id string) (*model.UserExport, error)
+	// PurgeScheduledDeletions hard-deletes the accounts whose deletion grace period is over
+	PurgeScheduledDeletions(ctx context.Context, batchSize int) (int, error)
 }
 
 type userDomain struct {
-	db outbound_port.DatabasePort
+	db    outbound_port.DatabasePort
+	cache outbound_port.CachePort
 }
 
-func NewUserDomain(db outbound_port.DatabasePort) UserDomain {
-	return &userDomain{db: db}
+func NewUserDomain(db outbound_port.DatabasePort, cache outbound_port.CachePort) UserDomain {
+	return &userDomain{
+		db:    db,
+		cache: cache,
+	}
 }
 
-func (d *userDomain) Create(ctx context.Context, input model.UserInput) (*model.User, error) {
+func (d *userDomain) Create(ctx context.Context, actorID string, input model.UserInput) (*model.User, error) {
 	repo := d.db.User()
 
 	exists, err := repo.ExistsByEmail(input.Email)
@@ -38,16 +56,37 @@ func (d *userDomain) Create(ctx context.Context, input model.UserInput) (*model.
 		return nil, stacktrace.NewError("email already taken")
 	}
 
-	hashed, err := password.HashPassword(input.Password)
+	roleName := input.Role
+	if roleName == "" {
+		roleName = model.RoleUser
+	}
+	if err := role.NewRoleDomain(d.db).CanManage(ctx, actorID, roleName); err != nil {
+		return nil, err
+	}
+
+	passwordless := input.Passwordless != nil && *input.Passwordless
+	secret := input.Password
+	if passwordless {
+		if input.Password != "" {
+			return nil, stacktrace.NewError("passwordless accounts cannot have a password")
+		}
+		// Never handed out, it only fills the column
+		secret = utils.GenerateSecureToken(32)
+	} else if err := password.ValidatePassword(input.Password, input.Name, input.Email); err != nil {
+		return nil, err
+	}
+
+	hashed, err := password.HashPassword(secret)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "hash password failed")
 	}
 
 	user := &model.User{
-		Name:     input.Name,
-		Email:    input.Email,
-		Password: hashed,
-		Role:     input.Role,
+		Name:         input.Name,
+		Email:        input.Email,
+		Password:     hashed,
+		Role:         roleName,
+		Passwordless: passwordless,
 	}
 	model.UserPrepare(user)
 
@@ -73,7 +112,7 @@ func (d *userDomain) GetAll(ctx context.Context, filters model.UserFilter, page,
 	return d.db.User().FindAll(filters, page, limit, sort)
 }
 
-func (d *userDomain) Update(ctx context.Context, id string, input model.UserInput) (*model.User, error) {
+func (d *userDomain) Update(ctx context.Context, actorID, id string, input model.UserInput) (*model.User, error) {
 	repo := d.db.User()
 	user, err := repo.FindByID(id)
 	if err != nil {
@@ -82,20 +121,44 @@ func (d *userDomain) Update(ctx context.Context, id string, input model.UserInpu
 	if user == nil {
 		return nil, stacktrace.NewError("user not found")
 	}
+	if err := role.NewRoleDomain(d.db).CanManage(ctx, actorID, user.Role); err != nil {
+		return nil, err
+	}
 
-	if input.Email != "" && input.Email != user.Email {
+	emailChanged := input.Email != "" && input.Email != user.Email
+	if emailChanged {
 		exists, _ := repo.ExistsByEmail(input.Email)
 		if exists {
 			return nil, stacktrace.NewError("email already taken")
 		}
 		user.Email = input.Email
+		// A new address has to be verified again
+		user.IsEmailVerified = false
 	}
 
 	if input.Name != "" {
 		user.Name = input.Name
 	}
 
+	if input.Passwordless != nil && *input.Passwordless != user.Passwordless {
+		user.Passwordless = *input.Passwordless
+		if user.Passwordless {
+			// Drop the old password; the user signs in by magic link from now on
+			hashed, err := password.HashPassword(utils.GenerateSecureToken(32))
+			if err != nil {
+				return nil, err
+			}
+			user.Password = hashed
+		}
+	}
+
 	if input.Password != "" {
+		if user.Passwordless {
+			return nil, stacktrace.NewError("passwordless accounts cannot have a password")
+		}
+		if err := password.ValidatePassword(input.Password, user.Name, user.Email); err != nil {
+			return nil, err
+		}
 		hashed, err := password.HashPassword(input.Password)
 		if err != nil {
 			return nil, err
@@ -103,6 +166,13 @@ func (d *userDomain) Update(ctx context.Context, id string, input model.UserInpu
 		user.Password = hashed
 	}
 
+	// Links sent to the old address must not verify the new one
+	if emailChanged {
+		if err := d.db.Token().DeleteByUserIDAndType(user.ID, model.TokenTypeVerifyEmail); err != nil {
+			return nil, stacktrace.Propagate(err, "delete verify email tokens failed")
+		}
+	}
+
 	user.UpdatedAt = time.Now()
 	if err := repo.Update(user); err != nil {
 		return nil, err
@@ -111,10 +181,108 @@ func (d *userDomain) Update(ctx context.Context, id string, input model.UserInpu
 	return user, nil
 }
 
-func (d *userDomain) Delete(ctx context.Context, id string) error {
-	repo := d.db.User()
-	if _, err := repo.FindByID(id); err != nil {
+func (d *userDomain) Delete(ctx context.Context, actorID, id string) error {
+	user, err := d.db.User().FindByID(id)
+	if err != nil || user == nil {
 		return stacktrace.NewError("user not found")
 	}
-	return repo.Delete(id)
-}
\ No newline at end of file
+	if err := role.NewRoleDomain(d.db).CanManage(ctx, actorID, user.Role); err != nil {
+		return err
+	}
+	return d.remove(ctx, id)
+}
+
+func (d *userDomain) remove(ctx context.Context, id string) error {
+	// Cut off outstanding access tokens before the tokens rows cascade away
+	if err := session.NewSessionDomain(d.db, d.cache).RevokeAll(ctx, id); err != nil {
+		return stacktrace.Propagate(err, "revoke sessions failed")
+	}
+	return d.db.User().Delete(id)
+}
+
+// GetLocked lists the accounts currently locked out after failed logins
+func (d *userDomain) GetLocked(ctx context.Context) ([]model.Lockout, error) {
+	return lockout.NewLockoutDomain(d.db, d.cache).List(ctx)
+}
+
+// GetLockout returns nil when the account is not locked
+func (d *userDomain) GetLockout(ctx context.Context, id string) (*model.Lockout, error) {
+	return lockout.NewLockoutDomain(d.db, d.cache).Get(ctx, id)
+}
+
+func (d *userDomain) Unlock(ctx context.Context, id string) error {
+	return lockout.NewLockoutDomain(d.db, d.cache).Unlock(ctx, id)
+}
+
+func (d *userDomain) Export(ctx context.Context, id string) (*model.UserExport, error) {
+	user, err := d.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := session.NewSessionDomain(d.db, d.cache).List(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := d.db.Identity().FindByUserID(id)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find identities failed")
+	}
+	accessTokens, err := d.db.AccessToken().FindByUserID(id)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find access tokens failed")
+	}
+	twoFactor, err := d.db.TwoFactor().FindByUserID(id)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find two-factor failed")
+	}
+	audit, err := d.db.Audit().FindByUserID(id)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find audit entries failed")
+	}
+
+	export := &model.UserExport{
+		ExportedAt:       time.Now(),
+		Profile:          *user,
+		Sessions:         sessions,
+		Identities:       identities,
+		AccessTokens:     accessTokens,
+		TwoFactorEnabled: twoFactor != nil && twoFactor.Enabled,
+		Audit:            audit,
+	}
+	// Empty lists rather than null keep the archive easy to consume
+	if export.Sessions == nil {
+		export.Sessions = []model.Session{}
+	}
+	if export.Identities == nil {
+		export.Identities = []model.UserIdentity{}
+	}
+	if export.AccessTokens == nil {
+		export.AccessTokens = []model.PersonalAccessToken{}
+	}
+	if export.Audit == nil {
+		export.Audit = []model.AuditEntry{}
+	}
+	return export, nil
+}
+
+func (d *userDomain) PurgeScheduledDeletions(ctx context.Context, batchSize int) (int, error) {
+	purged := 0
+	for {
+		users, err := d.db.User().FindDeletionDue(time.Now(), batchSize)
+		if err != nil {
+			return purged, stacktrace.Propagate(err, "find accounts due for deletion failed")
+		}
+
+		for _, user := range users {
+			if err := d.remove(ctx, user.ID); err != nil {
+				return purged, stacktrace.Propagate(err, "delete account %s failed", user.ID)
+			}
+			purged++
+		}
+
+		if len(users) < batchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestUser(t *testing.T) {
+	Convey("Test User", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockEmailPort := mock_outbound_port.NewMockEmailPort(mockCtrl)
+		mockDirectoryPort := mock_outbound_port.NewMockDirectoryPort(mockCtrl)
+
+		mockUserDatabasePort := mock_outbound_port.NewMockUserDatabasePort(mockCtrl)
+		mockTokenDatabasePort := mock_outbound_port.NewMockTokenDatabasePort(mockCtrl)
+		mockIdentityDatabasePort := mock_outbound_port.NewMockIdentityDatabasePort(mockCtrl)
+		mockAccessTokenDatabasePort := mock_outbound_port.NewMockAccessTokenDatabasePort(mockCtrl)
+		mockTwoFactorDatabasePort := mock_outbound_port.NewMockTwoFactorDatabasePort(mockCtrl)
+		mockAuditDatabasePort := mock_outbound_port.NewMockAuditDatabasePort(mockCtrl)
+		mockRevocationCachePort := mock_outbound_port.NewMockRevocationCachePort(mockCtrl)
+		mockRoleDatabasePort := mock_outbound_port.NewMockRoleDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().Role().Return(mockRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().User().Return(mockUserDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Token().Return(mockTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Identity().Return(mockIdentityDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().AccessToken().Return(mockAccessTokenDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().TwoFactor().Return(mockTwoFactorDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Audit().Return(mockAuditDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Revocation().Return(mockRevocationCachePort).AnyTimes()
+
+		userDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockEmailPort, mockDirectoryPort).User()
+
+		Convey("Create, Update and Delete", func() {
+			support := &model.User{ID: "support-1", Role: "support"}
+			mockUserDatabasePort.EXPECT().FindByID(support.ID).Return(support, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().FindByName("support").Return(&model.Role{Name: "support", Permissions: []string{model.PermissionUsersWrite}}, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().FindByName(model.RoleUser).Return(&model.Role{Name: model.RoleUser, Permissions: []string{}}, nil).AnyTimes()
+			mockRoleDatabasePort.EXPECT().FindByName(model.RoleAdmin).Return(&model.Role{Name: model.RoleAdmin, Permissions: []string{model.PermissionUsersWrite, model.PermissionRolesWrite}}, nil).AnyTimes()
+
+			Convey("Cannot create a user with more permissions than the actor", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).Times(0)
+
+				_, err := userDomain.Create(context.Background(), support.ID, model.UserInput{Name: "New", Email: "new@example.com", Password: "correct7horse", Role: model.RoleAdmin})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Created users get the user role by default", func() {
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+				created, err := userDomain.Create(context.Background(), support.ID, model.UserInput{Name: "New", Email: "new@example.com", Password: "correct7horse"})
+				So(err, ShouldBeNil)
+				So(created.Role, ShouldEqual, model.RoleUser)
+			})
+
+			Convey("Cannot edit a user with more permissions than the actor", func() {
+				mockUserDatabasePort.EXPECT().FindByID("admin-1").Return(&model.User{ID: "admin-1", Role: model.RoleAdmin, Email: "admin@example.com"}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Update(gomock.Any()).Times(0)
+
+				_, err := userDomain.Update(context.Background(), support.ID, "admin-1", model.UserInput{Email: "attacker@example.com"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Changing the email drops earlier verification links", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1", Role: model.RoleUser, Email: "old@example.com", IsEmailVerified: true}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().ExistsByEmail("new@example.com").Return(false, nil).Times(1)
+				gomock.InOrder(
+					mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeVerifyEmail).Return(nil),
+					mockUserDatabasePort.EXPECT().Update(gomock.Any()).Return(nil),
+				)
+
+				updated, err := userDomain.Update(context.Background(), support.ID, "user-1", model.UserInput{Email: "new@example.com"})
+				So(err, ShouldBeNil)
+				So(updated.IsEmailVerified, ShouldBeFalse)
+			})
+
+			Convey("Cannot delete a user with more permissions than the actor", func() {
+				mockUserDatabasePort.EXPECT().FindByID("admin-1").Return(&model.User{ID: "admin-1", Role: model.RoleAdmin}, nil).Times(1)
+				mockUserDatabasePort.EXPECT().Delete(gomock.Any()).Times(0)
+
+				So(userDomain.Delete(context.Background(), support.ID, "admin-1"), ShouldNotBeNil)
+			})
+
+			Convey("Deletes a user the actor can manage", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(&model.User{ID: "user-1", Role: model.RoleUser}, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return(nil, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeRefresh).Return(nil).Times(1)
+				mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType("user-1", model.TokenTypeImpersonation).Return(nil).Times(1)
+				mockUserDatabasePort.EXPECT().Delete("user-1").Return(nil).Times(1)
+
+				So(userDomain.Delete(context.Background(), support.ID, "user-1"), ShouldBeNil)
+			})
+		})
+
+		Convey("Export", func() {
+			user := &model.User{ID: "user-1", Email: "user@example.com"}
+
+			Convey("User not found", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(nil, nil).Times(1)
+
+				_, err := userDomain.Export(context.Background(), "user-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Lookup error", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(user, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return(nil, nil).Times(1)
+				mockIdentityDatabasePort.EXPECT().FindByUserID("user-1").Return(nil, errors.New("error")).Times(1)
+
+				_, err := userDomain.Export(context.Background(), "user-1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Gathers profile, sessions, identities, tokens and audit entries", func() {
+				mockUserDatabasePort.EXPECT().FindByID("user-1").Return(user, nil).Times(1)
+				mockTokenDatabasePort.EXPECT().FindSessionsByUserID("user-1").Return([]model.Session{{ID: "family-1", UserID: "user-1"}}, nil).Times(1)
+				mockIdentityDatabasePort.EXPECT().FindByUserID("user-1").Return(nil, nil).Times(1)
+				mockAccessTokenDatabasePort.EXPECT().FindByUserID("user-1").Return([]model.PersonalAccessToken{{ID: "pat-1", UserID: "user-1"}}, nil).Times(1)
+				mockTwoFactorDatabasePort.EXPECT().FindByUserID("user-1").Return(&model.TwoFactor{UserID: "user-1", Enabled: true}, nil).Times(1)
+				mockAuditDatabasePort.EXPECT().FindByUserID("user-1").Return([]model.AuditEntry{{ID: 1, UserID: "user-1", Action: model.AuditActionSignIn}}, nil).Times(1)
+
+				export, err := userDomain.Export(context.Background(), "user-1")
+				So(err, ShouldBeNil)
+				So(export.Profile.Email, ShouldEqual, "user@example.com")
+				So(export.Sessions, ShouldHaveLength, 1)
+				So(export.Identities, ShouldNotBeNil)
+				So(export.Identities, ShouldBeEmpty)
+				So(export.AccessTokens, ShouldHaveLength, 1)
+				So(export.TwoFactorEnabled, ShouldBeTrue)
+				So(export.ExportedAt.IsZero(), ShouldBeFalse)
+				So(export.Audit, ShouldHaveLength, 1)
+				So(export.Audit[0].Action, ShouldEqual, model.AuditActionSignIn)
+			})
+		})
+
+		Convey("PurgeScheduledDeletions", func() {
+			Convey("Lookup error", func() {
+				mockUserDatabasePort.EXPECT().FindDeletionDue(gomock.Any(), 2).Return(nil, errors.New("error")).Times(1)
+
+				purged, err := userDomain.PurgeScheduledDeletions(context.Background(), 2)
+				So(err, ShouldNotBeNil)
+				So(purged, ShouldEqual, 0)
+			})
+
+			Convey("Deletes due accounts batch by batch", func() {
+				gomock.InOrder(
+					mockUserDatabasePort.EXPECT().FindDeletionDue(gomock.Any(), 2).Return([]model.User{{ID: "user-1"}, {ID: "user-2"}}, nil),
+					mockUserDatabasePort.EXPECT().FindDeletionDue(gomock.Any(), 2).Return([]model.User{{ID: "user-3"}}, nil),
+				)
+				for _, id := range []string{"user-1", "user-2", "user-3"} {
+					mockTokenDatabasePort.EXPECT().FindSessionsByUserID(id).Return(nil, nil).Times(1)
+					mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(id, model.TokenTypeRefresh).Return(nil).Times(1)
+					mockTokenDatabasePort.EXPECT().DeleteByUserIDAndType(id, model.TokenTypeImpersonation).Return(nil).Times(1)
+					mockUserDatabasePort.EXPECT().Delete(id).Return(nil).Times(1)
+				}
+
+				purged, err := userDomain.PurgeScheduledDeletions(context.Background(), 2)
+				So(err, ShouldBeNil)
+				So(purged, ShouldEqual, 3)
+			})
+		})
+	})
+}
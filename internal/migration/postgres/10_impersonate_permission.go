@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upImpersonatePermission, downImpersonatePermission)
+}
+
+func upImpersonatePermission(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`INSERT INTO permissions (name, description) VALUES
+		('users:impersonate', 'Act as another user for support')
+		ON CONFLICT (name) DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO role_permissions (role, permission) VALUES
+		('admin', 'users:impersonate')
+		ON CONFLICT DO NOTHING;`)
+	return err
+}
+
+func downImpersonatePermission(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DELETE FROM permissions WHERE name = 'users:impersonate';`)
+	return err
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientScope, downClientScope)
+}
+
+func upClientScope(ctx context.Context, tx *sql.Tx) error {
+	// Scopes a service client may request from the client_credentials grant
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS client_scopes (
+		client_id INTEGER NOT NULL,
+		scope VARCHAR(100) NOT NULL,
+		PRIMARY KEY (client_id, scope),
+		FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientScope(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS client_scopes;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
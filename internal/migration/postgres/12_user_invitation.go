@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserInvitation, downUserInvitation)
+}
+
+func upUserInvitation(ctx context.Context, tx *sql.Tx) error {
+	// Invited users stay pending, unable to sign in, until they accept
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS pending BOOLEAN DEFAULT FALSE NOT NULL;`)
+	if err != nil {
+		return err
+	}
+
+	// One open invitation per pending user; only the SHA-256 of its token is kept
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS invitations (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) UNIQUE NOT NULL,
+		token_hash VARCHAR(64) UNIQUE NOT NULL,
+		invited_by VARCHAR(36) NULL,
+		expires_at TIMESTAMP NOT NULL,
+		sent_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+	);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downUserInvitation(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE invitations;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE users DROP COLUMN IF EXISTS pending;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserDeletion, downUserDeletion)
+}
+
+func upUserDeletion(ctx context.Context, tx *sql.Tx) error {
+	// Set when a user asks to delete their account; the row is purged once it passes
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP NULL;`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downUserDeletion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
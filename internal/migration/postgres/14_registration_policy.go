@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upRegistrationPolicy, downRegistrationPolicy)
+}
+
+func upRegistrationPolicy(ctx context.Context, tx *sql.Tx) error {
+	// A single row, edited by admins at runtime; registration stays open until it is saved.
+	// Domain lists are comma separated, like personal access token scopes.
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS registration_policy (
+		id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+		mode VARCHAR(20) NOT NULL,
+		allowed_domains TEXT NOT NULL DEFAULT '',
+		denied_domains TEXT NOT NULL DEFAULT '',
+		updated_by VARCHAR(36) NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO permissions (name, description) VALUES
+		('settings:read', 'View application settings such as the registration policy'),
+		('settings:write', 'Change application settings such as the registration policy')
+		ON CONFLICT (name) DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO role_permissions (role, permission) VALUES
+		('admin', 'settings:read'),
+		('admin', 'settings:write')
+		ON CONFLICT DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downRegistrationPolicy(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DELETE FROM permissions WHERE name IN ('settings:read', 'settings:write');`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE IF EXISTS registration_policy;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
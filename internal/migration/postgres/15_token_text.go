@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upTokenText, downTokenText)
+}
+
+func upTokenText(ctx context.Context, tx *sql.Tx) error {
+	// Signed JWTs carrying jti, sid and act claims, or signed with RSA/ECDSA keys, do not fit
+	// in 255 characters
+	_, err := tx.Exec(`ALTER TABLE tokens ALTER COLUMN token TYPE TEXT;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downTokenText(ctx context.Context, tx *sql.Tx) error {
+	// Tokens too long for the old column cannot be kept, their holders sign in again
+	_, err := tx.Exec(`DELETE FROM tokens WHERE length(token) > 255;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE tokens ALTER COLUMN token TYPE VARCHAR(255);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
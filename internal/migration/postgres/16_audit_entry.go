@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAuditEntry, downAuditEntry)
+}
+
+func upAuditEntry(ctx context.Context, tx *sql.Tx) error {
+	// Security events per account, handed to the user in their data export and removed with them
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS audit_entries (
+		id SERIAL PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		actor_id VARCHAR(36) NULL,
+		action VARCHAR(50) NOT NULL,
+		ip VARCHAR(45) NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_audit_entries_user_id ON audit_entries(user_id);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAuditEntry(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS audit_entries;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
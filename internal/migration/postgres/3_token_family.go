@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upTokenFamily, downTokenFamily)
+}
+
+func upTokenFamily(ctx context.Context, tx *sql.Tx) error {
+	// Refresh tokens issued from the same login share a family
+	_, err := tx.Exec(`ALTER TABLE tokens ADD COLUMN IF NOT EXISTS family VARCHAR(36) NOT NULL DEFAULT '';`)
+	if err != nil {
+		return err
+	}
+
+	// Every pre-existing refresh token becomes its own family
+	_, err = tx.Exec(`UPDATE tokens SET family = 'legacy-' || id WHERE type = 'refresh' AND family = '';`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_tokens_family ON tokens(family);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downTokenFamily(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP INDEX IF EXISTS idx_tokens_family;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE tokens DROP COLUMN IF EXISTS family;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upTokenSession, downTokenSession)
+}
+
+func upTokenSession(ctx context.Context, tx *sql.Tx) error {
+	// Device metadata captured when a token pair is issued
+	_, err := tx.Exec(`ALTER TABLE tokens
+		ADD COLUMN IF NOT EXISTS ip VARCHAR(45) NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_tokens_user_id_type ON tokens(user_id, type);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downTokenSession(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP INDEX IF EXISTS idx_tokens_user_id_type;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE tokens DROP COLUMN IF EXISTS ip, DROP COLUMN IF EXISTS user_agent;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upTwoFactor, downTwoFactor)
+}
+
+func upTwoFactor(ctx context.Context, tx *sql.Tx) error {
+	// TOTP secret per user, enabled once the first code is confirmed
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS user_two_factors (
+		user_id VARCHAR(36) PRIMARY KEY,
+		secret VARCHAR(64) NOT NULL,
+		enabled BOOLEAN DEFAULT FALSE NOT NULL,
+		last_used_step BIGINT DEFAULT 0 NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+
+	// One-time recovery codes, stored as SHA-256 hashes
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS user_recovery_codes (
+		id SERIAL PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		code_hash VARCHAR(64) NOT NULL,
+		used_at TIMESTAMP NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downTwoFactor(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE user_recovery_codes;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE user_two_factors;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
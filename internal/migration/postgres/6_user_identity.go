@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserIdentity, downUserIdentity)
+}
+
+func upUserIdentity(ctx context.Context, tx *sql.Tx) error {
+	// Links an account at an external identity provider to a local user
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS user_identities (
+		id SERIAL PRIMARY KEY,
+		provider VARCHAR(50) NOT NULL,
+		subject VARCHAR(255) NOT NULL,
+		user_id VARCHAR(36) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		UNIQUE (provider, subject),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downUserIdentity(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE user_identities;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserPasswordless, downUserPasswordless)
+}
+
+func upUserPasswordless(ctx context.Context, tx *sql.Tx) error {
+	// Passwordless accounts only sign in by magic link
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS passwordless BOOLEAN DEFAULT FALSE NOT NULL;`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downUserPasswordless(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users DROP COLUMN IF EXISTS passwordless;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPersonalAccessToken, downPersonalAccessToken)
+}
+
+func upPersonalAccessToken(ctx context.Context, tx *sql.Tx) error {
+	// Only the SHA-256 of a token is kept; scopes are stored comma separated
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS personal_access_tokens (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		token_hash VARCHAR(64) UNIQUE NOT NULL,
+		prefix VARCHAR(16) NOT NULL,
+		scopes TEXT NOT NULL DEFAULT '',
+		expires_at TIMESTAMP NOT NULL,
+		last_used_at TIMESTAMP NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downPersonalAccessToken(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE personal_access_tokens;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upRolePermission, downRolePermission)
+}
+
+func upRolePermission(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS roles (
+		name VARCHAR(50) PRIMARY KEY,
+		description VARCHAR(255) NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS permissions (
+		name VARCHAR(100) PRIMARY KEY,
+		description VARCHAR(255) NOT NULL DEFAULT ''
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS role_permissions (
+		role VARCHAR(50) NOT NULL,
+		permission VARCHAR(100) NOT NULL,
+		PRIMARY KEY (role, permission),
+		FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE,
+		FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO permissions (name, description) VALUES
+		('users:read', 'List and view any user'),
+		('users:write', 'Create, update, delete and unlock any user'),
+		('sessions:read', 'View the sessions of any user'),
+		('sessions:write', 'Revoke the sessions of any user'),
+		('roles:read', 'View roles and permissions'),
+		('roles:write', 'Manage roles and assign them to users')
+		ON CONFLICT (name) DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	// The two roles the code used to hard-code; admin keeps full access
+	_, err = tx.Exec(`INSERT INTO roles (name, description) VALUES
+		('admin', 'Full access'),
+		('user', 'Own account only')
+		ON CONFLICT (name) DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO role_permissions (role, permission)
+		SELECT 'admin', name FROM permissions
+		ON CONFLICT DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	// Any other role already stored on a user becomes a role without permissions
+	_, err = tx.Exec(`UPDATE users SET role = 'user' WHERE role IS NULL OR role = '';`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO roles (name, description)
+		SELECT DISTINCT role, '' FROM users
+		ON CONFLICT (name) DO NOTHING;`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE users ALTER COLUMN role SET NOT NULL;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name);`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downRolePermission(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE users ALTER COLUMN role DROP NOT NULL;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE role_permissions;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE permissions;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DROP TABLE roles;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// PersonalAccessTokenPrefix marks a bearer credential as a personal access token rather than a JWT
+const PersonalAccessTokenPrefix = "pat_"
+
+// Scopes a personal access token can be granted. Session logins are not limited by scopes.
+// They share the permission names; a token never does more than its owner's role allows.
+const (
+	ScopeUsersRead     = PermissionUsersRead
+	ScopeUsersWrite    = PermissionUsersWrite
+	ScopeSessionsRead  = PermissionSessionsRead
+	ScopeSessionsWrite = PermissionSessionsWrite
+	ScopeRolesRead     = PermissionRolesRead
+	ScopeRolesWrite    = PermissionRolesWrite
+	ScopeSettingsRead  = PermissionSettingsRead
+	ScopeSettingsWrite = PermissionSettingsWrite
+)
+
+// AccessTokenScopes lists every scope a token may ask for
+var AccessTokenScopes = []string{ScopeUsersRead, ScopeUsersWrite, ScopeSessionsRead, ScopeSessionsWrite, ScopeRolesRead, ScopeRolesWrite, ScopeSettingsRead, ScopeSettingsWrite}
+
+type PersonalAccessToken struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Prefix     string     `json:"prefix" db:"prefix"` // First characters of the token, to tell tokens apart
+	Scopes     []string   `json:"scopes" db:"-"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// HasScope reports whether the token was granted scope
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type PersonalAccessTokenInput struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// Audit actions, the security events recorded against an account
+const (
+	AuditActionSignIn            = "sign_in"
+	AuditActionPasswordChanged   = "password_changed"
+	AuditActionPasswordReset     = "password_reset"
+	AuditActionTwoFactorEnabled  = "two_factor_enabled"
+	AuditActionTwoFactorDisabled = "two_factor_disabled"
+	AuditActionImpersonated      = "impersonated"
+	AuditActionDeletionScheduled = "deletion_scheduled"
+	AuditActionDeletionCancelled = "deletion_cancelled"
+)
+
+type AuditEntry struct {
+	ID     int    `json:"id" db:"id"`
+	UserID string `json:"user_id" db:"user_id"`
+	// ActorID is set when someone else acted on the account, such as an impersonating admin
+	ActorID   *string   `json:"actor_id" db:"actor_id"`
+	Action    string    `json:"action" db:"action"`
+	IP        string    `json:"ip" db:"ip"`
+	UserAgent string    `json:"user_agent" db:"user_agent"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -20,6 +20,8 @@ type ClientInput struct {
 	BearerKey string    `json:"bearer_key" db:"bearer_key"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// Scopes granted for the client_credentials grant, kept in client_scopes; nil on upsert keeps them
+	Scopes []string `json:"scopes,omitempty" db:"-"`
 }
 
 type ClientFilter struct {
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+type Invitation struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	InvitedBy *string   `json:"invited_by" db:"invited_by"` // nil once the inviting admin is deleted
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	SentAt    time.Time `json:"sent_at" db:"sent_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// The invited user, joined in for listings
+	Email string `json:"email" db:"-"`
+	Name  string `json:"name" db:"-"`
+	Role  string `json:"role" db:"-"`
+}
+
+// Expired reports whether the invitation can no longer be accepted; resending renews it
+func (i *Invitation) Expired() bool {
+	return !i.ExpiresAt.After(time.Now())
+}
+
+type InvitationInput struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+type AcceptInvitationInput struct {
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
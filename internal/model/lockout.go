@@ -0,0 +1,31 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Lockout describes an account that may not log in until LockedUntil
+type Lockout struct {
+	UserID      string    `json:"user_id"`
+	Email       string    `json:"email"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// LoginThrottledError is returned while an account or address has to wait before trying again
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many login attempts, retry in %d seconds", e.RetrySeconds())
+}
+
+// RetrySeconds rounds up so a client never retries a moment too early
+func (e *LoginThrottledError) RetrySeconds() int {
+	seconds := int((e.RetryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
@@ -0,0 +1,70 @@
+package model
+
+// Error codes of RFC 6749 section 5.2, shared by the token, introspection and revocation endpoints
+const (
+	OAuthErrorInvalidRequest       = "invalid_request"
+	OAuthErrorInvalidClient        = "invalid_client"
+	OAuthErrorInvalidGrant         = "invalid_grant"
+	OAuthErrorInvalidScope         = "invalid_scope"
+	OAuthErrorUnauthorizedClient   = "unauthorized_client"
+	OAuthErrorUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrorUnsupportedTokenType = "unsupported_token_type"
+)
+
+// Grant types accepted by the token endpoint
+const (
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// ScopeTokensRevoke lets a client revoke tokens issued to users; its own tokens it can always revoke
+const ScopeTokensRevoke = "tokens:revoke"
+
+// ClientScopes lists every scope a service client may be granted
+var ClientScopes = append([]string{ScopeTokensRevoke}, AccessTokenScopes...)
+
+// Values of token_type_hint (RFC 7009) and of token_type in introspection responses
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// OAuthError is answered as {"error": Code, "error_description": Description}
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// OAuthToken is the successful token endpoint response (RFC 6749 section 5.1)
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// TokenIntrospection is the RFC 7662 response. Only Active is sent for inactive tokens.
+type TokenIntrospection struct {
+	Active    bool        `json:"active"`
+	Scope     string      `json:"scope,omitempty"`
+	ClientID  string      `json:"client_id,omitempty"`
+	TokenType string      `json:"token_type,omitempty"`
+	Exp       int64       `json:"exp,omitempty"`
+	Iat       int64       `json:"iat,omitempty"`
+	Sub       string      `json:"sub,omitempty"`
+	Iss       string      `json:"iss,omitempty"`
+	Jti       string      `json:"jti,omitempty"`
+	Sid       string      `json:"sid,omitempty"`
+	Act       *TokenActor `json:"act,omitempty"`
+}
+
+// TokenActor names the admin behind an impersonation token
+type TokenActor struct {
+	Sub string `json:"sub"`
+}
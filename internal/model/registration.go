@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+)
+
+// Registration modes. Only self-service sign-up through Register is governed by them;
+// admins creating users and external identity providers are not.
+const (
+	// Anyone may register, except from a denied email domain
+	RegistrationModeOpen = "open"
+	// Only addresses from an allowed email domain may register
+	RegistrationModeRestricted = "restricted"
+	// Nobody may register; admins invite users instead
+	RegistrationModeInviteOnly = "invite_only"
+	// Nobody may register and no new invitation can be sent
+	RegistrationModeClosed = "closed"
+)
+
+// RegistrationModes lists every valid mode
+var RegistrationModes = []string{RegistrationModeOpen, RegistrationModeRestricted, RegistrationModeInviteOnly, RegistrationModeClosed}
+
+// Codes of a RegistrationError, stable for clients to branch on
+const (
+	RegistrationErrorClosed           = "registration_closed"
+	RegistrationErrorInviteOnly       = "registration_invite_only"
+	RegistrationErrorDomainNotAllowed = "email_domain_not_allowed"
+	RegistrationErrorDomainDenied     = "email_domain_denied"
+)
+
+// RegistrationError is a sign-up refused by the registration policy
+type RegistrationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RegistrationError) Error() string {
+	return e.Message
+}
+
+type RegistrationPolicy struct {
+	Mode string `json:"mode" db:"mode"`
+	// Domains match themselves and their subdomains, "example.com" also covers "eu.example.com"
+	AllowedDomains []string   `json:"allowed_domains" db:"-"`
+	DeniedDomains  []string   `json:"denied_domains" db:"-"`
+	UpdatedBy      *string    `json:"updated_by" db:"updated_by"` // nil until saved, or once the admin is deleted
+	UpdatedAt      *time.Time `json:"updated_at" db:"updated_at"` // nil until saved
+}
+
+// DefaultRegistrationPolicy applies until an admin saves one
+func DefaultRegistrationPolicy() *RegistrationPolicy {
+	return &RegistrationPolicy{
+		Mode:           RegistrationModeOpen,
+		AllowedDomains: []string{},
+		DeniedDomains:  []string{},
+	}
+}
+
+// RegistrationPolicyInput replaces the whole policy
+type RegistrationPolicyInput struct {
+	Mode           string   `json:"mode"`
+	AllowedDomains []string `json:"allowed_domains"`
+	DeniedDomains  []string `json:"denied_domains"`
+}
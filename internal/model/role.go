@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// Seeded roles. Any other role is created by an admin at runtime.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permissions checked by RequirePermission
+const (
+	PermissionUsersRead  = "users:read"
+	PermissionUsersWrite = "users:write"
+	// Not a token scope: impersonation needs a real session
+	PermissionUsersImpersonate = "users:impersonate"
+	PermissionSessionsRead     = "sessions:read"
+	PermissionSessionsWrite    = "sessions:write"
+	PermissionRolesRead        = "roles:read"
+	PermissionRolesWrite       = "roles:write"
+	PermissionSettingsRead     = "settings:read"
+	PermissionSettingsWrite    = "settings:write"
+)
+
+type Role struct {
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Permissions []string  `json:"permissions" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+}
+
+type RoleInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Permissions replaces the role's permissions; left out on update keeps them
+	Permissions []string `json:"permissions"`
+}
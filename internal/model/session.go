@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// Session is a login, tracked through its refresh token family
+type Session struct {
+	ID         string    `json:"id" db:"family"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	IP         string    `json:"ip" db:"ip"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at"`
+	Expires    time.Time `json:"expires" db:"expires"`
+	Current    bool      `json:"current" db:"-"`
+}
@@ -9,6 +9,9 @@ const (
 	TokenTypeRefresh       = "refresh"
 	TokenTypeResetPassword = "resetPassword"
 	TokenTypeVerifyEmail   = "verifyEmail"
+	TokenTypeMfaPending    = "mfaPending"
+	TokenTypeMagicLink     = "magicLink"
+	TokenTypeImpersonation = "impersonation"
 )
 
 type Token struct {
@@ -19,6 +22,9 @@ type Token struct {
 	Expires     time.Time `json:"expires" db:"expires"`
 	Blacklisted bool      `json:"blacklisted" db:"blacklisted"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	Family      string    `json:"family" db:"family"`
+	IP          string    `json:"ip" db:"ip"`
+	UserAgent   string    `json:"user_agent" db:"user_agent"`
 }
 
 type TokenInput struct {
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+type TwoFactor struct {
+	UserID       string    `json:"user_id" db:"user_id"`
+	Secret       string    `json:"-" db:"secret"`
+	Enabled      bool      `json:"enabled" db:"enabled"`
+	LastUsedStep int64     `json:"-" db:"last_used_step"` // TOTP step of the last accepted code, blocks replays
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type RecoveryCode struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	CodeHash  string     `json:"-" db:"code_hash"`
+	UsedAt    *time.Time `json:"used_at" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
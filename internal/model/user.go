@@ -15,6 +15,10 @@ type User struct {
 	IsEmailVerified bool      `json:"is_email_verified" db:"is_email_verified"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	Passwordless    bool      `json:"passwordless" db:"passwordless"` // Signs in by magic link only
+	Pending         bool      `json:"pending" db:"pending"`           // Invited, cannot sign in until accepted
+	// Set while a self-service deletion waits out its grace period; signing in cancels it
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at" db:"deletion_scheduled_at"`
 }
 
 type UserInput struct {
@@ -22,6 +26,25 @@ type UserInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	Role     string `json:"role"`
+	// Passwordless is only honoured on admin create/update; nil leaves it unchanged
+	Passwordless *bool `json:"passwordless"`
+}
+
+// DeleteAccountInput re-authenticates a user asking to delete their own account
+type DeleteAccountInput struct {
+	Password string `json:"password"`
+}
+
+// UserExport is everything stored about a user, handed out by GET /v1/users/me/export.
+// Secrets (password and token hashes, 2FA seeds) are left out.
+type UserExport struct {
+	ExportedAt       time.Time             `json:"exported_at"`
+	Profile          User                  `json:"profile"`
+	Sessions         []Session             `json:"sessions"`
+	Identities       []UserIdentity        `json:"identities"`
+	AccessTokens     []PersonalAccessToken `json:"access_tokens"`
+	TwoFactorEnabled bool                  `json:"two_factor_enabled"`
+	Audit            []AuditEntry          `json:"audit"`
 }
 
 type UserFilter struct {
@@ -41,6 +64,6 @@ func UserPrepare(u *User) {
 	}
 	u.UpdatedAt = now
 	if u.Role == "" {
-		u.Role = "user"
+		u.Role = RoleUser
 	}
 }
\ No newline at end of file
@@ -0,0 +1,7 @@
+package inbound_port
+
+type AccessTokenHttpPort interface {
+	Create(a any) error
+	GetList(a any) error
+	Revoke(a any) error
+}
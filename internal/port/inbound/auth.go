@@ -7,4 +7,15 @@ type AuthHttpPort interface {
 	Logout(a any) error
 	ForgotPassword(a any) error
 	ResetPassword(a any) error
+	ChangePassword(a any) error
+	SendVerificationEmail(a any) error
+	VerifyEmail(a any) error
+	RequestMagicLink(a any) error
+	VerifyMagicLink(a any) error
+	EnrollTwoFactor(a any) error
+	ConfirmTwoFactor(a any) error
+	DisableTwoFactor(a any) error
+	RegenerateRecoveryCodes(a any) error
+	VerifyTwoFactor(a any) error
+	AcceptInvitation(a any) error
 }
\ No newline at end of file
@@ -0,0 +1,8 @@
+package inbound_port
+
+type InvitationHttpPort interface {
+	GetList(a any) error
+	Create(a any) error
+	Resend(a any) error
+	Revoke(a any) error
+}
@@ -2,8 +2,12 @@ package inbound_port
 
 type MiddlewareHttpPort interface {
 	Auth(a any) error
-	RequireAdmin(a any) error
-	RequireAdminOrSelf(a any) error
+	RequirePermission(a any, permission string) error
+	RequirePermissionOrSelf(a any, permission string) error
+	RequireVerifiedEmail(a any) error
+	RequireScope(a any, scope string) error
+	RequireClientScope(a any, scope string) error
+	RequireSession(a any) error
 
 	InternalAuth(a any) error
 	ClientAuth(a any) error
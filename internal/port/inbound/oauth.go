@@ -0,0 +1,7 @@
+package inbound_port
+
+type OAuthHttpPort interface {
+	Token(a any) error
+	Introspect(a any) error
+	Revoke(a any) error
+}
@@ -0,0 +1,6 @@
+package inbound_port
+
+type RegistrationHttpPort interface {
+	GetPolicy(a any) error
+	UpdatePolicy(a any) error
+}
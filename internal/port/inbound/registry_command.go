@@ -2,4 +2,6 @@ package inbound_port
 
 type CommandPort interface {
 	Client() ClientCommandPort
+	User() UserCommandPort
+	Token() TokenCommandPort
 }
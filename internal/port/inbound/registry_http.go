@@ -7,4 +7,11 @@ type HttpPort interface {
 	
 	Auth() AuthHttpPort
 	User() UserHttpPort
+	Session() SessionHttpPort
+	AccessToken() AccessTokenHttpPort
+	Role() RoleHttpPort
+	OAuth() OAuthHttpPort
+	Invitation() InvitationHttpPort
+	Registration() RegistrationHttpPort
+	WellKnown() WellKnownHttpPort
 }
\ No newline at end of file
@@ -0,0 +1,10 @@
+package inbound_port
+
+type RoleHttpPort interface {
+	GetList(a any) error
+	GetOne(a any) error
+	Create(a any) error
+	Update(a any) error
+	Delete(a any) error
+	GetPermissions(a any) error
+}
@@ -0,0 +1,8 @@
+package inbound_port
+
+type SessionHttpPort interface {
+	GetList(a any) error
+	GetOne(a any) error
+	Revoke(a any) error
+	RevokeAll(a any) error
+}
@@ -0,0 +1,5 @@
+package inbound_port
+
+type TokenCommandPort interface {
+	Purge()
+}
@@ -6,4 +6,14 @@ type UserHttpPort interface {
 	GetOne(a any) error
 	Update(a any) error
 	Delete(a any) error
-}
\ No newline at end of file
+	GetLocked(a any) error
+	GetLockout(a any) error
+	Unlock(a any) error
+	AssignRole(a any) error
+	Impersonate(a any) error
+	Export(a any) error
+	DeleteMe(a any) error
+}
+type UserCommandPort interface {
+	PurgeScheduledDeletions()
+}
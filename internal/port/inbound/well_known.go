@@ -0,0 +1,6 @@
+package inbound_port
+
+type WellKnownHttpPort interface {
+	JWKS(a any) error
+	OpenIDConfiguration(a any) error
+}
@@ -0,0 +1,18 @@
+package outbound_port
+
+import (
+	"time"
+
+	"prabogo/internal/model"
+)
+
+//go:generate mockgen -source=access_token.go -destination=./../../../tests/mocks/port/mock_access_token.go
+type AccessTokenDatabasePort interface {
+	Create(token *model.PersonalAccessToken) error
+	FindByHash(tokenHash string) (*model.PersonalAccessToken, error)
+	FindByUserID(userID string) ([]model.PersonalAccessToken, error)
+	// Delete removes a token of the user, reporting false when there was none
+	Delete(userID, id string) (bool, error)
+	DeleteByUserID(userID string) error
+	TouchLastUsed(id string, at time.Time) error
+}
@@ -0,0 +1,10 @@
+package outbound_port
+
+import "prabogo/internal/model"
+
+//go:generate mockgen -source=audit.go -destination=./../../../tests/mocks/port/mock_audit.go
+type AuditDatabasePort interface {
+	Create(entry *model.AuditEntry) error
+	// FindByUserID lists the user's entries, oldest first
+	FindByUserID(userID string) ([]model.AuditEntry, error)
+}
@@ -8,6 +8,9 @@ type ClientDatabasePort interface {
 	FindByFilter(filter model.ClientFilter, lock bool) ([]model.Client, error)
 	DeleteByFilter(filter model.ClientFilter) error
 	IsExists(bearerKey string) (bool, error)
+	FindScopes(clientID int) ([]string, error)
+	// SetScopes replaces every scope granted to the client
+	SetScopes(clientID int, scopes []string) error
 }
 
 type ClientMessagePort interface {
@@ -17,4 +20,5 @@ type ClientMessagePort interface {
 type ClientCachePort interface {
 	Set(data model.Client) error
 	Get(bearerKey string) (model.Client, error)
+	Delete(bearerKey string) error
 }
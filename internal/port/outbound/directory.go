@@ -0,0 +1,10 @@
+package outbound_port
+
+import "prabogo/internal/model"
+
+//go:generate mockgen -source=directory.go -destination=./../../../tests/mocks/port/mock_directory.go
+type DirectoryPort interface {
+	// Authenticate binds as the user behind username and returns what the directory holds
+	// about them, nil when the user is unknown or the password is wrong
+	Authenticate(username, password string) (*model.ExternalIdentity, error)
+}
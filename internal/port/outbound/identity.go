@@ -0,0 +1,10 @@
+package outbound_port
+
+import "prabogo/internal/model"
+
+//go:generate mockgen -source=identity.go -destination=./../../../tests/mocks/port/mock_identity.go
+type IdentityDatabasePort interface {
+	FindByProviderSubject(provider, subject string) (*model.UserIdentity, error)
+	Create(identity *model.UserIdentity) error
+	FindByUserID(userID string) ([]model.UserIdentity, error)
+}
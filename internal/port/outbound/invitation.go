@@ -0,0 +1,20 @@
+package outbound_port
+
+import (
+	"time"
+
+	"prabogo/internal/model"
+)
+
+//go:generate mockgen -source=invitation.go -destination=./../../../tests/mocks/port/mock_invitation.go
+type InvitationDatabasePort interface {
+	Create(invitation *model.Invitation) error
+	// Finders join in the invited user's email, name and role
+	FindByID(id string) (*model.Invitation, error)
+	FindByHash(tokenHash string) (*model.Invitation, error)
+	FindAll() ([]model.Invitation, error)
+	// Renew swaps in a new token, so earlier emails stop working
+	Renew(id string, tokenHash string, expiresAt time.Time, sentAt time.Time) error
+	// Consume deletes the invitation while it still holds the token, reporting whether it did
+	Consume(id string, tokenHash string) (bool, error)
+}
@@ -0,0 +1,16 @@
+package outbound_port
+
+import "time"
+
+//go:generate mockgen -source=login_attempt.go -destination=./../../../tests/mocks/port/mock_login_attempt.go
+type LoginAttemptCachePort interface {
+	// RegisterFailure counts a failed attempt against key and returns the total within window
+	RegisterFailure(key string, window time.Duration) (int64, error)
+	Lock(key string, ttl time.Duration) error
+	// LockedFor returns the time left on a lock, zero when key is not locked
+	LockedFor(key string) (time.Duration, error)
+	// Reset drops the failure count and any lock on key
+	Reset(key string) error
+	// Locked lists the locked keys starting with prefix and the time left on each
+	Locked(prefix string) (map[string]time.Duration, error)
+}
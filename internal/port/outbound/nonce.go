@@ -0,0 +1,9 @@
+package outbound_port
+
+import "time"
+
+//go:generate mockgen -source=nonce.go -destination=./../../../tests/mocks/port/mock_nonce.go
+type NonceCachePort interface {
+	// Claim records nonce for ttl, false when it was already recorded
+	Claim(nonce string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,10 @@
+package outbound_port
+
+import "prabogo/internal/model"
+
+//go:generate mockgen -source=registration_policy.go -destination=./../../../tests/mocks/port/mock_registration_policy.go
+type RegistrationPolicyDatabasePort interface {
+	// Get returns nil when no policy was ever saved
+	Get() (*model.RegistrationPolicy, error)
+	Save(policy *model.RegistrationPolicy) error
+}
@@ -9,6 +9,13 @@ type DatabasePort interface {
 	Client() ClientDatabasePort
 	User() UserDatabasePort
 	Token() TokenDatabasePort
+	TwoFactor() TwoFactorDatabasePort
+	Identity() IdentityDatabasePort
+	AccessToken() AccessTokenDatabasePort
+	Role() RoleDatabasePort
+	Invitation() InvitationDatabasePort
+	RegistrationPolicy() RegistrationPolicyDatabasePort
+	Audit() AuditDatabasePort
 	
 	DoInTransaction(txFunc InTransaction) (out interface{}, err error)
 }
@@ -0,0 +1,12 @@
+package outbound_port
+
+import "time"
+
+//go:generate mockgen -source=revocation.go -destination=./../../../tests/mocks/port/mock_revocation.go
+type RevocationCachePort interface {
+	// RevokeToken denies a single access token by its jti until ttl elapses
+	RevokeToken(tokenID string, ttl time.Duration) error
+	// RevokeSession denies every access token carrying the session ID until ttl elapses
+	RevokeSession(sessionID string, ttl time.Duration) error
+	IsRevoked(tokenID string, sessionID string) (bool, error)
+}
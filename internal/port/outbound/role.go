@@ -0,0 +1,17 @@
+package outbound_port
+
+import "prabogo/internal/model"
+
+//go:generate mockgen -source=role.go -destination=./../../../tests/mocks/port/mock_role.go
+type RoleDatabasePort interface {
+	FindAll() ([]model.Role, error)
+	FindByName(name string) (*model.Role, error)
+	Create(role *model.Role) error
+	Update(role *model.Role) error
+	Delete(name string) error
+	// SetPermissions replaces every permission of the role
+	SetPermissions(role string, permissions []string) error
+	FindPermissions() ([]model.Permission, error)
+	HasPermission(role, permission string) (bool, error)
+	CountUsers(role string) (int64, error)
+}
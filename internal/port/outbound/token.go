@@ -1,11 +1,28 @@
 package outbound_port
 
-import "prabogo/internal/model"
+import (
+	"time"
+
+	"prabogo/internal/model"
+)
 
 //go:generate mockgen -source=token.go -destination=./../../../tests/mocks/port/mock_token.go
 type TokenDatabasePort interface {
 	Create(token *model.Token) error
 	FindByToken(tokenStr string, tokenType string) (*model.Token, error)
+	// FindByTokenIncludingBlacklisted also returns rotated/revoked tokens, used for reuse detection
+	FindByTokenIncludingBlacklisted(tokenStr string, tokenType string) (*model.Token, error)
+	// FindSessionsByUserID lists one entry per token family that still has a live refresh token
+	FindSessionsByUserID(userID string) ([]model.Session, error)
+	FindSessionByFamily(family string) (*model.Session, error)
 	DeleteByUserIDAndType(userID string, tokenType string) error
+	DeleteByFamily(family string) error
 	Delete(tokenID int) error
+	// Blacklist marks a live token as rotated, reporting false when it already was
+	Blacklist(tokenID int) (bool, error)
+	BlacklistByFamily(family string) error
+	// PurgeExpired deletes up to limit expired or blacklisted tokens and reports how many went.
+	// Tokens of a family that still has a live refresh token are kept: they date the session
+	// and let a replayed rotated token be detected.
+	PurgeExpired(now time.Time, limit int) (int, error)
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+package outbound_port
+
+import "prabogo/internal/model"
+
+//go:generate mockgen -source=two_factor.go -destination=./../../../tests/mocks/port/mock_two_factor.go
+type TwoFactorDatabasePort interface {
+	FindByUserID(userID string) (*model.TwoFactor, error)
+	Upsert(twoFactor *model.TwoFactor) error
+	// UseStep records step as the last accepted TOTP step, reporting false when it or a later
+	// step was already used
+	UseStep(userID string, step int64) (bool, error)
+	// DeleteByUserID removes the secret together with every recovery code
+	DeleteByUserID(userID string) error
+
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used, reporting whether one matched
+	UseRecoveryCode(userID string, codeHash string) (bool, error)
+}
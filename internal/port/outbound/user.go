@@ -1,6 +1,10 @@
 package outbound_port
 
-import "prabogo/internal/model"
+import (
+	"time"
+
+	"prabogo/internal/model"
+)
 
 //go:generate mockgen -source=user.go -destination=./../../../tests/mocks/port/mock_user.go
 type UserDatabasePort interface {
@@ -12,4 +16,6 @@ type UserDatabasePort interface {
 	ExistsByEmail(email string) (bool, error)
 	Update(user *model.User) error
 	Delete(id string) error
+	// FindDeletionDue lists up to limit users whose scheduled deletion is at or before now
+	FindDeletionDue(now time.Time, limit int) ([]model.User, error)
 }
\ No newline at end of file
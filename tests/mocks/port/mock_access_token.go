@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: access_token.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccessTokenDatabasePort is a mock of AccessTokenDatabasePort interface.
+type MockAccessTokenDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenDatabasePortMockRecorder
+}
+
+// MockAccessTokenDatabasePortMockRecorder is the mock recorder for MockAccessTokenDatabasePort.
+type MockAccessTokenDatabasePortMockRecorder struct {
+	mock *MockAccessTokenDatabasePort
+}
+
+// NewMockAccessTokenDatabasePort creates a new mock instance.
+func NewMockAccessTokenDatabasePort(ctrl *gomock.Controller) *MockAccessTokenDatabasePort {
+	mock := &MockAccessTokenDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenDatabasePort) EXPECT() *MockAccessTokenDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccessTokenDatabasePort) Create(token *model.PersonalAccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessTokenDatabasePortMockRecorder) Create(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessTokenDatabasePort)(nil).Create), token)
+}
+
+// Delete mocks base method.
+func (m *MockAccessTokenDatabasePort) Delete(userID, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessTokenDatabasePortMockRecorder) Delete(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessTokenDatabasePort)(nil).Delete), userID, id)
+}
+
+// DeleteByUserID mocks base method.
+func (m *MockAccessTokenDatabasePort) DeleteByUserID(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserID", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserID indicates an expected call of DeleteByUserID.
+func (mr *MockAccessTokenDatabasePortMockRecorder) DeleteByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserID", reflect.TypeOf((*MockAccessTokenDatabasePort)(nil).DeleteByUserID), userID)
+}
+
+// FindByHash mocks base method.
+func (m *MockAccessTokenDatabasePort) FindByHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", tokenHash)
+	ret0, _ := ret[0].(*model.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockAccessTokenDatabasePortMockRecorder) FindByHash(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockAccessTokenDatabasePort)(nil).FindByHash), tokenHash)
+}
+
+// FindByUserID mocks base method.
+func (m *MockAccessTokenDatabasePort) FindByUserID(userID string) ([]model.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]model.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockAccessTokenDatabasePortMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockAccessTokenDatabasePort)(nil).FindByUserID), userID)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockAccessTokenDatabasePort) TouchLastUsed(id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockAccessTokenDatabasePortMockRecorder) TouchLastUsed(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockAccessTokenDatabasePort)(nil).TouchLastUsed), id, at)
+}
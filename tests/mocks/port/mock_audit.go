@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: audit.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditDatabasePort is a mock of AuditDatabasePort interface.
+type MockAuditDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditDatabasePortMockRecorder
+}
+
+// MockAuditDatabasePortMockRecorder is the mock recorder for MockAuditDatabasePort.
+type MockAuditDatabasePortMockRecorder struct {
+	mock *MockAuditDatabasePort
+}
+
+// NewMockAuditDatabasePort creates a new mock instance.
+func NewMockAuditDatabasePort(ctrl *gomock.Controller) *MockAuditDatabasePort {
+	mock := &MockAuditDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockAuditDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditDatabasePort) EXPECT() *MockAuditDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditDatabasePort) Create(entry *model.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditDatabasePortMockRecorder) Create(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditDatabasePort)(nil).Create), entry)
+}
+
+// FindByUserID mocks base method.
+func (m *MockAuditDatabasePort) FindByUserID(userID string) ([]model.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]model.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockAuditDatabasePortMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockAuditDatabasePort)(nil).FindByUserID), userID)
+}
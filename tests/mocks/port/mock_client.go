@@ -63,6 +63,21 @@ func (mr *MockClientDatabasePortMockRecorder) FindByFilter(filter, lock interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).FindByFilter), filter, lock)
 }
 
+// FindScopes mocks base method.
+func (m *MockClientDatabasePort) FindScopes(clientID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindScopes", clientID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindScopes indicates an expected call of FindScopes.
+func (mr *MockClientDatabasePortMockRecorder) FindScopes(clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindScopes", reflect.TypeOf((*MockClientDatabasePort)(nil).FindScopes), clientID)
+}
+
 // IsExists mocks base method.
 func (m *MockClientDatabasePort) IsExists(bearerKey string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +93,20 @@ func (mr *MockClientDatabasePortMockRecorder) IsExists(bearerKey interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExists", reflect.TypeOf((*MockClientDatabasePort)(nil).IsExists), bearerKey)
 }
 
+// SetScopes mocks base method.
+func (m *MockClientDatabasePort) SetScopes(clientID int, scopes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScopes", clientID, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetScopes indicates an expected call of SetScopes.
+func (mr *MockClientDatabasePortMockRecorder) SetScopes(clientID, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScopes", reflect.TypeOf((*MockClientDatabasePort)(nil).SetScopes), clientID, scopes)
+}
+
 // Upsert mocks base method.
 func (m *MockClientDatabasePort) Upsert(datas []model.ClientInput) error {
 	m.ctrl.T.Helper()
@@ -152,6 +181,20 @@ func (m *MockClientCachePort) EXPECT() *MockClientCachePortMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockClientCachePort) Delete(bearerKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", bearerKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockClientCachePortMockRecorder) Delete(bearerKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClientCachePort)(nil).Delete), bearerKey)
+}
+
 // Get mocks base method.
 func (m *MockClientCachePort) Get(bearerKey string) (model.Client, error) {
 	m.ctrl.T.Helper()
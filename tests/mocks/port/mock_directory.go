@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: directory.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDirectoryPort is a mock of DirectoryPort interface.
+type MockDirectoryPort struct {
+	ctrl     *gomock.Controller
+	recorder *MockDirectoryPortMockRecorder
+}
+
+// MockDirectoryPortMockRecorder is the mock recorder for MockDirectoryPort.
+type MockDirectoryPortMockRecorder struct {
+	mock *MockDirectoryPort
+}
+
+// NewMockDirectoryPort creates a new mock instance.
+func NewMockDirectoryPort(ctrl *gomock.Controller) *MockDirectoryPort {
+	mock := &MockDirectoryPort{ctrl: ctrl}
+	mock.recorder = &MockDirectoryPortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDirectoryPort) EXPECT() *MockDirectoryPortMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockDirectoryPort) Authenticate(username, password string) (*model.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", username, password)
+	ret0, _ := ret[0].(*model.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockDirectoryPortMockRecorder) Authenticate(username, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockDirectoryPort)(nil).Authenticate), username, password)
+}
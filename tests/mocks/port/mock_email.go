@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: email.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEmailPort is a mock of EmailPort interface.
+type MockEmailPort struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailPortMockRecorder
+}
+
+// MockEmailPortMockRecorder is the mock recorder for MockEmailPort.
+type MockEmailPortMockRecorder struct {
+	mock *MockEmailPort
+}
+
+// NewMockEmailPort creates a new mock instance.
+func NewMockEmailPort(ctrl *gomock.Controller) *MockEmailPort {
+	mock := &MockEmailPort{ctrl: ctrl}
+	mock.recorder = &MockEmailPortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailPort) EXPECT() *MockEmailPortMockRecorder {
+	return m.recorder
+}
+
+// SendEmail mocks base method.
+func (m *MockEmailPort) SendEmail(to, subject, body string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendEmail", to, subject, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendEmail indicates an expected call of SendEmail.
+func (mr *MockEmailPortMockRecorder) SendEmail(to, subject, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockEmailPort)(nil).SendEmail), to, subject, body)
+}
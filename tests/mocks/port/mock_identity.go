@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: identity.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIdentityDatabasePort is a mock of IdentityDatabasePort interface.
+type MockIdentityDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityDatabasePortMockRecorder
+}
+
+// MockIdentityDatabasePortMockRecorder is the mock recorder for MockIdentityDatabasePort.
+type MockIdentityDatabasePortMockRecorder struct {
+	mock *MockIdentityDatabasePort
+}
+
+// NewMockIdentityDatabasePort creates a new mock instance.
+func NewMockIdentityDatabasePort(ctrl *gomock.Controller) *MockIdentityDatabasePort {
+	mock := &MockIdentityDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockIdentityDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityDatabasePort) EXPECT() *MockIdentityDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIdentityDatabasePort) Create(identity *model.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityDatabasePortMockRecorder) Create(identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityDatabasePort)(nil).Create), identity)
+}
+
+// FindByProviderSubject mocks base method.
+func (m *MockIdentityDatabasePort) FindByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProviderSubject", provider, subject)
+	ret0, _ := ret[0].(*model.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProviderSubject indicates an expected call of FindByProviderSubject.
+func (mr *MockIdentityDatabasePortMockRecorder) FindByProviderSubject(provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProviderSubject", reflect.TypeOf((*MockIdentityDatabasePort)(nil).FindByProviderSubject), provider, subject)
+}
+
+// FindByUserID mocks base method.
+func (m *MockIdentityDatabasePort) FindByUserID(userID string) ([]model.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]model.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockIdentityDatabasePortMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockIdentityDatabasePort)(nil).FindByUserID), userID)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: invitation.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInvitationDatabasePort is a mock of InvitationDatabasePort interface.
+type MockInvitationDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationDatabasePortMockRecorder
+}
+
+// MockInvitationDatabasePortMockRecorder is the mock recorder for MockInvitationDatabasePort.
+type MockInvitationDatabasePortMockRecorder struct {
+	mock *MockInvitationDatabasePort
+}
+
+// NewMockInvitationDatabasePort creates a new mock instance.
+func NewMockInvitationDatabasePort(ctrl *gomock.Controller) *MockInvitationDatabasePort {
+	mock := &MockInvitationDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockInvitationDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationDatabasePort) EXPECT() *MockInvitationDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockInvitationDatabasePort) Consume(id, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", id, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockInvitationDatabasePortMockRecorder) Consume(id, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockInvitationDatabasePort)(nil).Consume), id, tokenHash)
+}
+
+// Create mocks base method.
+func (m *MockInvitationDatabasePort) Create(invitation *model.Invitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", invitation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitationDatabasePortMockRecorder) Create(invitation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitationDatabasePort)(nil).Create), invitation)
+}
+
+// FindAll mocks base method.
+func (m *MockInvitationDatabasePort) FindAll() ([]model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockInvitationDatabasePortMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockInvitationDatabasePort)(nil).FindAll))
+}
+
+// FindByHash mocks base method.
+func (m *MockInvitationDatabasePort) FindByHash(tokenHash string) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", tokenHash)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockInvitationDatabasePortMockRecorder) FindByHash(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockInvitationDatabasePort)(nil).FindByHash), tokenHash)
+}
+
+// FindByID mocks base method.
+func (m *MockInvitationDatabasePort) FindByID(id string) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockInvitationDatabasePortMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockInvitationDatabasePort)(nil).FindByID), id)
+}
+
+// Renew mocks base method.
+func (m *MockInvitationDatabasePort) Renew(id, tokenHash string, expiresAt, sentAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", id, tokenHash, expiresAt, sentAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockInvitationDatabasePortMockRecorder) Renew(id, tokenHash, expiresAt, sentAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockInvitationDatabasePort)(nil).Renew), id, tokenHash, expiresAt, sentAt)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: login_attempt.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoginAttemptCachePort is a mock of LoginAttemptCachePort interface.
+type MockLoginAttemptCachePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptCachePortMockRecorder
+}
+
+// MockLoginAttemptCachePortMockRecorder is the mock recorder for MockLoginAttemptCachePort.
+type MockLoginAttemptCachePortMockRecorder struct {
+	mock *MockLoginAttemptCachePort
+}
+
+// NewMockLoginAttemptCachePort creates a new mock instance.
+func NewMockLoginAttemptCachePort(ctrl *gomock.Controller) *MockLoginAttemptCachePort {
+	mock := &MockLoginAttemptCachePort{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptCachePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptCachePort) EXPECT() *MockLoginAttemptCachePortMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method.
+func (m *MockLoginAttemptCachePort) Lock(key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginAttemptCachePortMockRecorder) Lock(key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginAttemptCachePort)(nil).Lock), key, ttl)
+}
+
+// Locked mocks base method.
+func (m *MockLoginAttemptCachePort) Locked(prefix string) (map[string]time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Locked", prefix)
+	ret0, _ := ret[0].(map[string]time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Locked indicates an expected call of Locked.
+func (mr *MockLoginAttemptCachePortMockRecorder) Locked(prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Locked", reflect.TypeOf((*MockLoginAttemptCachePort)(nil).Locked), prefix)
+}
+
+// LockedFor mocks base method.
+func (m *MockLoginAttemptCachePort) LockedFor(key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockedFor", key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockedFor indicates an expected call of LockedFor.
+func (mr *MockLoginAttemptCachePortMockRecorder) LockedFor(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedFor", reflect.TypeOf((*MockLoginAttemptCachePort)(nil).LockedFor), key)
+}
+
+// RegisterFailure mocks base method.
+func (m *MockLoginAttemptCachePort) RegisterFailure(key string, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterFailure", key, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterFailure indicates an expected call of RegisterFailure.
+func (mr *MockLoginAttemptCachePortMockRecorder) RegisterFailure(key, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFailure", reflect.TypeOf((*MockLoginAttemptCachePort)(nil).RegisterFailure), key, window)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptCachePort) Reset(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptCachePortMockRecorder) Reset(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptCachePort)(nil).Reset), key)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: nonce.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNonceCachePort is a mock of NonceCachePort interface.
+type MockNonceCachePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockNonceCachePortMockRecorder
+}
+
+// MockNonceCachePortMockRecorder is the mock recorder for MockNonceCachePort.
+type MockNonceCachePortMockRecorder struct {
+	mock *MockNonceCachePort
+}
+
+// NewMockNonceCachePort creates a new mock instance.
+func NewMockNonceCachePort(ctrl *gomock.Controller) *MockNonceCachePort {
+	mock := &MockNonceCachePort{ctrl: ctrl}
+	mock.recorder = &MockNonceCachePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNonceCachePort) EXPECT() *MockNonceCachePortMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockNonceCachePort) Claim(nonce string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", nonce, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockNonceCachePortMockRecorder) Claim(nonce, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockNonceCachePort)(nil).Claim), nonce, ttl)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: registration_policy.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRegistrationPolicyDatabasePort is a mock of RegistrationPolicyDatabasePort interface.
+type MockRegistrationPolicyDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistrationPolicyDatabasePortMockRecorder
+}
+
+// MockRegistrationPolicyDatabasePortMockRecorder is the mock recorder for MockRegistrationPolicyDatabasePort.
+type MockRegistrationPolicyDatabasePortMockRecorder struct {
+	mock *MockRegistrationPolicyDatabasePort
+}
+
+// NewMockRegistrationPolicyDatabasePort creates a new mock instance.
+func NewMockRegistrationPolicyDatabasePort(ctrl *gomock.Controller) *MockRegistrationPolicyDatabasePort {
+	mock := &MockRegistrationPolicyDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockRegistrationPolicyDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistrationPolicyDatabasePort) EXPECT() *MockRegistrationPolicyDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockRegistrationPolicyDatabasePort) Get() (*model.RegistrationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get")
+	ret0, _ := ret[0].(*model.RegistrationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRegistrationPolicyDatabasePortMockRecorder) Get() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRegistrationPolicyDatabasePort)(nil).Get))
+}
+
+// Save mocks base method.
+func (m *MockRegistrationPolicyDatabasePort) Save(policy *model.RegistrationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRegistrationPolicyDatabasePortMockRecorder) Save(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRegistrationPolicyDatabasePort)(nil).Save), policy)
+}
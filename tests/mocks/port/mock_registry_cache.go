@@ -47,3 +47,45 @@ func (mr *MockCachePortMockRecorder) Client() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockCachePort)(nil).Client))
 }
+
+// LoginAttempt mocks base method.
+func (m *MockCachePort) LoginAttempt() outbound_port.LoginAttemptCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginAttempt")
+	ret0, _ := ret[0].(outbound_port.LoginAttemptCachePort)
+	return ret0
+}
+
+// LoginAttempt indicates an expected call of LoginAttempt.
+func (mr *MockCachePortMockRecorder) LoginAttempt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginAttempt", reflect.TypeOf((*MockCachePort)(nil).LoginAttempt))
+}
+
+// Nonce mocks base method.
+func (m *MockCachePort) Nonce() outbound_port.NonceCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nonce")
+	ret0, _ := ret[0].(outbound_port.NonceCachePort)
+	return ret0
+}
+
+// Nonce indicates an expected call of Nonce.
+func (mr *MockCachePortMockRecorder) Nonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nonce", reflect.TypeOf((*MockCachePort)(nil).Nonce))
+}
+
+// Revocation mocks base method.
+func (m *MockCachePort) Revocation() outbound_port.RevocationCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revocation")
+	ret0, _ := ret[0].(outbound_port.RevocationCachePort)
+	return ret0
+}
+
+// Revocation indicates an expected call of Revocation.
+func (mr *MockCachePortMockRecorder) Revocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revocation", reflect.TypeOf((*MockCachePort)(nil).Revocation))
+}
@@ -35,6 +35,34 @@ func (m *MockDatabasePort) EXPECT() *MockDatabasePortMockRecorder {
 	return m.recorder
 }
 
+// AccessToken mocks base method.
+func (m *MockDatabasePort) AccessToken() outbound_port.AccessTokenDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessToken")
+	ret0, _ := ret[0].(outbound_port.AccessTokenDatabasePort)
+	return ret0
+}
+
+// AccessToken indicates an expected call of AccessToken.
+func (mr *MockDatabasePortMockRecorder) AccessToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessToken", reflect.TypeOf((*MockDatabasePort)(nil).AccessToken))
+}
+
+// Audit mocks base method.
+func (m *MockDatabasePort) Audit() outbound_port.AuditDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Audit")
+	ret0, _ := ret[0].(outbound_port.AuditDatabasePort)
+	return ret0
+}
+
+// Audit indicates an expected call of Audit.
+func (mr *MockDatabasePortMockRecorder) Audit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Audit", reflect.TypeOf((*MockDatabasePort)(nil).Audit))
+}
+
 // Client mocks base method.
 func (m *MockDatabasePort) Client() outbound_port.ClientDatabasePort {
 	m.ctrl.T.Helper()
@@ -64,6 +92,104 @@ func (mr *MockDatabasePortMockRecorder) DoInTransaction(txFunc interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoInTransaction", reflect.TypeOf((*MockDatabasePort)(nil).DoInTransaction), txFunc)
 }
 
+// Identity mocks base method.
+func (m *MockDatabasePort) Identity() outbound_port.IdentityDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identity")
+	ret0, _ := ret[0].(outbound_port.IdentityDatabasePort)
+	return ret0
+}
+
+// Identity indicates an expected call of Identity.
+func (mr *MockDatabasePortMockRecorder) Identity() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identity", reflect.TypeOf((*MockDatabasePort)(nil).Identity))
+}
+
+// Invitation mocks base method.
+func (m *MockDatabasePort) Invitation() outbound_port.InvitationDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invitation")
+	ret0, _ := ret[0].(outbound_port.InvitationDatabasePort)
+	return ret0
+}
+
+// Invitation indicates an expected call of Invitation.
+func (mr *MockDatabasePortMockRecorder) Invitation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invitation", reflect.TypeOf((*MockDatabasePort)(nil).Invitation))
+}
+
+// RegistrationPolicy mocks base method.
+func (m *MockDatabasePort) RegistrationPolicy() outbound_port.RegistrationPolicyDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistrationPolicy")
+	ret0, _ := ret[0].(outbound_port.RegistrationPolicyDatabasePort)
+	return ret0
+}
+
+// RegistrationPolicy indicates an expected call of RegistrationPolicy.
+func (mr *MockDatabasePortMockRecorder) RegistrationPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistrationPolicy", reflect.TypeOf((*MockDatabasePort)(nil).RegistrationPolicy))
+}
+
+// Role mocks base method.
+func (m *MockDatabasePort) Role() outbound_port.RoleDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Role")
+	ret0, _ := ret[0].(outbound_port.RoleDatabasePort)
+	return ret0
+}
+
+// Role indicates an expected call of Role.
+func (mr *MockDatabasePortMockRecorder) Role() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Role", reflect.TypeOf((*MockDatabasePort)(nil).Role))
+}
+
+// Token mocks base method.
+func (m *MockDatabasePort) Token() outbound_port.TokenDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(outbound_port.TokenDatabasePort)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockDatabasePortMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockDatabasePort)(nil).Token))
+}
+
+// TwoFactor mocks base method.
+func (m *MockDatabasePort) TwoFactor() outbound_port.TwoFactorDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TwoFactor")
+	ret0, _ := ret[0].(outbound_port.TwoFactorDatabasePort)
+	return ret0
+}
+
+// TwoFactor indicates an expected call of TwoFactor.
+func (mr *MockDatabasePortMockRecorder) TwoFactor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TwoFactor", reflect.TypeOf((*MockDatabasePort)(nil).TwoFactor))
+}
+
+// User mocks base method.
+func (m *MockDatabasePort) User() outbound_port.UserDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "User")
+	ret0, _ := ret[0].(outbound_port.UserDatabasePort)
+	return ret0
+}
+
+// User indicates an expected call of User.
+func (mr *MockDatabasePortMockRecorder) User() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "User", reflect.TypeOf((*MockDatabasePort)(nil).User))
+}
+
 // MockDatabaseExecutor is a mock of DatabaseExecutor interface.
 type MockDatabaseExecutor struct {
 	ctrl     *gomock.Controller
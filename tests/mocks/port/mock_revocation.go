@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revocation.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevocationCachePort is a mock of RevocationCachePort interface.
+type MockRevocationCachePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationCachePortMockRecorder
+}
+
+// MockRevocationCachePortMockRecorder is the mock recorder for MockRevocationCachePort.
+type MockRevocationCachePortMockRecorder struct {
+	mock *MockRevocationCachePort
+}
+
+// NewMockRevocationCachePort creates a new mock instance.
+func NewMockRevocationCachePort(ctrl *gomock.Controller) *MockRevocationCachePort {
+	mock := &MockRevocationCachePort{ctrl: ctrl}
+	mock.recorder = &MockRevocationCachePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationCachePort) EXPECT() *MockRevocationCachePortMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevocationCachePort) IsRevoked(tokenID, sessionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", tokenID, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevocationCachePortMockRecorder) IsRevoked(tokenID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevocationCachePort)(nil).IsRevoked), tokenID, sessionID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockRevocationCachePort) RevokeSession(sessionID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", sessionID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockRevocationCachePortMockRecorder) RevokeSession(sessionID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockRevocationCachePort)(nil).RevokeSession), sessionID, ttl)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRevocationCachePort) RevokeToken(tokenID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", tokenID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRevocationCachePortMockRecorder) RevokeToken(tokenID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRevocationCachePort)(nil).RevokeToken), tokenID, ttl)
+}
@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: role.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoleDatabasePort is a mock of RoleDatabasePort interface.
+type MockRoleDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleDatabasePortMockRecorder
+}
+
+// MockRoleDatabasePortMockRecorder is the mock recorder for MockRoleDatabasePort.
+type MockRoleDatabasePortMockRecorder struct {
+	mock *MockRoleDatabasePort
+}
+
+// NewMockRoleDatabasePort creates a new mock instance.
+func NewMockRoleDatabasePort(ctrl *gomock.Controller) *MockRoleDatabasePort {
+	mock := &MockRoleDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockRoleDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleDatabasePort) EXPECT() *MockRoleDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// CountUsers mocks base method.
+func (m *MockRoleDatabasePort) CountUsers(role string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", role)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockRoleDatabasePortMockRecorder) CountUsers(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockRoleDatabasePort)(nil).CountUsers), role)
+}
+
+// Create mocks base method.
+func (m *MockRoleDatabasePort) Create(role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleDatabasePortMockRecorder) Create(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleDatabasePort)(nil).Create), role)
+}
+
+// Delete mocks base method.
+func (m *MockRoleDatabasePort) Delete(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoleDatabasePortMockRecorder) Delete(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoleDatabasePort)(nil).Delete), name)
+}
+
+// FindAll mocks base method.
+func (m *MockRoleDatabasePort) FindAll() ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockRoleDatabasePortMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRoleDatabasePort)(nil).FindAll))
+}
+
+// FindByName mocks base method.
+func (m *MockRoleDatabasePort) FindByName(name string) (*model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", name)
+	ret0, _ := ret[0].(*model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockRoleDatabasePortMockRecorder) FindByName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockRoleDatabasePort)(nil).FindByName), name)
+}
+
+// FindPermissions mocks base method.
+func (m *MockRoleDatabasePort) FindPermissions() ([]model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPermissions")
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPermissions indicates an expected call of FindPermissions.
+func (mr *MockRoleDatabasePortMockRecorder) FindPermissions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPermissions", reflect.TypeOf((*MockRoleDatabasePort)(nil).FindPermissions))
+}
+
+// HasPermission mocks base method.
+func (m *MockRoleDatabasePort) HasPermission(role, permission string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPermission", role, permission)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPermission indicates an expected call of HasPermission.
+func (mr *MockRoleDatabasePortMockRecorder) HasPermission(role, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPermission", reflect.TypeOf((*MockRoleDatabasePort)(nil).HasPermission), role, permission)
+}
+
+// SetPermissions mocks base method.
+func (m *MockRoleDatabasePort) SetPermissions(role string, permissions []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPermissions", role, permissions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPermissions indicates an expected call of SetPermissions.
+func (mr *MockRoleDatabasePortMockRecorder) SetPermissions(role, permissions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPermissions", reflect.TypeOf((*MockRoleDatabasePort)(nil).SetPermissions), role, permissions)
+}
+
+// Update mocks base method.
+func (m *MockRoleDatabasePort) Update(role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoleDatabasePortMockRecorder) Update(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoleDatabasePort)(nil).Update), role)
+}
@@ -0,0 +1,196 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTokenDatabasePort is a mock of TokenDatabasePort interface.
+type MockTokenDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenDatabasePortMockRecorder
+}
+
+// MockTokenDatabasePortMockRecorder is the mock recorder for MockTokenDatabasePort.
+type MockTokenDatabasePortMockRecorder struct {
+	mock *MockTokenDatabasePort
+}
+
+// NewMockTokenDatabasePort creates a new mock instance.
+func NewMockTokenDatabasePort(ctrl *gomock.Controller) *MockTokenDatabasePort {
+	mock := &MockTokenDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockTokenDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenDatabasePort) EXPECT() *MockTokenDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Blacklist mocks base method.
+func (m *MockTokenDatabasePort) Blacklist(tokenID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Blacklist", tokenID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Blacklist indicates an expected call of Blacklist.
+func (mr *MockTokenDatabasePortMockRecorder) Blacklist(tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Blacklist", reflect.TypeOf((*MockTokenDatabasePort)(nil).Blacklist), tokenID)
+}
+
+// BlacklistByFamily mocks base method.
+func (m *MockTokenDatabasePort) BlacklistByFamily(family string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlacklistByFamily", family)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlacklistByFamily indicates an expected call of BlacklistByFamily.
+func (mr *MockTokenDatabasePortMockRecorder) BlacklistByFamily(family interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlacklistByFamily", reflect.TypeOf((*MockTokenDatabasePort)(nil).BlacklistByFamily), family)
+}
+
+// Create mocks base method.
+func (m *MockTokenDatabasePort) Create(token *model.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTokenDatabasePortMockRecorder) Create(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTokenDatabasePort)(nil).Create), token)
+}
+
+// Delete mocks base method.
+func (m *MockTokenDatabasePort) Delete(tokenID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTokenDatabasePortMockRecorder) Delete(tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTokenDatabasePort)(nil).Delete), tokenID)
+}
+
+// DeleteByFamily mocks base method.
+func (m *MockTokenDatabasePort) DeleteByFamily(family string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByFamily", family)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByFamily indicates an expected call of DeleteByFamily.
+func (mr *MockTokenDatabasePortMockRecorder) DeleteByFamily(family interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFamily", reflect.TypeOf((*MockTokenDatabasePort)(nil).DeleteByFamily), family)
+}
+
+// DeleteByUserIDAndType mocks base method.
+func (m *MockTokenDatabasePort) DeleteByUserIDAndType(userID, tokenType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserIDAndType", userID, tokenType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserIDAndType indicates an expected call of DeleteByUserIDAndType.
+func (mr *MockTokenDatabasePortMockRecorder) DeleteByUserIDAndType(userID, tokenType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserIDAndType", reflect.TypeOf((*MockTokenDatabasePort)(nil).DeleteByUserIDAndType), userID, tokenType)
+}
+
+// FindByToken mocks base method.
+func (m *MockTokenDatabasePort) FindByToken(tokenStr, tokenType string) (*model.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByToken", tokenStr, tokenType)
+	ret0, _ := ret[0].(*model.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByToken indicates an expected call of FindByToken.
+func (mr *MockTokenDatabasePortMockRecorder) FindByToken(tokenStr, tokenType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByToken", reflect.TypeOf((*MockTokenDatabasePort)(nil).FindByToken), tokenStr, tokenType)
+}
+
+// FindByTokenIncludingBlacklisted mocks base method.
+func (m *MockTokenDatabasePort) FindByTokenIncludingBlacklisted(tokenStr, tokenType string) (*model.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTokenIncludingBlacklisted", tokenStr, tokenType)
+	ret0, _ := ret[0].(*model.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTokenIncludingBlacklisted indicates an expected call of FindByTokenIncludingBlacklisted.
+func (mr *MockTokenDatabasePortMockRecorder) FindByTokenIncludingBlacklisted(tokenStr, tokenType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTokenIncludingBlacklisted", reflect.TypeOf((*MockTokenDatabasePort)(nil).FindByTokenIncludingBlacklisted), tokenStr, tokenType)
+}
+
+// FindSessionByFamily mocks base method.
+func (m *MockTokenDatabasePort) FindSessionByFamily(family string) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionByFamily", family)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionByFamily indicates an expected call of FindSessionByFamily.
+func (mr *MockTokenDatabasePortMockRecorder) FindSessionByFamily(family interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionByFamily", reflect.TypeOf((*MockTokenDatabasePort)(nil).FindSessionByFamily), family)
+}
+
+// FindSessionsByUserID mocks base method.
+func (m *MockTokenDatabasePort) FindSessionsByUserID(userID string) ([]model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessionsByUserID", userID)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessionsByUserID indicates an expected call of FindSessionsByUserID.
+func (mr *MockTokenDatabasePortMockRecorder) FindSessionsByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessionsByUserID", reflect.TypeOf((*MockTokenDatabasePort)(nil).FindSessionsByUserID), userID)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockTokenDatabasePort) PurgeExpired(now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockTokenDatabasePortMockRecorder) PurgeExpired(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockTokenDatabasePort)(nil).PurgeExpired), now, limit)
+}
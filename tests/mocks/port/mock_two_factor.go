@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: two_factor.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTwoFactorDatabasePort is a mock of TwoFactorDatabasePort interface.
+type MockTwoFactorDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorDatabasePortMockRecorder
+}
+
+// MockTwoFactorDatabasePortMockRecorder is the mock recorder for MockTwoFactorDatabasePort.
+type MockTwoFactorDatabasePortMockRecorder struct {
+	mock *MockTwoFactorDatabasePort
+}
+
+// NewMockTwoFactorDatabasePort creates a new mock instance.
+func NewMockTwoFactorDatabasePort(ctrl *gomock.Controller) *MockTwoFactorDatabasePort {
+	mock := &MockTwoFactorDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorDatabasePort) EXPECT() *MockTwoFactorDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// DeleteByUserID mocks base method.
+func (m *MockTwoFactorDatabasePort) DeleteByUserID(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserID", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserID indicates an expected call of DeleteByUserID.
+func (mr *MockTwoFactorDatabasePortMockRecorder) DeleteByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserID", reflect.TypeOf((*MockTwoFactorDatabasePort)(nil).DeleteByUserID), userID)
+}
+
+// FindByUserID mocks base method.
+func (m *MockTwoFactorDatabasePort) FindByUserID(userID string) (*model.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].(*model.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockTwoFactorDatabasePortMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockTwoFactorDatabasePort)(nil).FindByUserID), userID)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockTwoFactorDatabasePort) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockTwoFactorDatabasePortMockRecorder) ReplaceRecoveryCodes(userID, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockTwoFactorDatabasePort)(nil).ReplaceRecoveryCodes), userID, codeHashes)
+}
+
+// Upsert mocks base method.
+func (m *MockTwoFactorDatabasePort) Upsert(twoFactor *model.TwoFactor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", twoFactor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockTwoFactorDatabasePortMockRecorder) Upsert(twoFactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockTwoFactorDatabasePort)(nil).Upsert), twoFactor)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactorDatabasePort) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userID, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorDatabasePortMockRecorder) UseRecoveryCode(userID, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactorDatabasePort)(nil).UseRecoveryCode), userID, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MockTwoFactorDatabasePort) UseStep(userID string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MockTwoFactorDatabasePortMockRecorder) UseStep(userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MockTwoFactorDatabasePort)(nil).UseStep), userID, step)
+}
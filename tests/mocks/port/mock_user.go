@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: user.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	model "prabogo/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserDatabasePort is a mock of UserDatabasePort interface.
+type MockUserDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserDatabasePortMockRecorder
+}
+
+// MockUserDatabasePortMockRecorder is the mock recorder for MockUserDatabasePort.
+type MockUserDatabasePortMockRecorder struct {
+	mock *MockUserDatabasePort
+}
+
+// NewMockUserDatabasePort creates a new mock instance.
+func NewMockUserDatabasePort(ctrl *gomock.Controller) *MockUserDatabasePort {
+	mock := &MockUserDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockUserDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserDatabasePort) EXPECT() *MockUserDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserDatabasePort) Create(user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserDatabasePortMockRecorder) Create(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserDatabasePort)(nil).Create), user)
+}
+
+// Delete mocks base method.
+func (m *MockUserDatabasePort) Delete(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserDatabasePortMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserDatabasePort)(nil).Delete), id)
+}
+
+// ExistsByEmail mocks base method.
+func (m *MockUserDatabasePort) ExistsByEmail(email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByEmail", email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByEmail indicates an expected call of ExistsByEmail.
+func (mr *MockUserDatabasePortMockRecorder) ExistsByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByEmail", reflect.TypeOf((*MockUserDatabasePort)(nil).ExistsByEmail), email)
+}
+
+// FindAll mocks base method.
+func (m *MockUserDatabasePort) FindAll(filters model.UserFilter, page, limit int, sort string) ([]model.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", filters, page, limit, sort)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockUserDatabasePortMockRecorder) FindAll(filters, page, limit, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserDatabasePort)(nil).FindAll), filters, page, limit, sort)
+}
+
+// FindByEmail mocks base method.
+func (m *MockUserDatabasePort) FindByEmail(email string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", email)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *MockUserDatabasePortMockRecorder) FindByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserDatabasePort)(nil).FindByEmail), email)
+}
+
+// FindByID mocks base method.
+func (m *MockUserDatabasePort) FindByID(id string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockUserDatabasePortMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUserDatabasePort)(nil).FindByID), id)
+}
+
+// FindDeletionDue mocks base method.
+func (m *MockUserDatabasePort) FindDeletionDue(now time.Time, limit int) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletionDue", now, limit)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletionDue indicates an expected call of FindDeletionDue.
+func (mr *MockUserDatabasePortMockRecorder) FindDeletionDue(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletionDue", reflect.TypeOf((*MockUserDatabasePort)(nil).FindDeletionDue), now, limit)
+}
+
+// Update mocks base method.
+func (m *MockUserDatabasePort) Update(user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserDatabasePortMockRecorder) Update(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserDatabasePort)(nil).Update), user)
+}
@@ -14,6 +14,11 @@ const (
 	ClientID
 	Payload
 	Result
+	IPAddress
+	UserAgent
+	UserID
+	ActorID
+	TokenScopes
 )
 
 func NewContext(action string) context.Context {
@@ -61,6 +66,53 @@ func GetResult(ctx context.Context) interface{} {
 	return ctx.Value(Result)
 }
 
+func WithIPAddress(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, IPAddress, ip)
+}
+
+func GetIPAddress(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(IPAddress).(string)
+	return ip, ok
+}
+
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, UserAgent, userAgent)
+}
+
+func GetUserAgent(ctx context.Context) (string, bool) {
+	userAgent, ok := ctx.Value(UserAgent).(string)
+	return userAgent, ok
+}
+
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, UserID, userID)
+}
+
+func GetUserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(UserID).(string)
+	return userID, ok
+}
+
+// WithActorID marks the request as made by actorID on behalf of the user
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, ActorID, actorID)
+}
+
+func GetActorID(ctx context.Context) (string, bool) {
+	actorID, ok := ctx.Value(ActorID).(string)
+	return actorID, ok
+}
+
+// WithTokenScopes marks the request as made with a personal access token limited to scopes
+func WithTokenScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, TokenScopes, scopes)
+}
+
+func GetTokenScopes(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(TokenScopes).([]string)
+	return scopes, ok
+}
+
 func GetFields(ctx context.Context) map[string]interface{} {
 	fields := make(map[string]interface{})
 
@@ -76,6 +128,19 @@ func GetFields(ctx context.Context) map[string]interface{} {
 		fields["client_id"] = clientID
 	}
 
+	if ip, ok := GetIPAddress(ctx); ok {
+		fields["ip_address"] = ip
+	}
+
+	if userID, ok := GetUserID(ctx); ok {
+		fields["user_id"] = userID
+	}
+
+	if actorID, ok := GetActorID(ctx); ok {
+		fields["actor_id"] = actorID
+		fields["impersonated"] = true
+	}
+
 	fields["payload"] = GetPayload(ctx)
 	fields["result"] = GetResult(ctx)
 
@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one asymmetric key used to sign local tokens
+type SigningKey struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+}
+
+// SigningKeySet holds every configured key; the first one signs, all of them verify
+type SigningKeySet struct {
+	Active *SigningKey
+	Keys   []*SigningKey
+	byKid  map[string]*SigningKey
+}
+
+var (
+	signingKeysMu     sync.Mutex
+	signingKeysConfig string
+	signingKeysCache  *SigningKeySet
+)
+
+// LoadSigningKeys reads JWT_SIGNING_KEYS, a comma-separated list of PEM private key files
+// written as "path" or "kid=path". Returns nil when unset, meaning HS256 with JWT_SECRET.
+func LoadSigningKeys() (*SigningKeySet, error) {
+	config := strings.TrimSpace(os.Getenv("JWT_SIGNING_KEYS"))
+	if config == "" {
+		return nil, nil
+	}
+
+	signingKeysMu.Lock()
+	defer signingKeysMu.Unlock()
+
+	if signingKeysCache != nil && signingKeysConfig == config {
+		return signingKeysCache, nil
+	}
+
+	set := &SigningKeySet{byKid: map[string]*SigningKey{}}
+	for _, entry := range strings.Split(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path := "", entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			kid, path = entry[:i], entry[i+1:]
+		}
+
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signing key %s: %w", path, err)
+		}
+		if kid == "" {
+			kid = key.thumbprint()
+		}
+		key.Kid = kid
+
+		if _, ok := set.byKid[kid]; ok {
+			return nil, fmt.Errorf("duplicate signing key id %s", kid)
+		}
+		set.byKid[kid] = key
+		set.Keys = append(set.Keys, key)
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("no signing keys configured")
+	}
+	set.Active = set.Keys[0]
+
+	signingKeysConfig = config
+	signingKeysCache = set
+	return set, nil
+}
+
+// Key returns the key with the given kid
+func (s *SigningKeySet) Key(kid string) (*SigningKey, bool) {
+	key, ok := s.byKid[kid]
+	return key, ok
+}
+
+// JWKSet publishes the public half of every key
+func (s *SigningKeySet) JWKSet() *JWKSet {
+	set := &JWKSet{Keys: []JWK{}}
+	for _, key := range s.Keys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	return set
+}
+
+// Algorithms lists the signing algorithms in use
+func (s *SigningKeySet) Algorithms() []string {
+	algs := []string{}
+	seen := map[string]bool{}
+	for _, key := range s.Keys {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+func loadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{Method: jwt.SigningMethodRS256, Private: key}, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve.Params().Name {
+		case "P-256":
+			return &SigningKey{Method: jwt.SigningMethodES256, Private: key}, nil
+		case "P-384":
+			return &SigningKey{Method: jwt.SigningMethodES384, Private: key}, nil
+		case "P-521":
+			return &SigningKey{Method: jwt.SigningMethodES512, Private: key}, nil
+		}
+		return nil, fmt.Errorf("unsupported curve %s", key.Curve.Params().Name)
+	case ed25519.PrivateKey:
+		return &SigningKey{Method: jwt.SigningMethodEdDSA, Private: key}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", parsed)
+}
+
+// Public returns the verification key
+func (k *SigningKey) Public() crypto.PublicKey {
+	return k.Private.Public()
+}
+
+// JWK renders the public key as a JSON Web Key
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Kid: k.Kid, Use: "sig", Alg: k.Method.Alg()}
+
+	switch pub := k.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// thumbprint is the RFC 7638 key thumbprint, used as kid when none is given
+func (k *SigningKey) thumbprint() string {
+	jwk := k.JWK()
+
+	var members map[string]string
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	case "EC":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X, "y": jwk.Y}
+	default:
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	}
+
+	// encoding/json sorts map keys, which is the canonical form the RFC asks for
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
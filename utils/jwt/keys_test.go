@@ -0,0 +1,139 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/jwt"
+)
+
+func writePEM(t *testing.T, name string, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSigningKeys(t *testing.T) {
+	Convey("Asymmetric signing keys", t, func() {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+		rsaPath := writePEM(t, "rsa.pem", rsaKey)
+		ecPath := writePEM(t, "ec.pem", ecKey)
+		edPath := writePEM(t, "ed.pem", edKey)
+
+		os.Setenv("JWT_SECRET", "test-secret")
+		defer os.Unsetenv("JWT_SECRET")
+		defer os.Unsetenv("JWT_SIGNING_KEYS")
+
+		userID := uuid.New().String()
+
+		Convey("Unset keeps HS256", func() {
+			os.Unsetenv("JWT_SIGNING_KEYS")
+
+			keys, err := jwt.LoadSigningKeys()
+			So(err, ShouldBeNil)
+			So(keys, ShouldBeNil)
+
+			token, _, err := jwt.GenerateToken(userID, time.Minute, "access", "test-secret")
+			So(err, ShouldBeNil)
+			payload, err := jwt.ValidateLocalToken(token)
+			So(err, ShouldBeNil)
+			So(payload.Sub, ShouldEqual, userID)
+		})
+
+		Convey("Each key type signs and verifies with its algorithm", func() {
+			for path, alg := range map[string]string{rsaPath: "RS256", ecPath: "ES256", edPath: "EdDSA"} {
+				os.Setenv("JWT_SIGNING_KEYS", "k1="+path)
+
+				keys, err := jwt.LoadSigningKeys()
+				So(err, ShouldBeNil)
+				So(keys.Active.Method.Alg(), ShouldEqual, alg)
+
+				token, _, err := jwt.GenerateToken(userID, time.Minute, "access", "test-secret")
+				So(err, ShouldBeNil)
+
+				payload, err := jwt.ValidateLocalToken(token)
+				So(err, ShouldBeNil)
+				So(payload.Sub, ShouldEqual, userID)
+			}
+		})
+
+		Convey("Missing kid falls back to the key thumbprint", func() {
+			os.Setenv("JWT_SIGNING_KEYS", edPath)
+
+			keys, err := jwt.LoadSigningKeys()
+			So(err, ShouldBeNil)
+			So(keys.Active.Kid, ShouldNotBeEmpty)
+			So(keys.Active.Kid, ShouldNotContainSubstring, "=")
+		})
+
+		Convey("Unreadable key file", func() {
+			os.Setenv("JWT_SIGNING_KEYS", "k1="+filepath.Join(t.TempDir(), "missing.pem"))
+
+			_, _, err := jwt.GenerateToken(userID, time.Minute, "access", "test-secret")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rotation keeps tokens from the previous key valid", func() {
+			os.Setenv("JWT_SIGNING_KEYS", "old="+rsaPath)
+			oldToken, _, err := jwt.GenerateToken(userID, time.Minute, "access", "test-secret")
+			So(err, ShouldBeNil)
+
+			os.Setenv("JWT_SIGNING_KEYS", "new="+ecPath+",old="+rsaPath)
+			_, err = jwt.ValidateLocalToken(oldToken)
+			So(err, ShouldBeNil)
+
+			// Once retired, the old key no longer verifies
+			os.Setenv("JWT_SIGNING_KEYS", "new="+ecPath)
+			_, err = jwt.ValidateLocalToken(oldToken)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Published key set works with JWKSClient", func() {
+			os.Setenv("JWT_SIGNING_KEYS", "new="+edPath+",old="+rsaPath)
+			keys, err := jwt.LoadSigningKeys()
+			So(err, ShouldBeNil)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(keys.JWKSet())
+			}))
+			defer server.Close()
+			defer jwt.GetJWKSClient(server.URL).Close()
+
+			token, _, err := jwt.GenerateToken(userID, time.Minute, "access", "test-secret")
+			So(err, ShouldBeNil)
+
+			claims, err := jwt.GetJWTClaimsWithURL(token, server.URL)
+			So(err, ShouldBeNil)
+			So(claims["sub"], ShouldEqual, userID)
+
+			// Private material never leaves the server
+			data, _ := json.Marshal(keys.JWKSet())
+			So(strings.Contains(string(data), `"d"`), ShouldBeFalse)
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -12,11 +13,26 @@ import (
 type TokenPayload struct {
 	Sub  string `json:"sub"` // User ID
 	Type string `json:"type"`
+	Sid  string `json:"sid,omitempty"` // Session (refresh token family) ID
+	Act  *Actor `json:"act,omitempty"` // Set while an admin impersonates Sub
+	// Set on client_credentials tokens only, where Sub is the client ID as well
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"` // Space separated (RFC 6749 section 3.3)
 	jwt.RegisteredClaims
 }
 
+// Actor is the RFC 8693 "act" claim: the user really making an impersonated request
+type Actor struct {
+	Sub string `json:"sub"`
+}
+
 // GenerateToken creates a signed JWT token
 func GenerateToken(userID string, expires time.Duration, tokenType string, secret string) (string, time.Time, error) {
+	return GenerateSessionToken(userID, "", expires, tokenType, secret)
+}
+
+// GenerateSessionToken creates a signed JWT token bound to a session
+func GenerateSessionToken(userID string, sessionID string, expires time.Duration, tokenType string, secret string) (string, time.Time, error) {
 	expirationTime := time.Now().Add(expires)
 
 	// Verify UserID is valid UUID
@@ -28,14 +44,16 @@ func GenerateToken(userID string, expires time.Duration, tokenType string, secre
 	claims := &TokenPayload{
 		Sub:  uid.String(),
 		Type: tokenType,
+		Sid:  sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // jti, used to revoke a single token
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    os.Getenv("JWT_ISSUER"),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(secret))
+	signedToken, err := signClaims(claims, secret)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -43,16 +61,36 @@ func GenerateToken(userID string, expires time.Duration, tokenType string, secre
 	return signedToken, expirationTime, nil
 }
 
+// signClaims uses the active key from JWT_SIGNING_KEYS, or HS256 with the secret when none are configured
+func signClaims(claims jwt.Claims, secret string) (string, error) {
+	keys, err := LoadSigningKeys()
+	if err != nil {
+		return "", err
+	}
+	if keys == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	}
+
+	token := jwt.NewWithClaims(keys.Active.Method, claims)
+	token.Header["kid"] = keys.Active.Kid
+	return token.SignedString(keys.Active.Private)
+}
+
+// AccessTokenLifetime is how long an access token stays valid after it is issued
+func AccessTokenLifetime() time.Duration {
+	accessMinutes, _ := strconv.Atoi(os.Getenv("JWT_ACCESS_EXPIRATION_MINUTES"))
+	if accessMinutes == 0 { accessMinutes = 30 }
+	return time.Duration(accessMinutes) * time.Minute
+}
+
 // Helper to generate Auth (Access + Refresh) tokens pair
-func GenerateAuthTokens(userID string) (string, string, time.Time, time.Time, error) {
+func GenerateAuthTokens(userID string, sessionID string) (string, string, time.Time, time.Time, error) {
 	secret := os.Getenv("JWT_SECRET")
 	
 	// Access Token
-	accessMinutes, _ := strconv.Atoi(os.Getenv("JWT_ACCESS_EXPIRATION_MINUTES"))
-	if accessMinutes == 0 { accessMinutes = 30 }
-	accessTokenExpires := time.Duration(accessMinutes) * time.Minute
-	
-	accessToken, accessExp, err := GenerateToken(userID, accessTokenExpires, "access", secret)
+	accessTokenExpires := AccessTokenLifetime()
+
+	accessToken, accessExp, err := GenerateSessionToken(userID, sessionID, accessTokenExpires, "access", secret)
 	if err != nil {
 		return "", "", time.Time{}, time.Time{}, err
 	}
@@ -62,7 +100,7 @@ func GenerateAuthTokens(userID string) (string, string, time.Time, time.Time, er
 	if refreshDays == 0 { refreshDays = 30 }
 	refreshTokenExpires := time.Duration(refreshDays) * 24 * time.Hour
 	
-	refreshToken, refreshExp, err := GenerateToken(userID, refreshTokenExpires, "refresh", secret)
+	refreshToken, refreshExp, err := GenerateSessionToken(userID, sessionID, refreshTokenExpires, "refresh", secret)
 	if err != nil {
 		return "", "", time.Time{}, time.Time{}, err
 	}
@@ -70,12 +108,37 @@ func GenerateAuthTokens(userID string) (string, string, time.Time, time.Time, er
 	return accessToken, refreshToken, accessExp, refreshExp, nil
 }
 
-// ValidateLocalToken verifies a locally generated HMAC token
+// ValidateLocalToken verifies a locally generated token. Tokens signed with any key in
+// JWT_SIGNING_KEYS are accepted, and HS256 ones too while JWT_SECRET is set.
 func ValidateLocalToken(tokenString string) (*TokenPayload, error) {
 	secret := os.Getenv("JWT_SECRET")
+	keys, err := LoadSigningKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	methods := []string{}
+	if secret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if keys != nil {
+		methods = append(methods, keys.Algorithms()...)
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &TokenPayload{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			return []byte(secret), nil
+		}
+		if keys == nil {
+			return nil, fmt.Errorf("no signing keys configured")
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.Key(kid)
+		if !ok || key.Method.Alg() != token.Method.Alg() {
+			return nil, fmt.Errorf("unknown signing key %s", kid)
+		}
+		return key.Public(), nil
+	}, jwt.WithValidMethods(methods))
 
 	if err != nil {
 		return nil, err
@@ -86,4 +149,106 @@ func ValidateLocalToken(tokenString string) (*TokenPayload, error) {
 	}
 
 	return nil, jwt.ErrTokenInvalidClaims
-}
\ No newline at end of file
+}
+
+// GenerateImpersonationToken creates a short-lived access token for userID acting as actorID.
+// It has no session and no refresh token; it runs out, or ends earlier when the server drops it.
+func GenerateImpersonationToken(userID string, actorID string) (string, time.Time, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	impersonationMinutes, _ := strconv.Atoi(os.Getenv("JWT_IMPERSONATION_EXPIRATION_MINUTES"))
+	if impersonationMinutes == 0 { impersonationMinutes = 15 }
+	expirationTime := time.Now().Add(time.Duration(impersonationMinutes) * time.Minute)
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := &TokenPayload{
+		Sub:  uid.String(),
+		Type: "access",
+		Act:  &Actor{Sub: actorID},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    os.Getenv("JWT_ISSUER"),
+		},
+	}
+
+	signedToken, err := signClaims(claims, secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signedToken, expirationTime, nil
+}
+
+// GenerateClientToken creates an access token for a service client from the client_credentials
+// grant. It is not bound to any user.
+func GenerateClientToken(clientID string, scope string) (string, time.Time, error) {
+	secret := os.Getenv("JWT_SECRET")
+	expirationTime := time.Now().Add(AccessTokenLifetime())
+
+	claims := &TokenPayload{
+		Sub:      clientID,
+		Type:     "access",
+		ClientID: clientID,
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    os.Getenv("JWT_ISSUER"),
+		},
+	}
+
+	signedToken, err := signClaims(claims, secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signedToken, expirationTime, nil
+}
+
+// GenerateVerifyEmailToken creates a signed token for the email verification flow
+func GenerateVerifyEmailToken(userID string) (string, time.Time, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	verifyMinutes, _ := strconv.Atoi(os.Getenv("JWT_VERIFY_EMAIL_EXPIRATION_MINUTES"))
+	if verifyMinutes == 0 { verifyMinutes = 10 }
+
+	return GenerateToken(userID, time.Duration(verifyMinutes)*time.Minute, "verifyEmail", secret)
+}
+
+
+// GenerateResetPasswordToken creates a signed token for the reset password flow
+func GenerateResetPasswordToken(userID string) (string, time.Time, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	resetMinutes, _ := strconv.Atoi(os.Getenv("JWT_RESET_PASSWORD_EXPIRATION_MINUTES"))
+	if resetMinutes == 0 { resetMinutes = 10 }
+
+	return GenerateToken(userID, time.Duration(resetMinutes)*time.Minute, "resetPassword", secret)
+}
+
+// GenerateMfaPendingToken creates a short-lived token proving the password step of a 2FA login
+func GenerateMfaPendingToken(userID string) (string, time.Time, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	mfaMinutes, _ := strconv.Atoi(os.Getenv("JWT_MFA_PENDING_EXPIRATION_MINUTES"))
+	if mfaMinutes == 0 { mfaMinutes = 5 }
+
+	return GenerateToken(userID, time.Duration(mfaMinutes)*time.Minute, "mfaPending", secret)
+}
+
+// GenerateMagicLinkToken creates a single-use token that signs the user in from an email link
+func GenerateMagicLinkToken(userID string) (string, time.Time, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	magicMinutes, _ := strconv.Atoi(os.Getenv("JWT_MAGIC_LINK_EXPIRATION_MINUTES"))
+	if magicMinutes == 0 { magicMinutes = 15 }
+
+	return GenerateToken(userID, time.Duration(magicMinutes)*time.Minute, "magicLink", secret)
+}
@@ -2,17 +2,36 @@ package jwt
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// defaultJWKSCacheTTL applies when the endpoint sends no usable Cache-Control
+	defaultJWKSCacheTTL = 15 * time.Minute
+	// minJWKSRefetchInterval limits refetches triggered by unknown kids
+	minJWKSRefetchInterval = 30 * time.Second
+	// jwksRetryInterval is how soon a failed background refresh is retried
+	jwksRetryInterval = 30 * time.Second
+)
+
+// asymmetricMethods are the algorithms accepted for provider tokens; HMAC is never allowed
+var asymmetricMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
 // JWKSet represents a JSON Web Key Set
 type JWKSet struct {
 	Keys []JWK `json:"keys"`
@@ -22,17 +41,43 @@ type JWKSet struct {
 type JWK struct {
 	Kid string `json:"kid"`
 	Kty string `json:"kty"`
-	Use string `json:"use"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC and OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
 }
 
-// JWKSClient handles fetching and caching JWKS
+// JWKSClient keeps the keys of one JWKS endpoint in memory, keyed by kid.
+// Keys are refreshed in the background before Cache-Control max-age runs out,
+// and stale keys keep being served while the endpoint is unreachable.
 type JWKSClient struct {
 	jwksURL string
 	client  *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	expiresAt time.Time
+	lastFetch time.Time
+
+	// fetchMu lets one caller hit the endpoint while concurrent misses wait for it
+	fetchMu sync.Mutex
+
+	minRefetchInterval time.Duration
+	stop               chan struct{}
+	stopOnce           sync.Once
+	startOnce          sync.Once
 }
 
+var (
+	jwksClientsMu sync.Mutex
+	jwksClients   = map[string]*JWKSClient{}
+)
+
 // NewJWKSClient creates a new JWKS client with custom URL
 func NewJWKSClient(jwksURL string) *JWKSClient {
 	return &JWKSClient{
@@ -40,40 +85,237 @@ func NewJWKSClient(jwksURL string) *JWKSClient {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		keys:               map[string]crypto.PublicKey{},
+		minRefetchInterval: minJWKSRefetchInterval,
+		stop:               make(chan struct{}),
 	}
 }
 
+// GetJWKSClient returns the long-lived client for a JWKS URL, creating and starting it on first use
+func GetJWKSClient(jwksURL string) *JWKSClient {
+	jwksClientsMu.Lock()
+	defer jwksClientsMu.Unlock()
+
+	c, ok := jwksClients[jwksURL]
+	if !ok {
+		c = NewJWKSClient(jwksURL)
+		jwksClients[jwksURL] = c
+	}
+	c.Start()
+	return c
+}
+
 // GetJWKSet fetches the JWKS from the URL
 func (c *JWKSClient) GetJWKSet(ctx context.Context) (*JWKSet, error) {
+	jwkSet, _, err := c.fetch(ctx)
+	return jwkSet, err
+}
+
+func (c *JWKSClient) fetch(ctx context.Context) (*JWKSet, time.Duration, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", c.jwksURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+		return nil, 0, fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+		return nil, 0, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
 	}
 
 	var jwkSet JWKSet
 	if err := json.NewDecoder(resp.Body).Decode(&jwkSet); err != nil {
-		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+		return nil, 0, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	return &jwkSet, cacheTTL(resp.Header.Get("Cache-Control")), nil
+}
+
+// cacheTTL reads max-age from a Cache-Control header, falling back to the default
+func cacheTTL(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		if directive == "no-store" || directive == "no-cache" {
+			return minJWKSRefetchInterval
+		}
+		if strings.HasPrefix(directive, "max-age=") {
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || seconds <= 0 {
+				return minJWKSRefetchInterval
+			}
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultJWKSCacheTTL
+}
+
+// Refresh fetches the key set and replaces the cached keys
+func (c *JWKSClient) Refresh(ctx context.Context) error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	return c.refresh(ctx)
+}
+
+// refresh expects fetchMu to be held
+func (c *JWKSClient) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	c.lastFetch = time.Now()
+	c.mu.Unlock()
+
+	jwkSet, ttl, err := c.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range jwkSet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.GetPublicKey()
+		if err != nil {
+			// One odd key must not take the others down
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.expiresAt = time.Now().Add(ttl)
+	c.mu.Unlock()
+	return nil
+}
+
+// Start launches the background refresh loop once
+func (c *JWKSClient) Start() {
+	c.startOnce.Do(func() {
+		go c.refreshLoop()
+	})
+}
+
+// Close stops the background refresh loop
+func (c *JWKSClient) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+func (c *JWKSClient) refreshLoop() {
+	for {
+		wait := c.nextRefresh()
+		if wait <= 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			err := c.refreshIfDue(ctx)
+			cancel()
+			wait = c.nextRefresh()
+			if err != nil || wait < c.minRefetchInterval {
+				wait = jwksRetryInterval
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// refreshIfDue skips the fetch when a caller refreshed while we waited for the lock
+func (c *JWKSClient) refreshIfDue(ctx context.Context) error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	if c.nextRefresh() > 0 {
+		return nil
 	}
+	return c.refresh(ctx)
+}
 
-	return &jwkSet, nil
+// nextRefresh is the time left before the cache should be renewed, a tenth of
+// the lifetime ahead of expiry so a valid cache never runs dry
+func (c *JWKSClient) nextRefresh() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.expiresAt.IsZero() {
+		return 0
+	}
+	left := time.Until(c.expiresAt)
+	return left - left/10
 }
 
-// GetPublicKey converts JWK to RSA public key
-func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
-	if jwk.Kty != "RSA" {
-		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+// Key returns the public key for kid. An unknown kid triggers a refetch, at most
+// once per minRefetchInterval, so rotated keys are picked up without hammering the IdP.
+func (c *JWKSClient) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := c.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	// Someone else may have fetched while we waited
+	if key, ok := c.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	c.mu.RLock()
+	limited := !c.lastFetch.IsZero() && time.Since(c.lastFetch) < c.minRefetchInterval
+	c.mu.RUnlock()
+	if limited {
+		return nil, fmt.Errorf("key with kid %s not found in JWKS", kid)
 	}
 
+	if err := c.refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	if key, ok := c.cachedKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("key with kid %s not found in JWKS", kid)
+}
+
+func (c *JWKSClient) cachedKey(kid string) (crypto.PublicKey, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// Keyfunc resolves the verification key for a token from its kid header
+func (c *JWKSClient) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, fmt.Errorf("kid not found in token header")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return c.Key(ctx, kid)
+}
+
+// GetPublicKey converts JWK to an RSA, ECDSA or Ed25519 public key
+func (jwk *JWK) GetPublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		return jwk.rsaPublicKey()
+	case "EC":
+		return jwk.ecdsaPublicKey()
+	case "OKP":
+		return jwk.ed25519PublicKey()
+	}
+	return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+}
+
+func (jwk *JWK) rsaPublicKey() (*rsa.PublicKey, error) {
 	// Decode the modulus
 	nBytes, err := base64.RawURLEncoding.DecodeString(jwk.N)
 	if err != nil {
@@ -85,17 +327,13 @@ func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode exponent: %w", err)
 	}
+	if len(eBytes) == 0 || len(eBytes) > 4 {
+		return nil, fmt.Errorf("invalid exponent length")
+	}
 
 	// Convert to big.Int
 	n := new(big.Int).SetBytes(nBytes)
-	var e int
-	if len(eBytes) == 3 {
-		e = int(eBytes[0])<<16 + int(eBytes[1])<<8 + int(eBytes[2])
-	} else if len(eBytes) == 4 {
-		e = int(eBytes[0])<<24 + int(eBytes[1])<<16 + int(eBytes[2])<<8 + int(eBytes[3])
-	} else {
-		return nil, fmt.Errorf("invalid exponent length")
-	}
+	e := int(new(big.Int).SetBytes(eBytes).Int64())
 
 	return &rsa.PublicKey{
 		N: n,
@@ -103,112 +341,78 @@ func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
 	}, nil
 }
 
-// ValidateJWTWithURL validates the JWT token with a specific JWKS URL
-// Returns (isValid bool, error)
-func ValidateJWTWithURL(tokenString, jwksURL string) (bool, error) {
-	// Create JWKS client
-	jwksClient := NewJWKSClient(jwksURL)
-
-	// Parse the token to get the header and validate
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		// Get the kid from the token header
-		kid, ok := token.Header["kid"].(string)
-		if !ok {
-			return nil, fmt.Errorf("kid not found in token header")
-		}
-
-		// Fetch JWKS
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		jwkSet, err := jwksClient.GetJWKSet(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
-		}
-
-		// Find the matching key
-		for _, jwk := range jwkSet.Keys {
-			if jwk.Kid == kid {
-				return jwk.GetPublicKey()
-			}
-		}
-
-		return nil, fmt.Errorf("key with kid %s not found in JWKS", kid)
-	})
+func (jwk *JWK) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
 
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
 	if err != nil {
-		return false, fmt.Errorf("failed to parse/validate token: %w", err)
+		return nil, fmt.Errorf("failed to decode x coordinate: %w", err)
 	}
-
-	if !token.Valid {
-		return false, fmt.Errorf("token is not valid")
+	yBytes, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode y coordinate: %w", err)
 	}
 
-	// Extract claims to check expiration
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return false, fmt.Errorf("failed to parse claims")
+	key := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, fmt.Errorf("point is not on curve %s", jwk.Crv)
 	}
+	return key, nil
+}
 
-	// Check token expiration
-	if exp, ok := claims["exp"].(float64); ok {
-		expTime := time.Unix(int64(exp), 0)
-		if time.Now().After(expTime) {
-			return false, fmt.Errorf("token has expired at %s", expTime.Format(time.RFC3339))
-		}
-	} else {
-		return false, fmt.Errorf("token does not contain expiration claim")
+func (jwk *JWK) ed25519PublicKey() (ed25519.PublicKey, error) {
+	if jwk.Crv != "Ed25519" {
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
 	}
 
-	// Check not before time if present
-	if nbf, ok := claims["nbf"].(float64); ok {
-		nbfTime := time.Unix(int64(nbf), 0)
-		if time.Now().Before(nbfTime) {
-			return false, fmt.Errorf("token is not valid before %s", nbfTime.Format(time.RFC3339))
-		}
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode x coordinate: %w", err)
 	}
+	if len(xBytes) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 key length")
+	}
+	return ed25519.PublicKey(xBytes), nil
+}
 
+// ValidateJWTWithURL validates the JWT token with a specific JWKS URL
+// Returns (isValid bool, error)
+func ValidateJWTWithURL(tokenString, jwksURL string) (bool, error) {
+	if _, err := GetJWTClaimsWithURL(tokenString, jwksURL); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
 // GetJWTClaimsWithURL validates JWT with specific URL and returns the claims map
 func GetJWTClaimsWithURL(tokenString, jwksURL string) (jwt.MapClaims, error) {
-	// First validate the token
-	isValid, err := ValidateJWTWithURL(tokenString, jwksURL)
-	if !isValid && err != nil {
-		return nil, err
-	}
-
-	// Parse token again to get claims (we know it's valid now)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// We already validated, so just get the key again
-		jwksClient := NewJWKSClient(jwksURL)
-		kid := token.Header["kid"].(string)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		jwkSet, jwkErr := jwksClient.GetJWKSet(ctx)
-		if jwkErr != nil {
-			return nil, jwkErr
-		}
-
-		for _, jwk := range jwkSet.Keys {
-			if jwk.Kid == kid {
-				return jwk.GetPublicKey()
-			}
-		}
-
-		return nil, fmt.Errorf("key not found")
-	})
+	jwksClient := GetJWKSClient(jwksURL)
 
+	// exp is mandatory, nbf is checked when present
+	token, err := jwt.Parse(tokenString, jwksClient.Keyfunc,
+		jwt.WithValidMethods(asymmetricMethods),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse/validate token: %w", err)
+	}
+
+	if !token.Valid {
+		return nil, fmt.Errorf("token is not valid")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
@@ -0,0 +1,205 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/jwt"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) jwt.JWK {
+	return jwt.JWK{Kid: kid, Kty: "RSA", Use: "sig", N: b64(key.N.Bytes()), E: b64(big.NewInt(int64(key.E)).Bytes())}
+}
+
+// jwksServer is a stand-in identity provider whose key set can be swapped
+type jwksServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	keys []jwt.JWK
+	down bool
+	hits int32
+}
+
+func newJWKSServer(keys ...jwt.JWK) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.hits, 1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.down {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		json.NewEncoder(w).Encode(jwt.JWKSet{Keys: s.keys})
+	}))
+	return s
+}
+
+func (s *jwksServer) set(fn func(s *jwksServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s)
+}
+
+func sign(method gojwt.SigningMethod, key interface{}, kid string) string {
+	token := gojwt.NewWithClaims(method, gojwt.MapClaims{
+		"sub": "subject",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = kid
+	signed, _ := token.SignedString(key)
+	return signed
+}
+
+func TestJWK(t *testing.T) {
+	Convey("JWK.GetPublicKey", t, func() {
+		Convey("RSA", func() {
+			key, _ := rsa.GenerateKey(rand.Reader, 2048)
+			jwk := rsaJWK("k", &key.PublicKey)
+
+			pub, err := jwk.GetPublicKey()
+			So(err, ShouldBeNil)
+			So(key.PublicKey.Equal(pub), ShouldBeTrue)
+		})
+
+		Convey("EC", func() {
+			key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			jwk := jwt.JWK{Kid: "k", Kty: "EC", Crv: "P-256", X: b64(key.X.Bytes()), Y: b64(key.Y.Bytes())}
+
+			pub, err := jwk.GetPublicKey()
+			So(err, ShouldBeNil)
+			So(key.PublicKey.Equal(pub), ShouldBeTrue)
+		})
+
+		Convey("EC point off the curve", func() {
+			jwk := jwt.JWK{Kid: "k", Kty: "EC", Crv: "P-256", X: b64([]byte{1}), Y: b64([]byte{2})}
+
+			_, err := jwk.GetPublicKey()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("OKP", func() {
+			pubKey, _, _ := ed25519.GenerateKey(rand.Reader)
+			jwk := jwt.JWK{Kid: "k", Kty: "OKP", Crv: "Ed25519", X: b64(pubKey)}
+
+			pub, err := jwk.GetPublicKey()
+			So(err, ShouldBeNil)
+			So(pubKey.Equal(pub), ShouldBeTrue)
+		})
+
+		Convey("Unsupported key type", func() {
+			jwk := jwt.JWK{Kid: "k", Kty: "oct"}
+
+			_, err := jwk.GetPublicKey()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGetJWTClaimsWithURL(t *testing.T) {
+	Convey("GetJWTClaimsWithURL", t, func() {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		server := newJWKSServer(rsaJWK("rsa-1", &rsaKey.PublicKey))
+		defer server.Close()
+		defer jwt.GetJWKSClient(server.URL).Close()
+
+		Convey("Keys are fetched once and reused", func() {
+			for i := 0; i < 5; i++ {
+				claims, err := jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodRS256, rsaKey, "rsa-1"), server.URL)
+				So(err, ShouldBeNil)
+				So(claims["sub"], ShouldEqual, "subject")
+			}
+			So(atomic.LoadInt32(&server.hits), ShouldEqual, 1)
+		})
+
+		Convey("Cached keys outlive an IdP outage", func() {
+			_, err := jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodRS256, rsaKey, "rsa-1"), server.URL)
+			So(err, ShouldBeNil)
+
+			server.set(func(s *jwksServer) { s.down = true })
+			_, err = jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodRS256, rsaKey, "rsa-1"), server.URL)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Unknown kid refetches at most once per interval", func() {
+			_, err := jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodRS256, rsaKey, "rsa-1"), server.URL)
+			So(err, ShouldBeNil)
+
+			rotated, _ := rsa.GenerateKey(rand.Reader, 2048)
+			server.set(func(s *jwksServer) { s.keys = append(s.keys, rsaJWK("rsa-2", &rotated.PublicKey)) })
+			hits := atomic.LoadInt32(&server.hits)
+
+			// The shared client fetched just now, so the miss does not reach the IdP
+			_, err = jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodRS256, rotated, "rsa-2"), server.URL)
+			So(err, ShouldNotBeNil)
+			So(atomic.LoadInt32(&server.hits), ShouldEqual, hits)
+
+			client := jwt.NewJWKSClient(server.URL)
+			defer client.Close()
+
+			_, err = client.Key(context.Background(), "rsa-2")
+			So(err, ShouldBeNil)
+			So(atomic.LoadInt32(&server.hits), ShouldEqual, hits+1)
+
+			_, err = client.Key(context.Background(), "missing")
+			So(err, ShouldNotBeNil)
+			So(atomic.LoadInt32(&server.hits), ShouldEqual, hits+1)
+		})
+
+		Convey("EC and OKP signed tokens", func() {
+			ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+			mixed := newJWKSServer(
+				jwt.JWK{Kid: "ec-1", Kty: "EC", Crv: "P-256", X: b64(ecKey.X.Bytes()), Y: b64(ecKey.Y.Bytes())},
+				jwt.JWK{Kid: "ed-1", Kty: "OKP", Crv: "Ed25519", X: b64(edPub)},
+			)
+			defer mixed.Close()
+			defer jwt.GetJWKSClient(mixed.URL).Close()
+
+			_, err := jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodES256, ecKey, "ec-1"), mixed.URL)
+			So(err, ShouldBeNil)
+			_, err = jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodEdDSA, edKey, "ed-1"), mixed.URL)
+			So(err, ShouldBeNil)
+
+			// Key type must match the algorithm
+			_, err = jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodES256, ecKey, "ed-1"), mixed.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("HMAC tokens are refused", func() {
+			_, err := jwt.GetJWTClaimsWithURL(sign(gojwt.SigningMethodHS256, []byte("secret"), "rsa-1"), server.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Token without exp is refused", func() {
+			token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, gojwt.MapClaims{"sub": "subject"})
+			token.Header["kid"] = "rsa-1"
+			signed, _ := token.SignedString(rsaKey)
+
+			valid, err := jwt.ValidateJWTWithURL(signed, server.URL)
+			So(err, ShouldNotBeNil)
+			So(valid, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package password_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/crypto/bcrypt"
+
+	"prabogo/utils/password"
+)
+
+func TestHasher(t *testing.T) {
+	Convey("Test Password Hashing", t, func() {
+		// Small parameters keep the test fast
+		argon := password.NewArgon2idHasher(password.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1})
+
+		Convey("Argon2id round trip", func() {
+			hash, err := argon.Hash("correct7horse")
+			So(err, ShouldBeNil)
+			So(hash, ShouldStartWith, "$argon2id$v=19$m=1024,t=1,p=1$")
+			So(argon.Verify("correct7horse", hash), ShouldBeTrue)
+			So(argon.Verify("wrong", hash), ShouldBeFalse)
+			So(argon.NeedsRehash(hash), ShouldBeFalse)
+
+			Convey("Changed parameters need a rehash", func() {
+				stronger := password.NewArgon2idHasher(password.Argon2idParams{Memory: 2048, Iterations: 1, Parallelism: 1})
+				So(stronger.NeedsRehash(hash), ShouldBeTrue)
+			})
+		})
+
+		Convey("Bcrypt needs a rehash below the configured cost", func() {
+			hash, err := password.NewBcryptHasher(bcrypt.MinCost).Hash("correct7horse")
+			So(err, ShouldBeNil)
+			So(password.NewBcryptHasher(bcrypt.MinCost).NeedsRehash(hash), ShouldBeFalse)
+			So(password.NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(hash), ShouldBeTrue)
+			So(argon.NeedsRehash(hash), ShouldBeTrue)
+		})
+
+		Convey("CheckPassword dispatches on the hash prefix", func() {
+			t.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
+			t.Setenv("PASSWORD_ARGON2_MEMORY_KB", "1024")
+			t.Setenv("PASSWORD_ARGON2_ITERATIONS", "1")
+
+			bcryptHash, _ := password.NewBcryptHasher(bcrypt.MinCost).Hash("correct7horse")
+			argonHash, err := password.HashPassword("correct7horse")
+			So(err, ShouldBeNil)
+			So(argonHash, ShouldStartWith, "$argon2id$")
+
+			So(password.CheckPassword("correct7horse", bcryptHash), ShouldBeTrue)
+			So(password.CheckPassword("correct7horse", argonHash), ShouldBeTrue)
+			So(password.CheckPassword("correct7horse", "plain"), ShouldBeFalse)
+
+			So(password.NeedsRehash(bcryptHash), ShouldBeTrue)
+			So(password.NeedsRehash(argonHash), ShouldBeFalse)
+		})
+	})
+}
@@ -1,17 +1,34 @@
 package password
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"crypto/sha256"
+	"encoding/hex"
 )
 
-// HashPassword hashes a plain text password
+// HashPassword hashes a plain text password with the configured algorithm
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+	return CurrentHasher().Hash(password)
 }
 
-// CheckPassword compares a hashed password with a plain text password
+// CheckPassword compares a hashed password with a plain text password, whichever supported
+// algorithm produced the hash
 func CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
-}
\ No newline at end of file
+	hasher := hasherFor(hash)
+	if hasher == nil {
+		return false
+	}
+	return hasher.Verify(password, hash)
+}
+
+// NeedsRehash reports whether hash should be replaced by one from the configured algorithm
+// and settings. Only call it after CheckPassword succeeded, the plain password is needed.
+func NeedsRehash(hash string) bool {
+	return CurrentHasher().NeedsRehash(hash)
+}
+
+// HashToken hashes a high-entropy random secret (recovery codes, API tokens) for lookup.
+// Not suitable for user chosen passwords.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,273 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Rule names reported in a Violation
+const (
+	RuleMinLength    = "min_length"
+	RuleMaxLength    = "max_length"
+	RuleLetter       = "letter"
+	RuleUppercase    = "uppercase"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+)
+
+// bcrypt only looks at the first 72 bytes
+const maxLength = 72
+
+// Violation is one policy rule a password failed
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PolicyError lists every rule a password failed. Domains return it unwrapped, so adapters
+// find it with errors.As and can list the violations
+type PolicyError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return "password does not meet policy: " + strings.Join(messages, "; ")
+}
+
+// Policy is the set of rules a new password has to satisfy
+type Policy struct {
+	MinLength     int
+	RequireLetter bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Breached      *BreachedList
+}
+
+// LoadPolicy builds the policy from PASSWORD_* environment variables
+func LoadPolicy() (*Policy, error) {
+	minLength, _ := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH"))
+	if minLength <= 0 {
+		minLength = 8
+	}
+
+	policy := &Policy{
+		MinLength:     minLength,
+		RequireLetter: envBool("PASSWORD_REQUIRE_LETTER", true),
+		RequireUpper:  envBool("PASSWORD_REQUIRE_UPPER", false),
+		RequireDigit:  envBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol: envBool("PASSWORD_REQUIRE_SYMBOL", false),
+	}
+
+	if path := os.Getenv("PASSWORD_BREACHED_LIST"); path != "" {
+		list, err := LoadBreachedList(path)
+		if err != nil {
+			return nil, err
+		}
+		policy.Breached = list
+	}
+
+	return policy, nil
+}
+
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Validate checks pass against every rule. personal holds values the password must not
+// contain, such as the user's name and email.
+func (p *Policy) Validate(pass string, personal ...string) error {
+	violations := []Violation{}
+	add := func(rule, message string) {
+		violations = append(violations, Violation{Rule: rule, Message: message})
+	}
+
+	if len([]rune(pass)) < p.MinLength {
+		add(RuleMinLength, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if len(pass) > maxLength {
+		add(RuleMaxLength, fmt.Sprintf("must be at most %d bytes", maxLength))
+	}
+
+	var hasLetter, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range pass {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+			if unicode.IsUpper(r) {
+				hasUpper = true
+			}
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireLetter && !hasLetter {
+		add(RuleLetter, "must contain a letter")
+	}
+	if p.RequireUpper && !hasUpper {
+		add(RuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(RuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(RuleSymbol, "must contain a symbol")
+	}
+
+	if containsPersonalInfo(pass, personal) {
+		add(RulePersonalInfo, "must not contain your name or email")
+	}
+
+	if p.Breached != nil && p.Breached.Contains(pass) {
+		add(RuleBreached, "appears in a list of breached passwords")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// containsPersonalInfo matches the whole value, the local part of an email and each word
+// of a name; fragments shorter than 3 characters are ignored
+func containsPersonalInfo(pass string, personal []string) bool {
+	lowered := strings.ToLower(pass)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		fragments := []string{value}
+		if at := strings.Index(value, "@"); at > 0 {
+			fragments = append(fragments, value[:at])
+		}
+		fragments = append(fragments, strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+
+		for _, fragment := range fragments {
+			if len([]rune(fragment)) >= 3 && strings.Contains(lowered, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var (
+	defaultPolicyMu     sync.Mutex
+	defaultPolicy       *Policy
+	defaultPolicyConfig string
+)
+
+// ValidatePassword checks pass against the policy from the environment
+func ValidatePassword(pass string, personal ...string) error {
+	policy, err := currentPolicy()
+	if err != nil {
+		return err
+	}
+	return policy.Validate(pass, personal...)
+}
+
+// currentPolicy reloads only when the PASSWORD_* settings change
+func currentPolicy() (*Policy, error) {
+	config := strings.Join([]string{
+		os.Getenv("PASSWORD_MIN_LENGTH"),
+		os.Getenv("PASSWORD_REQUIRE_LETTER"),
+		os.Getenv("PASSWORD_REQUIRE_UPPER"),
+		os.Getenv("PASSWORD_REQUIRE_DIGIT"),
+		os.Getenv("PASSWORD_REQUIRE_SYMBOL"),
+		os.Getenv("PASSWORD_BREACHED_LIST"),
+	}, "|")
+
+	defaultPolicyMu.Lock()
+	defer defaultPolicyMu.Unlock()
+
+	if defaultPolicy != nil && defaultPolicyConfig == config {
+		return defaultPolicy, nil
+	}
+
+	policy, err := LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	defaultPolicy = policy
+	defaultPolicyConfig = config
+	return policy, nil
+}
+
+// BreachedList holds SHA-1 hashes of breached passwords, indexed by their 5 character prefix
+type BreachedList struct {
+	byPrefix map[string][]string
+}
+
+// LoadBreachedList reads one hex SHA-1 hash per line, optionally followed by ":count" as in
+// the Pwned Passwords dumps. Truncated hashes (at least 10 characters) are accepted to keep
+// the file small, at the cost of rare false positives.
+func LoadBreachedList(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	list := &BreachedList{byPrefix: map[string][]string{}}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if i := strings.Index(entry, ":"); i >= 0 {
+			entry = entry[:i]
+		}
+		entry = strings.ToUpper(entry)
+
+		if len(entry) < 10 || len(entry) > sha1.Size*2 {
+			return nil, fmt.Errorf("breached password list line %d: invalid hash length", line)
+		}
+		if _, err := hex.DecodeString(entry[:len(entry)/2*2]); err != nil {
+			return nil, fmt.Errorf("breached password list line %d: not a hex hash", line)
+		}
+
+		list.byPrefix[entry[:5]] = append(list.byPrefix[entry[:5]], entry[5:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return list, nil
+}
+
+// Contains reports whether the SHA-1 of pass matches a listed hash
+func (l *BreachedList) Contains(pass string) bool {
+	sum := sha1.Sum([]byte(pass))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	for _, suffix := range l.byPrefix[hash[:5]] {
+		if strings.HasPrefix(hash[5:], suffix) {
+			return true
+		}
+	}
+	return false
+}
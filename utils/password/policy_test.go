@@ -0,0 +1,108 @@
+package password_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/password"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func rules(err error) []string {
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+	names := []string{}
+	for _, v := range policyErr.Violations {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	Convey("Test Password Policy", t, func() {
+		policy := &password.Policy{MinLength: 8, RequireLetter: true, RequireDigit: true}
+
+		Convey("Accepts a password meeting every rule", func() {
+			So(policy.Validate("correct7horse", "Test User", "test@example.com"), ShouldBeNil)
+		})
+
+		Convey("Reports every failed rule at once", func() {
+			err := policy.Validate("")
+			So(err, ShouldNotBeNil)
+			So(rules(err), ShouldResemble, []string{password.RuleMinLength, password.RuleLetter, password.RuleDigit})
+		})
+
+		Convey("Enforces optional character classes", func() {
+			policy.RequireUpper = true
+			policy.RequireSymbol = true
+			So(rules(policy.Validate("correct7horse")), ShouldResemble, []string{password.RuleUppercase, password.RuleSymbol})
+			So(policy.Validate("Correct7horse!"), ShouldBeNil)
+		})
+
+		Convey("Rejects passwords longer than bcrypt accepts", func() {
+			So(rules(policy.Validate(strings.Repeat("a1", 37))), ShouldResemble, []string{password.RuleMaxLength})
+		})
+
+		Convey("Rejects passwords containing the name or email", func() {
+			So(rules(policy.Validate("johnny2024", "Johnny Appleseed", "")), ShouldResemble, []string{password.RulePersonalInfo})
+			So(rules(policy.Validate("1jsmith99x", "", "JSmith@example.com")), ShouldResemble, []string{password.RulePersonalInfo})
+			// Short fragments are too common to reject
+			So(policy.Validate("bo2ardvark", "Bo Li", "bo@example.com"), ShouldBeNil)
+		})
+
+		Convey("Breached list", func() {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "breached.txt")
+			content := "# pwned sample\n" +
+				sha1Hex("password1") + ":2413945\n" +
+				strings.ToLower(sha1Hex("letmein123")[:12]) + "\n"
+			So(os.WriteFile(path, []byte(content), 0600), ShouldBeNil)
+
+			list, err := password.LoadBreachedList(path)
+			So(err, ShouldBeNil)
+
+			Convey("Matches full hashes and hash prefixes", func() {
+				So(list.Contains("password1"), ShouldBeTrue)
+				So(list.Contains("letmein123"), ShouldBeTrue)
+				So(list.Contains("correct7horse"), ShouldBeFalse)
+			})
+
+			Convey("Is reported as a policy violation", func() {
+				policy.Breached = list
+				So(rules(policy.Validate("password1")), ShouldResemble, []string{password.RuleBreached})
+			})
+
+			Convey("ValidatePassword loads it from the environment", func() {
+				t.Setenv("PASSWORD_BREACHED_LIST", path)
+				So(rules(password.ValidatePassword("password1")), ShouldResemble, []string{password.RuleBreached})
+				So(password.ValidatePassword("correct7horse"), ShouldBeNil)
+			})
+
+			Convey("Rejects malformed lines", func() {
+				bad := filepath.Join(dir, "bad.txt")
+				So(os.WriteFile(bad, []byte("not-a-hash\n"), 0600), ShouldBeNil)
+				_, err := password.LoadBreachedList(bad)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("ValidatePassword reads the policy from the environment", func() {
+			t.Setenv("PASSWORD_MIN_LENGTH", "12")
+			t.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+			So(rules(password.ValidatePassword("correct7h")), ShouldResemble, []string{password.RuleMinLength, password.RuleSymbol})
+		})
+	})
+}
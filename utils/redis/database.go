@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"os"
+	"time"
 
 	redis "github.com/redis/go-redis/v9"
 )
@@ -33,3 +34,45 @@ func Get(ctx context.Context, key string) (string, error) {
 func Del(ctx context.Context, key string) error {
 	return dbClient.Del(ctx, key).Err()
 }
+
+func SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return dbClient.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX sets key only when it is missing and reports whether it did
+func SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return dbClient.SetNX(ctx, key, value, ttl).Result()
+}
+
+func Exists(ctx context.Context, keys ...string) (int64, error) {
+	return dbClient.Exists(ctx, keys...).Result()
+}
+
+// IncrWithTTL increments a counter, starting its ttl when the key is created
+func IncrWithTTL(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	count, err := dbClient.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := dbClient.Expire(ctx, key, ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// TTL returns the time left on a key; negative when the key is missing or persistent
+func TTL(ctx context.Context, key string) (time.Duration, error) {
+	return dbClient.TTL(ctx, key).Result()
+}
+
+// ScanKeys lists every key matching pattern without blocking the server like KEYS does
+func ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	keys := []string{}
+	iter := dbClient.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
@@ -0,0 +1,94 @@
+package signature_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/signature"
+)
+
+func TestSignature(t *testing.T) {
+	Convey("Test Request Signing", t, func() {
+		now := time.Now()
+		request := signature.Request{
+			Method:    "POST",
+			Path:      "/v1/internal/clients/find?limit=1",
+			Body:      []byte(`{"ids":[1]}`),
+			Timestamp: strconv.FormatInt(now.Unix(), 10),
+			Nonce:     "3f2a9c",
+		}
+		sig := request.Signature("secret")
+
+		Convey("Accepts a matching signature", func() {
+			So(signature.Verify("secret", request, sig, now, time.Minute), ShouldBeNil)
+		})
+
+		Convey("Rejects a different key", func() {
+			So(signature.Verify("other", request, sig, now, time.Minute), ShouldEqual, signature.ErrInvalid)
+		})
+
+		Convey("Covers method, path, body and nonce", func() {
+			tampered := []signature.Request{request, request, request, request}
+			tampered[0].Method = "DELETE"
+			tampered[1].Path = "/v1/internal/clients/find?limit=2"
+			tampered[2].Body = []byte(`{"ids":[2]}`)
+			tampered[3].Nonce = "3f2a9d"
+			for _, r := range tampered {
+				So(signature.Verify("secret", r, sig, now, time.Minute), ShouldEqual, signature.ErrInvalid)
+			}
+		})
+
+		Convey("Rejects timestamps outside the window", func() {
+			So(signature.Verify("secret", request, sig, now.Add(2*time.Minute), time.Minute), ShouldEqual, signature.ErrExpired)
+			So(signature.Verify("secret", request, sig, now.Add(-2*time.Minute), time.Minute), ShouldEqual, signature.ErrExpired)
+		})
+
+		Convey("Rejects missing parts", func() {
+			So(signature.Verify("secret", request, "", now, time.Minute), ShouldEqual, signature.ErrMissing)
+			request.Nonce = ""
+			So(signature.Verify("secret", request, sig, now, time.Minute), ShouldEqual, signature.ErrMissing)
+		})
+
+		Convey("SignRequest signs an outgoing request and keeps its body", func() {
+			var received signature.Request
+			var receivedSig string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = signature.Request{
+					Method:    r.Method,
+					Path:      r.URL.RequestURI(),
+					Body:      body,
+					Timestamp: r.Header.Get(signature.HeaderTimestamp),
+					Nonce:     r.Header.Get(signature.HeaderNonce),
+				}
+				receivedSig = strings.TrimPrefix(r.Header.Get("Authorization"), signature.Scheme+" ")
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/internal/clients/upsert?dry=1", strings.NewReader(`[{"name":"svc"}]`))
+			So(err, ShouldBeNil)
+			So(signature.SignRequest(req, "secret"), ShouldBeNil)
+
+			resp, err := http.DefaultClient.Do(req)
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+
+			So(string(received.Body), ShouldEqual, `[{"name":"svc"}]`)
+			So(received.Nonce, ShouldNotBeEmpty)
+			So(signature.Verify("secret", received, receivedSig, time.Now(), time.Minute), ShouldBeNil)
+		})
+
+		Convey("MaxSkew reads the environment", func() {
+			So(signature.MaxSkew(), ShouldEqual, signature.DefaultMaxSkew)
+			t.Setenv("INTERNAL_SIGNATURE_MAX_SKEW_SECONDS", "30")
+			So(signature.MaxSkew(), ShouldEqual, 30*time.Second)
+		})
+	})
+}
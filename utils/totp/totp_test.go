@@ -0,0 +1,74 @@
+package totp_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/totp"
+)
+
+func TestTotp(t *testing.T) {
+	Convey("Test TOTP", t, func() {
+		// RFC 6238 appendix B seed "12345678901234567890", truncated to 6 digits
+		secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+		Convey("GenerateCode matches the RFC 6238 vectors", func() {
+			vectors := map[int64]string{
+				59:          "287082",
+				1111111109:  "081804",
+				1111111111:  "050471",
+				1234567890:  "005924",
+				2000000000:  "279037",
+				20000000000: "353130",
+			}
+			for ts, expected := range vectors {
+				code, err := totp.GenerateCode(secret, time.Unix(ts, 0))
+				So(err, ShouldBeNil)
+				So(code, ShouldEqual, expected)
+			}
+		})
+
+		Convey("Validate", func() {
+			now := time.Unix(1234567890, 0)
+
+			Convey("Accepts the current and adjacent steps", func() {
+				step, ok := totp.Validate("005924", secret, now)
+				So(ok, ShouldBeTrue)
+				So(step, ShouldEqual, 1234567890/totp.Period)
+
+				previous, _ := totp.GenerateCode(secret, now.Add(-totp.Period*time.Second))
+				_, ok = totp.Validate(previous, secret, now)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Rejects codes outside the window", func() {
+				old, _ := totp.GenerateCode(secret, now.Add(-3*totp.Period*time.Second))
+				_, ok := totp.Validate(old, secret, now)
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Rejects malformed codes", func() {
+				_, ok := totp.Validate("12345", secret, now)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("GenerateSecret produces a usable secret", func() {
+			generated, err := totp.GenerateSecret()
+			So(err, ShouldBeNil)
+
+			code, err := totp.GenerateCode(generated, time.Now())
+			So(err, ShouldBeNil)
+			_, ok := totp.Validate(code, generated, time.Now())
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("ProvisioningURI", func() {
+			uri := totp.ProvisioningURI("Prabogo", "test@example.com", secret)
+			So(uri, ShouldStartWith, "otpauth://totp/Prabogo:test@example.com?")
+			So(uri, ShouldContainSubstring, "secret="+secret)
+		})
+	})
+}